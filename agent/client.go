@@ -0,0 +1,195 @@
+package agent
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+)
+
+// Client talks to an agent Server over its Unix socket.
+type Client struct {
+	path string
+
+	mu      sync.Mutex
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+// Dial creates a Client for the agent listening on the Unix socket at path. No connection is made until it's used.
+func Dial(path string) *Client {
+	return &Client{path: path}
+}
+
+// FromEnv creates a Client for the agent named by the TRUSTDRAW_AGENT_SOCK environment variable, if it is set.
+func FromEnv() (*Client, bool) {
+	path := os.Getenv(SocketEnv)
+	if path == "" {
+		return nil, false
+	}
+	return Dial(path), true
+}
+
+// Close closes the connection to the agent, if one was made.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// Keys lists the keys the agent holds.
+func (c *Client) Keys() ([]KeyInfo, error) {
+	res, err := c.do(Request{Op: OpList})
+	return res.Keys, err
+}
+
+// Add gives the agent a private key to hold, returning its fingerprint.
+func (c *Client) Add(prv crypto.Signer, comment string) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(prv)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.do(Request{
+		Op:         OpAdd,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		Comment:    comment,
+	})
+	return res.Fingerprint, err
+}
+
+// Remove makes the agent forget the key with the given fingerprint.
+func (c *Client) Remove(fingerprint string) error {
+	_, err := c.do(Request{Op: OpRemove, Fingerprint: fingerprint})
+	return err
+}
+
+// RemoveAll makes the agent forget every key it holds.
+func (c *Client) RemoveAll() error {
+	_, err := c.do(Request{Op: OpRemoveAll})
+	return err
+}
+
+// Key returns the agent's private key for the given public key, which can decrypt and sign like a local key.
+func (c *Client) Key(pub crypto.PublicKey) (*Key, error) {
+	fingerprint, err := Fingerprint(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := c.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if key.Fingerprint == fingerprint {
+			return &Key{client: c, fingerprint: fingerprint, pub: pub}, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Key is a private key held by an agent. It is a crypto.Signer, and for RSA keys a crypto.Decrypter too.
+// Ed25519 keys can agree secrets with X25519 public keys instead, with ECDH.
+type Key struct {
+	client      *Client
+	fingerprint string
+	pub         crypto.PublicKey
+}
+
+// Fingerprint identifies the key, as Fingerprint does.
+func (k *Key) Fingerprint() string {
+	return k.fingerprint
+}
+
+func (k *Key) Public() crypto.PublicKey {
+	return k.pub
+}
+
+// Sign signs the digest with the agent's key. opts can be a crypto.Hash, *rsa.PSSOptions, or crypto.Hash(0) to
+// sign a whole message with an Ed25519 key.
+func (k *Key) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	req := Request{Op: OpSign, Fingerprint: k.fingerprint, Data: digest}
+	if hash := opts.HashFunc(); hash != 0 {
+		req.Hash = hash.String()
+	}
+	if pss, ok := opts.(*rsa.PSSOptions); ok {
+		req.PSS = true
+		req.SaltLength = pss.SaltLength
+	}
+
+	res, err := k.client.do(req)
+	return res.Data, err
+}
+
+// Decrypt decrypts RSA-OAEP ciphertext with the agent's key; opts must be *rsa.OAEPOptions.
+func (k *Key) Decrypt(_ io.Reader, ciphertext []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	oaep, ok := opts.(*rsa.OAEPOptions)
+	if !ok {
+		return nil, fmt.Errorf("the agent can only decrypt with RSA-OAEP")
+	}
+
+	res, err := k.client.do(Request{
+		Op:          OpDecrypt,
+		Fingerprint: k.fingerprint,
+		Data:        ciphertext,
+		Hash:        oaep.Hash.String(),
+		Label:       oaep.Label,
+	})
+	return res.Data, err
+}
+
+// ECDH agrees a shared secret between the agent's Ed25519 key (as an X25519 key) and the given X25519 public key,
+// as *ecdh.PrivateKey's ECDH method does.
+func (k *Key) ECDH(remote *ecdh.PublicKey) ([]byte, error) {
+	res, err := k.client.do(Request{Op: OpECDH, Fingerprint: k.fingerprint, Data: remote.Bytes()})
+	return res.Data, err
+}
+
+// do sends a request over the (lazily made) connection to the agent, and reads its response.
+func (c *Client) do(req Request) (Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := net.Dial("unix", c.path)
+		if err != nil {
+			return Response{}, fmt.Errorf("could not reach the agent: %w", err)
+		}
+		c.conn = conn
+		c.decoder = json.NewDecoder(conn)
+	}
+
+	var res Response
+	err := json.NewEncoder(c.conn).Encode(req)
+	if err == nil {
+		err = c.decoder.Decode(&res)
+	}
+	if err != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+		return Response{}, fmt.Errorf("could not talk to the agent: %w", err)
+	}
+
+	switch {
+	case res.Error == ErrKeyNotFound.Error():
+		return res, ErrKeyNotFound
+	case res.Error != "":
+		return res, errors.New(res.Error)
+	}
+	return res, nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package agent
+
+import "net"
+
+// listenPrivate makes a Unix socket at path. Only Unix systems have a umask to make it private from the start, so
+// elsewhere it relies on Listen's chmod, and the directory it's in.
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package agent
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivate makes a Unix socket at path with no permissions for anyone but its owner, from the moment it's made,
+// so nobody else can connect to it before it's chmodded.
+func listenPrivate(path string) (net.Listener, error) {
+	// The umask is process-wide, so this is only safe while nothing else is creating files, like at startup.
+	previous := syscall.Umask(0177)
+	defer syscall.Umask(previous)
+	return net.Listen("unix", path)
+}
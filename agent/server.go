@@ -0,0 +1,363 @@
+// Package agent holds decrypted player and dealer keys in memory and uses them on request, like ssh-agent,
+// so other processes can decrypt and sign without the keys ever sitting on disk in the clear.
+//
+// The agent listens on a Unix socket, whose path is given to other processes in the TRUSTDRAW_AGENT_SOCK
+// environment variable. Clients send a stream of JSON encoded Requests, and get a Response to each in turn.
+package agent
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/x25519"
+)
+
+// SocketEnv is the environment variable holding the path of the running agent's socket.
+const SocketEnv = "TRUSTDRAW_AGENT_SOCK"
+
+// maxRequestSize limits the size of requests the agent will accept.
+const maxRequestSize = 1 << 20
+
+var ErrKeyNotFound = errors.New("the agent doesn't hold that key")
+
+// The operations a Request can ask for.
+const (
+	// OpList lists the keys held, in Response.Keys.
+	OpList = "list"
+	// OpAdd holds Request.PrivateKey (a PEM encoded, unencrypted PKCS #8 key), returning its Response.Fingerprint.
+	OpAdd = "add"
+	// OpRemove forgets the key with Request.Fingerprint.
+	OpRemove = "remove"
+	// OpRemoveAll forgets every key.
+	OpRemoveAll = "removeAll"
+	// OpDecrypt decrypts Request.Data (RSA-OAEP ciphertext) with an RSA key, into Response.Data.
+	OpDecrypt = "decrypt"
+	// OpSign signs Request.Data (a digest, or for Ed25519 keys a whole message), into Response.Data.
+	OpSign = "sign"
+	// OpECDH agrees a shared secret between an Ed25519 key (as an X25519 key) and the X25519 public key in
+	// Request.Data, into Response.Data.
+	OpECDH = "ecdh"
+)
+
+// Request asks the agent to perform one operation.
+type Request struct {
+	Op          string `json:"op"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	PrivateKey string `json:"privateKey,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+
+	Data []byte `json:"data,omitempty"`
+	// Hash is the name of the OAEP hash, or the hash that made a digest to sign, eg. "SHA-256".
+	// It is empty for Ed25519 signatures.
+	Hash  string `json:"hash,omitempty"`
+	Label []byte `json:"label,omitempty"`
+	// PSS asks for an RSA-PSS signature, rather than PKCS #1 v1.5.
+	PSS        bool `json:"pss,omitempty"`
+	SaltLength int  `json:"saltLength,omitempty"`
+}
+
+// Response is the agent's answer to a Request.
+type Response struct {
+	Error       string    `json:"error,omitempty"`
+	Keys        []KeyInfo `json:"keys,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Data        []byte    `json:"data,omitempty"`
+}
+
+// KeyInfo describes a key the agent holds.
+type KeyInfo struct {
+	Fingerprint string `json:"fingerprint"`
+	// Type is "RSA" or "Ed25519".
+	Type    string `json:"type"`
+	Comment string `json:"comment,omitempty"`
+	// PublicKey is the PEM encoded public key.
+	PublicKey string `json:"publicKey"`
+}
+
+// Server holds private keys and uses them on request.
+// It must only be served on a socket that nobody else can connect to, as made by Listen.
+type Server struct {
+	mu   sync.Mutex
+	keys map[string]heldKey
+}
+
+type heldKey struct {
+	signer  crypto.Signer
+	comment string
+}
+
+// NewServer creates an agent Server holding no keys.
+func NewServer() *Server {
+	return &Server{keys: make(map[string]heldKey)}
+}
+
+// Fingerprint identifies a public key, as trustdraw.Fingerprint does.
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	return trustdraw.Fingerprint(pub)
+}
+
+// Add holds the given key, which must be an *rsa.PrivateKey or an ed25519.PrivateKey.
+func (s *Server) Add(prv crypto.Signer, comment string) (string, error) {
+	switch prv.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return "", fmt.Errorf("only RSA and Ed25519 keys can be held")
+	}
+
+	fingerprint, err := Fingerprint(prv.Public())
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[fingerprint] = heldKey{signer: prv, comment: comment}
+	return fingerprint, nil
+}
+
+// Serve answers requests from every connection made to the listener, until it is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	// One decoder reads the whole connection, as it can read ahead into the next request.
+	limit := &requestLimit{r: conn}
+	decoder := json.NewDecoder(limit)
+	encoder := json.NewEncoder(conn)
+	for {
+		limit.left = maxRequestSize
+		var req Request
+		if err := decoder.Decode(&req); err != nil {
+			if !errors.Is(err, io.EOF) {
+				_ = encoder.Encode(Response{Error: fmt.Sprintf("invalid request: %v", err)})
+			}
+			return
+		}
+
+		if err := encoder.Encode(s.Handle(req)); err != nil {
+			log.Printf("unable to write response: %v", err)
+			return
+		}
+	}
+}
+
+// requestLimit stops a connection from sending more than maxRequestSize bytes while one request is being read.
+type requestLimit struct {
+	r    io.Reader
+	left int
+}
+
+func (l *requestLimit) Read(p []byte) (int, error) {
+	if l.left <= 0 {
+		return 0, fmt.Errorf("request is larger than %d bytes", maxRequestSize)
+	}
+	if len(p) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= n
+	return n, err
+}
+
+// Handle performs one request.
+func (s *Server) Handle(req Request) Response {
+	switch req.Op {
+	case OpList:
+		return Response{Keys: s.list()}
+	case OpAdd:
+		return s.add(req)
+	case OpRemoveAll:
+		s.mu.Lock()
+		s.keys = make(map[string]heldKey)
+		s.mu.Unlock()
+		return Response{}
+	}
+
+	s.mu.Lock()
+	key, ok := s.keys[req.Fingerprint]
+	s.mu.Unlock()
+	if !ok {
+		return Response{Error: ErrKeyNotFound.Error()}
+	}
+
+	switch req.Op {
+	case OpRemove:
+		s.mu.Lock()
+		delete(s.keys, req.Fingerprint)
+		s.mu.Unlock()
+		return Response{}
+	case OpDecrypt:
+		return decrypt(req, key)
+	case OpSign:
+		return sign(req, key)
+	case OpECDH:
+		return agree(req, key)
+	default:
+		return Response{Error: fmt.Sprintf("unknown operation: %s", req.Op)}
+	}
+}
+
+func (s *Server) list() []KeyInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]KeyInfo, 0, len(s.keys))
+	for fingerprint, key := range s.keys {
+		info := KeyInfo{Fingerprint: fingerprint, Comment: key.comment, Type: "RSA"}
+		if _, ok := key.signer.(ed25519.PrivateKey); ok {
+			info.Type = "Ed25519"
+		}
+
+		der, err := x509.MarshalPKIXPublicKey(key.signer.Public())
+		if err != nil {
+			continue
+		}
+		info.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Fingerprint < infos[j].Fingerprint })
+	return infos
+}
+
+func (s *Server) add(req Request) Response {
+	pemBlock, _ := pem.Decode([]byte(req.PrivateKey))
+	if pemBlock == nil || pemBlock.Type != "PRIVATE KEY" {
+		return Response{Error: "the private key must be a PEM encoded PKCS #8 key"}
+	}
+	key, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("invalid private key: %v", err)}
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return Response{Error: "only RSA and Ed25519 keys can be held"}
+	}
+
+	fingerprint, err := s.Add(signer, req.Comment)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	return Response{Fingerprint: fingerprint}
+}
+
+func decrypt(req Request, key heldKey) Response {
+	rsaKey, ok := key.signer.(*rsa.PrivateKey)
+	if !ok {
+		return Response{Error: "only RSA keys can decrypt"}
+	}
+	hash, ok := hashNamed(req.Hash)
+	if !ok || hash == 0 {
+		return Response{Error: fmt.Sprintf("unsupported hash: %s", req.Hash)}
+	}
+
+	plaintext, err := rsaKey.Decrypt(crand.Reader, req.Data, &rsa.OAEPOptions{Hash: hash, Label: req.Label})
+	if err != nil {
+		return Response{Error: "decryption error"}
+	}
+	return Response{Data: plaintext}
+}
+
+func sign(req Request, key heldKey) Response {
+	hash, ok := hashNamed(req.Hash)
+	if !ok {
+		return Response{Error: fmt.Sprintf("unsupported hash: %s", req.Hash)}
+	}
+
+	var opts crypto.SignerOpts = hash
+	if req.PSS {
+		opts = &rsa.PSSOptions{Hash: hash, SaltLength: req.SaltLength}
+	}
+
+	signature, err := key.signer.Sign(crand.Reader, req.Data, opts)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("could not sign: %v", err)}
+	}
+	return Response{Data: signature}
+}
+
+func agree(req Request, key heldKey) Response {
+	edKey, ok := key.signer.(ed25519.PrivateKey)
+	if !ok {
+		return Response{Error: "only Ed25519 keys can agree secrets"}
+	}
+	prv, err := x25519.PrivateKey(edKey)
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	remote, err := ecdh.X25519().NewPublicKey(req.Data)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("invalid X25519 public key: %v", err)}
+	}
+
+	shared, err := prv.ECDH(remote)
+	if err != nil {
+		return Response{Error: fmt.Sprintf("could not agree a secret: %v", err)}
+	}
+	return Response{Data: shared}
+}
+
+// Listen makes a Unix socket at path that only the current user can connect to, replacing any stale socket.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("an agent is already listening on %s", path)
+		}
+		_ = os.Remove(path)
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// hashes are the hashes that can be named in requests.
+var hashes = []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512}
+
+func hashNamed(name string) (crypto.Hash, bool) {
+	if name == "" {
+		return 0, true
+	}
+	for _, hash := range hashes {
+		if hash.String() == name {
+			return hash, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,224 @@
+package trustdraw
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+)
+
+// Cards can leave the deck, or a player's hand, without anyone seeing them: burnt from the deck, like in poker, or
+// discarded face-down from a hand. The player burning or discarding them shares a burn or discard (see Game.Burn and
+// Game.DiscardHidden), which every other player checks and applies to their own state, so the cards can't be drawn or
+// played afterwards. Once the game is over, the players can share their allowKeys for these cards (see Game.AuditKeys)
+// to check what they were (see Game.Audit).
+
+const (
+	// burnt is the state of cards that were burnt from the deck, unseen.
+	burnt PlayerNumber = 254
+	// discarded is the state of cards that were discarded face-down from a player's hand.
+	discarded PlayerNumber = 253
+)
+
+// AuditedCard is a burnt or discarded card, revealed once the game is over.
+type AuditedCard struct {
+	CardID int
+	Card   string
+	// Burnt is true if the card was burnt from the deck, and false if it was discarded from a hand.
+	Burnt bool
+}
+
+// Burn records the given cards, which must still be in the deck, as burnt, returning the burn to share with the other
+// players, who apply it with ApplyBurn. The cards are usually the next to be drawn (see Game.Top).
+func (g *Game) Burn(cardIDs ...int) (string, error) {
+	return g.goneToken(burnt, cardIDs)
+}
+
+// DiscardHidden records the given cards, which must be in this player's hand, as discarded without being shown,
+// returning the discard to share with the other players, who apply it with ApplyDiscard.
+func (g *Game) DiscardHidden(cardIDs ...int) (string, error) {
+	return g.goneToken(discarded, cardIDs)
+}
+
+// ApplyBurn checks that the cards in another player's burn are still in the deck, and records them as burnt.
+func (g *Game) ApplyBurn(burn string) error {
+	return g.applyGone(burnt, burn)
+}
+
+// ApplyDiscard checks that the cards in another player's discard are in their hand, and records them as discarded.
+func (g *Game) ApplyDiscard(discard string) error {
+	return g.applyGone(discarded, discard)
+}
+
+// goneToken encodes a burn or discard of the given cards by this player, and applies it to this player's state.
+// A burn or discard is 1 byte of player number, 1 byte of the state the cards go to, then 2 bytes of each card's ID.
+func (g *Game) goneToken(gone PlayerNumber, cardIDs []int) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	if len(cardIDs) == 0 {
+		return "", fmt.Errorf("no cards were given")
+	}
+	token := make([]byte, 2, 2+2*len(cardIDs))
+	token[0] = byte(g.playerNumber)
+	token[1] = byte(gone)
+	for _, cardID := range cardIDs {
+		if cardID < 0 || cardID >= len(g.state) {
+			return "", fmt.Errorf("card %d isn't in this deck", cardID+1)
+		}
+		if owner := g.state[cardID]; owner == burnt || owner == discarded {
+			return "", fmt.Errorf("card %d has already been burnt or discarded", cardID+1)
+		}
+		token = binary.LittleEndian.AppendUint16(token, uint16(cardID))
+	}
+	encoded := base64.RawStdEncoding.EncodeToString(token)
+	if err := g.applyGone(gone, encoded); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
+
+// applyGone checks a burn or discard against this player's state, and records its cards as gone. Cards already
+// recorded as gone the same way are left as they are, so a burn or discard can be applied more than once.
+func (g *Game) applyGone(gone PlayerNumber, token string) error {
+	player, tokenGone, cardIDs, err := parseGone(token)
+	if err != nil {
+		return err
+	}
+	if tokenGone != gone {
+		if gone == burnt {
+			return fmt.Errorf("this is a discard, not a burn")
+		}
+		return fmt.Errorf("this is a burn, not a discard")
+	}
+	if player < 1 || player > PlayerNumber(g.Players) {
+		return fmt.Errorf("player %d is not in this game", player)
+	}
+	if _, departed := g.recovered[player]; departed {
+		return fmt.Errorf("%s has left the game", g.PlayerName(player))
+	}
+
+	oldState := make(map[int]PlayerNumber)
+	for _, cardID := range cardIDs {
+		if cardID >= len(g.state) {
+			return fmt.Errorf("card %d isn't in this deck", cardID+1)
+		}
+		owner := g.state[cardID]
+		// Players seated after the card left the deck, and observers, who don't see draws being allowed, can't know who
+		// holds it, so take the holder's word for it.
+		unknown := owner == notInDeck || (g.role != RolePlayer && owner == 0)
+		switch {
+		case owner == gone:
+			continue
+		case gone == burnt && owner != 0:
+			return fmt.Errorf("card %d isn't in the deck", cardID+1)
+		case gone == discarded && owner != player && !unknown:
+			return fmt.Errorf("card %d isn't in %s's hand", cardID+1, g.PlayerName(player))
+		}
+		if _, seen := oldState[cardID]; !seen {
+			oldState[cardID] = owner
+		}
+		g.state[cardID] = gone
+	}
+
+	if err := g.Save(); err != nil {
+		for cardID, owner := range oldState {
+			g.state[cardID] = owner
+		}
+		return fmt.Errorf("could not save game state: %w", err)
+	}
+	return nil
+}
+
+func parseGone(token string) (PlayerNumber, PlayerNumber, []int, error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(token)
+	if err != nil || len(decoded) < 4 || len(decoded)%2 != 0 {
+		return 0, 0, nil, fmt.Errorf("invalid burn or discard")
+	}
+	gone := PlayerNumber(decoded[1])
+	if gone != burnt && gone != discarded {
+		return 0, 0, nil, fmt.Errorf("invalid burn or discard")
+	}
+	var cardIDs []int
+	for i := 2; i < len(decoded); i += 2 {
+		cardIDs = append(cardIDs, int(binary.LittleEndian.Uint16(decoded[i:i+2])))
+	}
+	return PlayerNumber(decoded[0]), gone, cardIDs, nil
+}
+
+// Burnt lists the IDs of the cards this player has recorded as burnt.
+func (g *Game) Burnt() []int {
+	return g.cardsIn(burnt)
+}
+
+// Discarded lists the IDs of the cards this player has recorded as discarded.
+func (g *Game) Discarded() []int {
+	return g.cardsIn(discarded)
+}
+
+func (g *Game) cardsIn(state PlayerNumber) []int {
+	var cards []int
+	for cardID, owner := range g.state {
+		if owner == state {
+			cards = append(cards, cardID)
+		}
+	}
+	return cards
+}
+
+// AuditKeys returns this player's allowKeys for every card they've recorded as burnt or discarded, to share with the
+// other players once the game is over, so that everyone can see what the cards were.
+func (g *Game) AuditKeys() ([]string, error) {
+	if g.role != RolePlayer {
+		return nil, ErrObserving
+	}
+	var allowKeys []string
+	for cardID, owner := range g.state {
+		if owner == burnt || owner == discarded {
+			allowKeys = append(allowKeys, toAllowKey(cardID, g.keys[cardID]))
+		}
+	}
+	return allowKeys, nil
+}
+
+// Audit reveals the cards this player has recorded as burnt or discarded, using the other players' allowKeys for
+// them (see AuditKeys). Observers need every player's allowKeys.
+func (g *Game) Audit(allowKeys ...string) ([]AuditedCard, error) {
+	byCard := make(map[int][]string)
+	for _, allowKey := range allowKeys {
+		cardID, _, err := fromAllowKey(allowKey)
+		if err != nil {
+			return nil, err
+		}
+		if cardID >= len(g.state) || (g.state[cardID] != burnt && g.state[cardID] != discarded) {
+			return nil, fmt.Errorf("card %d wasn't burnt or discarded", cardID+1)
+		}
+		byCard[cardID] = append(byCard[cardID], allowKey)
+	}
+
+	var audited []AuditedCard
+	for cardID, owner := range g.state {
+		if owner != burnt && owner != discarded {
+			continue
+		}
+		keys := byCard[cardID]
+		if g.role == RolePlayer {
+			// The allowKeys of players who have left the game can be left out, as their recovered key shares are used.
+			if needed := g.Players - 1 - len(g.recovered); len(keys) < needed {
+				return nil, fmt.Errorf("card %d needs %d allowKeys, %d given", cardID+1, needed, len(keys))
+			}
+			keys = g.withRecovered(keys)
+		} else if len(keys) == 0 {
+			return nil, fmt.Errorf("no allowKeys were given for card %d", cardID+1)
+		}
+		_, cardKey, err := g.allowKeysToCardKey(keys)
+		if err != nil {
+			return nil, fmt.Errorf("could not re-create the key for card %d: %w", cardID+1, err)
+		}
+		card, err := g.decryptCard(cardID, cardKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt card %d: %w", cardID+1, err)
+		}
+		audited = append(audited, AuditedCard{CardID: cardID, Card: card, Burnt: owner == burnt})
+	}
+	return audited, nil
+}
@@ -0,0 +1,157 @@
+package trustdraw
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+)
+
+func TestBurnAndDiscard(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	drawn, _, _ := testDraw(t, games, 1)
+	hand, err := games[0].Hand()
+	if err != nil || len(hand) != 1 || hand[0].Card != drawn {
+		t.Fatalf("got hand %v (%v)", hand, err)
+	}
+
+	top := games[0].Top("", 2)
+	burn, err := games[1].Burn(top...)
+	if err != nil {
+		t.Fatalf("could not burn: %v", err)
+	}
+	discard, err := games[0].DiscardHidden(hand[0].CardID)
+	if err != nil {
+		t.Fatalf("could not discard: %v", err)
+	}
+	for _, game := range games {
+		if game.PlayerNumber() != 2 {
+			if err := game.ApplyBurn(burn); err != nil {
+				t.Fatalf("player %d could not apply the burn: %v", game.PlayerNumber(), err)
+			}
+		}
+		if game.PlayerNumber() != 1 {
+			if err := game.ApplyDiscard(discard); err != nil {
+				t.Fatalf("player %d could not apply the discard: %v", game.PlayerNumber(), err)
+			}
+		}
+		// Applying them again changes nothing.
+		if err := game.ApplyBurn(burn); err != nil {
+			t.Errorf("player %d could not apply the burn again: %v", game.PlayerNumber(), err)
+		}
+	}
+
+	for _, game := range games {
+		if got := game.Burnt(); !sameCards(got, top) {
+			t.Errorf("player %d has burnt %v, want %v", game.PlayerNumber(), got, top)
+		}
+		if got := game.Discarded(); len(got) != 1 || got[0] != hand[0].CardID {
+			t.Errorf("player %d has discarded %v, want %d", game.PlayerNumber(), got, hand[0].CardID)
+		}
+		if got := game.Remaining(); got != len(testDeck)-3 {
+			t.Errorf("player %d sees %d cards left, want %d", game.PlayerNumber(), got, len(testDeck)-3)
+		}
+	}
+
+	// Once the game is over, the players' allowKeys reveal the burnt and discarded cards.
+	var auditKeys []string
+	for _, game := range games[1:] {
+		keys, err := game.AuditKeys()
+		if err != nil {
+			t.Fatal(err)
+		}
+		auditKeys = append(auditKeys, keys...)
+	}
+	audited, err := games[0].Audit(auditKeys...)
+	if err != nil || len(audited) != 3 {
+		t.Fatalf("got %d audited cards (%v), want 3", len(audited), err)
+	}
+	for _, card := range audited {
+		if wantBurnt := card.CardID != hand[0].CardID; card.Burnt != wantBurnt || (!wantBurnt && card.Card != drawn) {
+			t.Errorf("got audited card %+v", card)
+		}
+	}
+}
+
+func TestBurnAndDiscardRejections(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	testDraw(t, games, 1)
+	testDraw(t, games, 2)
+	hand1, _ := games[0].Hand()
+	hand2, _ := games[1].Hand()
+	top := games[0].Top("", 1)[0]
+
+	burnOfDrawn, err := games[0].Burn(top)
+	if err != nil {
+		t.Fatal(err)
+	}
+	discardOfOther, err := games[1].DiscardHidden(hand2[0].CardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Player 3 allows player 1's next draw, so the burnt card has left the deck for them.
+	if _, err := games[2].AllowDraw(1); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  func() error
+	}{
+		{"burning a card in a hand", func() error { _, err := games[1].Burn(hand1[0].CardID); return err }},
+		{"burning a card not in the deck", func() error { _, err := games[1].Burn(len(testDeck)); return err }},
+		{"burning nothing", func() error { _, err := games[1].Burn(); return err }},
+		{"burning a burnt card", func() error { _, err := games[0].Burn(top); return err }},
+		{"discarding another player's card", func() error { _, err := games[0].DiscardHidden(hand2[0].CardID); return err }},
+		{"discarding a card in the deck", func() error {
+			_, err := games[0].DiscardHidden(games[0].Top("", 1)[0])
+			return err
+		}},
+		{"applying a burn of a card that's left the deck", func() error { return games[2].ApplyBurn(burnOfDrawn) }},
+		{"applying a discard as a burn", func() error { return games[0].ApplyBurn(discardOfOther) }},
+		{"applying a burn as a discard", func() error { return games[1].ApplyDiscard(burnOfDrawn) }},
+		{"applying a discard of a card someone else holds", func() error {
+			return games[0].ApplyDiscard(goneTestToken(2, discarded, hand1[0].CardID))
+		}},
+		{"applying a burn by someone not playing", func() error { return games[1].ApplyBurn(goneTestToken(9, burnt, top)) }},
+		{"applying a badly formed burn", func() error { return games[1].ApplyBurn("nope") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.err(); err == nil {
+				t.Errorf("no error was returned")
+			}
+		})
+	}
+}
+
+func TestObserversCantBurn(t *testing.T) {
+	players := []Player{{PublicKey: testKey(t).Public()}, {PublicKey: testKey(t).Public()}}
+	observerPrv := testKey(t)
+	players = append(players, Player{PublicKey: observerPrv.Public(), Role: RoleObserver})
+	var deal bytes.Buffer
+	if err := DealPlayers(&deal, append([]string(nil), testDeck...), testKey(t), players...); err != nil {
+		t.Fatal(err)
+	}
+	observer := openTestGame(t, deal.Bytes(), observerPrv, "")
+
+	if _, err := observer.Burn(0); err != ErrObserving {
+		t.Errorf("got error %v, want %v", err, ErrObserving)
+	}
+	if _, err := observer.DiscardHidden(0); err != ErrObserving {
+		t.Errorf("got error %v, want %v", err, ErrObserving)
+	}
+	// Observers don't see draws being allowed, so take the discarding player's word that they held the card.
+	if err := observer.ApplyDiscard(goneTestToken(1, discarded, 0)); err != nil {
+		t.Errorf("the observer could not apply a discard: %v", err)
+	}
+}
+
+// goneTestToken makes a burn or discard, as the given player would.
+func goneTestToken(player, gone PlayerNumber, cardIDs ...int) string {
+	token := []byte{byte(player), byte(gone)}
+	for _, cardID := range cardIDs {
+		token = binary.LittleEndian.AppendUint16(token, uint16(cardID))
+	}
+	return base64.RawStdEncoding.EncodeToString(token)
+}
@@ -0,0 +1,266 @@
+// Command libtrustdraw builds TrustDraw as a C shared library (with -buildmode=c-shared), exposing the
+// API declared in trustdraw.h to languages that can't link Go packages.
+package main
+
+/*
+#include <stdlib.h>
+#define TRUSTDRAW_TYPES_ONLY
+#include "trustdraw.h"
+*/
+import "C"
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/cards"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+)
+
+func main() {}
+
+//export td_free
+func td_free(ptr unsafe.Pointer) {
+	C.free(ptr)
+}
+
+//export td_deal
+func td_deal(deckName *C.char, cardsIn **C.char, cardCount C.size_t, dealerKey *C.char, playerKeys **C.char, playerCount C.size_t, dealOut **C.char, errorOut **C.char) C.td_status {
+	if dealOut == nil || dealerKey == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("dealer key and deal_out are required"))
+	}
+
+	var dealCards []string
+	if deckName != nil {
+		inBuilt, ok := cards.LoadInBuilt(C.GoString(deckName))
+		if !ok {
+			return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, fmt.Errorf("unknown deck: %s", C.GoString(deckName)))
+		}
+		dealCards = inBuilt
+	} else {
+		dealCards = goStrings(cardsIn, cardCount)
+	}
+
+	dealerPrv, err := cmdhelpers.ParseDealerPrivateKey([]byte(C.GoString(dealerKey)), "dealer_private_key")
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_KEY, err)
+	}
+
+	pemKeys := goStrings(playerKeys, playerCount)
+	playerPubs := make([]crypto.PublicKey, len(pemKeys))
+	for i, pemKey := range pemKeys {
+		if playerPubs[i], err = cmdhelpers.ParsePlayerPublicKey([]byte(pemKey), fmt.Sprintf("player_public_keys[%d]", i)); err != nil {
+			return fail(errorOut, C.TD_ERR_INVALID_KEY, err)
+		}
+	}
+
+	var deal bytes.Buffer
+	if err := trustdraw.Deal(&deal, dealCards, dealerPrv, playerPubs...); err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, err)
+	}
+
+	*dealOut = C.CString(deal.String())
+	return C.TD_OK
+}
+
+//export td_verify_deal
+func td_verify_deal(deal *C.char, dealerKey *C.char, cardsOut *C.int, playersOut *C.int, errorOut **C.char) C.td_status {
+	if deal == nil || dealerKey == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("deal and dealer key are required"))
+	}
+
+	dealerPub, err := cmdhelpers.ParseDealerPublicKey([]byte(C.GoString(dealerKey)), "dealer_public_key")
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_KEY, err)
+	}
+
+	cardCount, players, err := trustdraw.VerifyDeal(strings.NewReader(C.GoString(deal)), dealerPub)
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_DEAL, err)
+	}
+
+	if cardsOut != nil {
+		*cardsOut = C.int(cardCount)
+	}
+	if playersOut != nil {
+		*playersOut = C.int(players)
+	}
+	return C.TD_OK
+}
+
+//export td_open_game
+func td_open_game(deal *C.char, playerKey *C.char, state *C.char, gameOut *C.td_game, errorOut **C.char) C.td_status {
+	if deal == nil || playerKey == nil || gameOut == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("deal, player key and game_out are required"))
+	}
+
+	playerPrv, err := cmdhelpers.ParsePlayerPrivateKey([]byte(C.GoString(playerKey)), "player_private_key")
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_KEY, err)
+	}
+
+	game, err := trustdraw.OpenGame(strings.NewReader(C.GoString(deal)), playerPrv, goStringOrEmpty(state))
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_DEAL, err)
+	}
+
+	*gameOut = C.td_game(cgo.NewHandle(game))
+	return C.TD_OK
+}
+
+//export td_close_game
+func td_close_game(handle C.td_game) {
+	if _, ok := gameFor(handle); ok {
+		cgo.Handle(handle).Delete()
+	}
+}
+
+//export td_game_player
+func td_game_player(handle C.td_game, playerOut *C.int, playersOut *C.int, errorOut **C.char) C.td_status {
+	game, ok := gameFor(handle)
+	if !ok {
+		return fail(errorOut, C.TD_ERR_INVALID_HANDLE, errors.New("not an open game"))
+	}
+
+	if playerOut != nil {
+		*playerOut = C.int(game.PlayerNumber())
+	}
+	if playersOut != nil {
+		*playersOut = C.int(game.Players)
+	}
+	return C.TD_OK
+}
+
+//export td_allow_draw
+func td_allow_draw(handle C.td_game, player C.int, allowKeyOut **C.char, errorOut **C.char) C.td_status {
+	game, ok := gameFor(handle)
+	if !ok {
+		return fail(errorOut, C.TD_ERR_INVALID_HANDLE, errors.New("not an open game"))
+	}
+	if allowKeyOut == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("allow_key_out is required"))
+	}
+
+	allowKey, err := game.AllowDraw(trustdraw.PlayerNumber(player))
+	if errors.Is(err, trustdraw.ErrNoCardsLeft) {
+		return fail(errorOut, C.TD_ERR_NO_CARDS_LEFT, err)
+	} else if err != nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, err)
+	}
+
+	*allowKeyOut = C.CString(allowKey)
+	return C.TD_OK
+}
+
+//export td_draw
+func td_draw(handle C.td_game, allowKeys **C.char, allowKeyCount C.size_t, cardOut **C.char, allowKeyOut **C.char, alreadyDrawnOut *C.int, errorOut **C.char) C.td_status {
+	game, ok := gameFor(handle)
+	if !ok {
+		return fail(errorOut, C.TD_ERR_INVALID_HANDLE, errors.New("not an open game"))
+	}
+	if cardOut == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("card_out is required"))
+	}
+
+	card, allowKey, alreadyDrawn, err := game.Draw(goStrings(allowKeys, allowKeyCount)...)
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_FAILED, err)
+	}
+
+	*cardOut = C.CString(card)
+	if allowKeyOut != nil {
+		*allowKeyOut = C.CString(allowKey)
+	}
+	if alreadyDrawnOut != nil {
+		*alreadyDrawnOut = cBool(alreadyDrawn)
+	}
+	return C.TD_OK
+}
+
+//export td_verify_draw
+func td_verify_draw(handle C.td_game, card *C.char, allowKeys **C.char, allowKeyCount C.size_t, validOut *C.int, errorOut **C.char) C.td_status {
+	game, ok := gameFor(handle)
+	if !ok {
+		return fail(errorOut, C.TD_ERR_INVALID_HANDLE, errors.New("not an open game"))
+	}
+	if card == nil || validOut == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("card and valid_out are required"))
+	}
+
+	valid, err := game.VerifyDraw(C.GoString(card), goStrings(allowKeys, allowKeyCount)...)
+	if err != nil {
+		return fail(errorOut, C.TD_ERR_FAILED, err)
+	}
+
+	*validOut = cBool(valid)
+	return C.TD_OK
+}
+
+//export td_state
+func td_state(handle C.td_game, stateOut **C.char, errorOut **C.char) C.td_status {
+	game, ok := gameFor(handle)
+	if !ok {
+		return fail(errorOut, C.TD_ERR_INVALID_HANDLE, errors.New("not an open game"))
+	}
+	if stateOut == nil {
+		return fail(errorOut, C.TD_ERR_INVALID_ARGUMENT, errors.New("state_out is required"))
+	}
+
+	*stateOut = C.CString(game.State())
+	return C.TD_OK
+}
+
+// gameFor looks up the game an opaque handle refers to, without panicking on invalid handles.
+func gameFor(handle C.td_game) (game *trustdraw.Game, ok bool) {
+	if handle == 0 {
+		return nil, false
+	}
+	defer func() {
+		if recover() != nil {
+			game, ok = nil, false
+		}
+	}()
+
+	game, ok = cgo.Handle(handle).Value().(*trustdraw.Game)
+	return game, ok
+}
+
+// fail sets the error message (if the caller wants it) and returns the status.
+func fail(errorOut **C.char, status C.td_status, err error) C.td_status {
+	if errorOut != nil {
+		*errorOut = C.CString(err.Error())
+	}
+	return status
+}
+
+func goStrings(strs **C.char, count C.size_t) []string {
+	if strs == nil || count == 0 {
+		return nil
+	}
+
+	goStrs := make([]string, count)
+	for i, str := range unsafe.Slice(strs, int(count)) {
+		goStrs[i] = C.GoString(str)
+	}
+	return goStrs
+}
+
+func goStringOrEmpty(str *C.char) string {
+	if str == nil {
+		return ""
+	}
+	return C.GoString(str)
+}
+
+func cBool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+/* Deals a game, then has two players allow, draw and verify a card through the C API. */
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "trustdraw.h"
+
+static int failures = 0;
+
+#define CHECK(cond, ...)                                      \
+	do {                                                      \
+		if (!(cond)) {                                        \
+			fprintf(stderr, "FAIL %s:%d: ", __FILE__, __LINE__); \
+			fprintf(stderr, __VA_ARGS__);                     \
+			fprintf(stderr, "\n");                            \
+			failures++;                                       \
+		}                                                     \
+	} while (0)
+
+static char *read_file(const char *path) {
+	FILE *f = fopen(path, "rb");
+	if (f == NULL) {
+		fprintf(stderr, "could not open %s\n", path);
+		exit(2);
+	}
+	fseek(f, 0, SEEK_END);
+	long size = ftell(f);
+	fseek(f, 0, SEEK_SET);
+
+	char *data = calloc(size + 1, 1);
+	if (fread(data, 1, size, f) != (size_t)size) {
+		fprintf(stderr, "could not read %s\n", path);
+		exit(2);
+	}
+	fclose(f);
+	return data;
+}
+
+int main(void) {
+	char *dealer_prv = read_file("../../../test_data/dealer.pem");
+	char *dealer_pub = read_file("../../../test_data/dealer.pub.pem");
+	char *player1_prv = read_file("../../../test_data/player1.pem");
+	char *player2_prv = read_file("../../../test_data/player2.pem");
+	const char *player_pubs[] = {
+		read_file("../../../test_data/player1.pub.pem"),
+		read_file("../../../test_data/player2.pub.pem"),
+	};
+	char *err = NULL;
+
+	/* Deal */
+	char *deal = NULL;
+	td_status status = td_deal("standard52-fr", NULL, 0, dealer_prv, player_pubs, 2, &deal, &err);
+	CHECK(status == TD_OK, "td_deal returned %d: %s", status, err);
+
+	int cards = 0, players = 0;
+	status = td_verify_deal(deal, dealer_pub, &cards, &players, &err);
+	CHECK(status == TD_OK, "td_verify_deal returned %d: %s", status, err);
+	CHECK(cards == 52 && players == 2, "deal has %d cards for %d players", cards, players);
+
+	/* A deal of custom cards */
+	const char *custom[] = {"A", "B", "C"};
+	char *custom_deal = NULL;
+	status = td_deal(NULL, custom, 3, dealer_prv, player_pubs, 2, &custom_deal, NULL);
+	CHECK(status == TD_OK, "td_deal with custom cards returned %d", status);
+	status = td_verify_deal(custom_deal, dealer_pub, &cards, NULL, NULL);
+	CHECK(status == TD_OK && cards == 3, "custom deal has %d cards", cards);
+	td_free(custom_deal);
+
+	/* Open the game for both players */
+	td_game alice = 0, bob = 0;
+	status = td_open_game(deal, player1_prv, NULL, &alice, &err);
+	CHECK(status == TD_OK, "td_open_game (alice) returned %d: %s", status, err);
+	status = td_open_game(deal, player2_prv, "", &bob, &err);
+	CHECK(status == TD_OK, "td_open_game (bob) returned %d: %s", status, err);
+
+	int player = 0;
+	status = td_game_player(bob, &player, &players, NULL);
+	CHECK(status == TD_OK && player == 2 && players == 2, "bob is player %d of %d", player, players);
+
+	/* Bob allows Alice to draw, and she draws */
+	char *allow_key = NULL;
+	status = td_allow_draw(bob, 1, &allow_key, &err);
+	CHECK(status == TD_OK, "td_allow_draw returned %d: %s", status, err);
+
+	char *card = NULL, *proof = NULL;
+	int already_drawn = -1;
+	const char *allow_keys[] = {allow_key};
+	status = td_draw(alice, allow_keys, 1, &card, &proof, &already_drawn, &err);
+	CHECK(status == TD_OK, "td_draw returned %d: %s", status, err);
+	CHECK(already_drawn == 0, "card was already drawn");
+
+	/* Bob verifies Alice's card */
+	int valid = -1;
+	const char *proofs[] = {proof};
+	status = td_verify_draw(bob, card, proofs, 1, &valid, &err);
+	CHECK(status == TD_OK && valid == 1, "td_verify_draw returned %d, valid=%d", status, valid);
+	status = td_verify_draw(bob, "not a card", proofs, 1, &valid, &err);
+	CHECK(status == TD_OK && valid == 0, "a cheating draw was valid");
+
+	/* State survives re-opening the game */
+	char *state = NULL;
+	status = td_state(alice, &state, &err);
+	CHECK(status == TD_OK, "td_state returned %d: %s", status, err);
+
+	td_game reopened = 0;
+	status = td_open_game(deal, player1_prv, state, &reopened, &err);
+	td_free(state);
+	state = NULL;
+	CHECK(status == TD_OK, "re-opening returned %d: %s", status, err);
+	char *again = NULL;
+	status = td_draw(reopened, allow_keys, 1, &again, NULL, &already_drawn, &err);
+	CHECK(status == TD_OK && already_drawn == 1 && strcmp(card, again) == 0, "re-drawing gave %s", again);
+
+	/* Errors */
+	status = td_verify_deal("not a deal", dealer_pub, NULL, NULL, &err);
+	CHECK(status == TD_ERR_INVALID_DEAL && err != NULL, "bad deal returned %d", status);
+	td_free(err);
+	err = NULL;
+
+	status = td_open_game(deal, dealer_pub, NULL, &reopened, NULL);
+	CHECK(status == TD_ERR_INVALID_KEY, "public key as private key returned %d", status);
+
+	status = td_state(12345, &state, NULL);
+	CHECK(status == TD_ERR_INVALID_HANDLE, "invalid handle returned %d", status);
+
+	td_close_game(alice);
+	td_close_game(bob);
+	td_close_game(reopened);
+	status = td_state(alice, &state, NULL);
+	CHECK(status == TD_ERR_INVALID_HANDLE, "closed handle returned %d", status);
+
+	td_free(deal);
+	td_free(allow_key);
+	td_free(card);
+	td_free(proof);
+	td_free(again);
+
+	if (failures > 0) {
+		fprintf(stderr, "%d checks failed\n", failures);
+		return 1;
+	}
+	printf("✅ all checks passed\n");
+	return 0;
+}
@@ -0,0 +1,89 @@
+/*
+ * TrustDraw C API
+ *
+ * A stable C ABI around the TrustDraw library, for game clients that can't link Go packages
+ * (eg. Unity or Godot). Build it with `make` in this directory, which produces libtrustdraw.so
+ * (or .dylib/.dll) and the header generated by Go alongside it.
+ *
+ * Conventions:
+ *   - Games are referred to by opaque td_game handles, which must be released with td_close_game.
+ *   - Every string returned through an out parameter is allocated by the library, and must be
+ *     released by the caller with td_free.
+ *   - Every call returns a td_status. If error_out is not NULL and the call fails, it is set to a
+ *     description of the error, which must also be released with td_free.
+ *   - Keys are PEM, OpenSSH or JWK encoded strings, for RSA or Ed25519 keys.
+ */
+#ifndef TRUSTDRAW_H
+#define TRUSTDRAW_H
+
+#include <stddef.h>
+#include <stdint.h>
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+typedef uintptr_t td_game;
+
+typedef enum {
+	TD_OK = 0,
+	TD_ERR_INVALID_ARGUMENT = 1,
+	TD_ERR_INVALID_KEY = 2,
+	TD_ERR_INVALID_DEAL = 3,
+	TD_ERR_INVALID_HANDLE = 4,
+	TD_ERR_NO_CARDS_LEFT = 5,
+	TD_ERR_FAILED = 6
+} td_status;
+
+/* The Go side of the library only needs the types above: its exported functions are declared by cgo. */
+#ifndef TRUSTDRAW_TYPES_ONLY
+
+/* Releases memory returned by any td_ function. */
+void td_free(void *ptr);
+
+/*
+ * Shuffles and deals cards for the given players, signed by the dealer.
+ * Either deck_name is one of the in-built decks (eg. "standard52-fr"), or it is NULL and the cards
+ * are given in cards/card_count. The deal file is returned in deal_out.
+ */
+td_status td_deal(const char *deck_name, const char *const *cards, size_t card_count,
+                  const char *dealer_private_key,
+                  const char *const *player_public_keys, size_t player_count,
+                  char **deal_out, char **error_out);
+
+/* Verifies a deal file was made by the dealer, returning how many cards and players it has. */
+td_status td_verify_deal(const char *deal, const char *dealer_public_key,
+                         int *cards_out, int *players_out, char **error_out);
+
+/* Opens a deal for a player, with the state previously returned by td_state (or NULL/"" for a new game). */
+td_status td_open_game(const char *deal, const char *player_private_key, const char *state,
+                       td_game *game_out, char **error_out);
+
+/* Releases an open game. */
+void td_close_game(td_game game);
+
+/* Returns the number of the player a game was opened for, and how many players there are. */
+td_status td_game_player(td_game game, int *player_out, int *players_out, char **error_out);
+
+/* Gets the allowKey that will let the given player draw the next card. */
+td_status td_allow_draw(td_game game, int player, char **allow_key_out, char **error_out);
+
+/* Draws a card with the allowKeys given by the other players. */
+td_status td_draw(td_game game, const char *const *allow_keys, size_t allow_key_count,
+                  char **card_out, char **allow_key_out, int *already_drawn_out, char **error_out);
+
+/* Checks whether the given allowKeys decrypt the card another player says they drew. */
+td_status td_verify_draw(td_game game, const char *card,
+                         const char *const *allow_keys, size_t allow_key_count,
+                         int *valid_out, char **error_out);
+
+/* Returns the game's state, to be stored and given to td_open_game later. */
+td_status td_state(td_game game, char **state_out, char **error_out);
+
+#endif /* TRUSTDRAW_TYPES_ONLY */
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif /* TRUSTDRAW_H */
@@ -0,0 +1,33 @@
+// Loads the TrustDraw WebAssembly build and exposes its API, throwing errors rather than returning them.
+//
+//   import { load } from './trustdraw.mjs';
+//   const trustdraw = await load(fetch('trustdraw.wasm'));
+//
+// Key material is given as PEM or JWK strings.
+import './wasm_exec.js';
+
+const functions = ['deal', 'verifyDeal', 'openGame', 'closeGame', 'allowDraw', 'draw', 'verifyDraw', 'state'];
+
+// load instantiates the WebAssembly module from a Response (or a promise of one), or from its raw bytes.
+export async function load(source) {
+  const go = new Go();
+
+  source = await source;
+  const { instance } = source instanceof Response
+    ? await WebAssembly.instantiateStreaming(source, go.importObject)
+    : await WebAssembly.instantiate(source, go.importObject);
+  go.run(instance);
+
+  const raw = globalThis.trustdraw;
+  const api = { version: raw.version };
+  for (const name of functions) {
+    api[name] = (...args) => {
+      const result = raw[name](...args);
+      if (result !== null && typeof result === 'object' && typeof result.error === 'string') {
+        throw new Error(result.error);
+      }
+      return result;
+    };
+  }
+  return api;
+}
@@ -0,0 +1,237 @@
+//go:build js && wasm
+
+// Command trustdraw-wasm exposes the TrustDraw engine to JavaScript, so games can run in a browser with no server.
+//
+// It registers a global `trustdraw` object whose functions take key material as PEM, OpenSSH or JWK strings.
+// Each function returns its result, or an object with an `error` property if it failed; trustdraw.mjs
+// wraps them so that errors are thrown instead.
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"strings"
+	"syscall/js"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/cards"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+)
+
+var (
+	games      = make(map[int]*trustdraw.Game)
+	nextHandle = 1
+)
+
+func main() {
+	js.Global().Set("trustdraw", js.ValueOf(map[string]any{
+		"version":    trustdraw.Version,
+		"deal":       export(deal),
+		"verifyDeal": export(verifyDeal),
+		"openGame":   export(openGame),
+		"closeGame":  export(closeGame),
+		"allowDraw":  export(allowDraw),
+		"draw":       export(draw),
+		"verifyDraw": export(verifyDraw),
+		"state":      export(state),
+	}))
+
+	// Keep the Go runtime alive so the exported functions can be called.
+	select {}
+}
+
+// export wraps a Go function so it can be called from JavaScript, turning errors into `{error: "…"}`.
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		result, err := fn(args)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return result
+	})
+}
+
+// deal(cards: string | string[], dealerPrivateKey: string, playerPublicKeys: string[]) => string
+func deal(args []js.Value) (any, error) {
+	if err := needArgs(args, 3); err != nil {
+		return nil, err
+	}
+
+	var dealCards []string
+	if args[0].Type() == js.TypeString {
+		inBuilt, ok := cards.LoadInBuilt(args[0].String())
+		if !ok {
+			return nil, fmt.Errorf("unknown deck: %s", args[0].String())
+		}
+		dealCards = inBuilt
+	} else {
+		dealCards = stringSlice(args[0])
+	}
+
+	dealerPrv, err := cmdhelpers.ParseDealerPrivateKey([]byte(args[1].String()), "dealerPrivateKey")
+	if err != nil {
+		return nil, err
+	}
+
+	pemKeys := stringSlice(args[2])
+	playerPubs := make([]crypto.PublicKey, len(pemKeys))
+	for i, pemKey := range pemKeys {
+		if playerPubs[i], err = cmdhelpers.ParsePlayerPublicKey([]byte(pemKey), fmt.Sprintf("playerPublicKeys[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	var dealFile bytes.Buffer
+	if err := trustdraw.Deal(&dealFile, dealCards, dealerPrv, playerPubs...); err != nil {
+		return nil, err
+	}
+	return dealFile.String(), nil
+}
+
+// verifyDeal(dealFile: string, dealerPublicKey: string) => {cards: number, players: number}
+func verifyDeal(args []js.Value) (any, error) {
+	if err := needArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	dealerPub, err := cmdhelpers.ParseDealerPublicKey([]byte(args[1].String()), "dealerPublicKey")
+	if err != nil {
+		return nil, err
+	}
+
+	cardCount, players, err := trustdraw.VerifyDeal(strings.NewReader(args[0].String()), dealerPub)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"cards": cardCount, "players": players}, nil
+}
+
+// openGame(dealFile: string, playerPrivateKey: string, state?: string) => {handle: number, id: string, player: number, players: number}
+func openGame(args []js.Value) (any, error) {
+	if err := needArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	playerPrv, err := cmdhelpers.ParsePlayerPrivateKey([]byte(args[1].String()), "playerPrivateKey")
+	if err != nil {
+		return nil, err
+	}
+
+	state := ""
+	if len(args) > 2 && args[2].Type() == js.TypeString {
+		state = args[2].String()
+	}
+
+	game, err := trustdraw.OpenGame(strings.NewReader(args[0].String()), playerPrv, state)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := nextHandle
+	nextHandle++
+	games[handle] = game
+
+	return map[string]any{
+		"handle":  handle,
+		"id":      game.ID(),
+		"player":  int(game.PlayerNumber()),
+		"players": game.Players,
+	}, nil
+}
+
+// closeGame(handle: number) => true
+func closeGame(args []js.Value) (any, error) {
+	if _, err := gameFor(args); err != nil {
+		return nil, err
+	}
+	delete(games, args[0].Int())
+	return true, nil
+}
+
+// allowDraw(handle: number, player: number) => string
+func allowDraw(args []js.Value) (any, error) {
+	game, err := gameFor(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := needArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	return game.AllowDraw(trustdraw.PlayerNumber(args[1].Int()))
+}
+
+// draw(handle: number, allowKeys: string[]) => {card: string, allowKey: string, alreadyDrawn: boolean}
+func draw(args []js.Value) (any, error) {
+	game, err := gameFor(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := needArgs(args, 2); err != nil {
+		return nil, err
+	}
+
+	card, allowKey, alreadyDrawn, err := game.Draw(stringSlice(args[1])...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"card": card, "allowKey": allowKey, "alreadyDrawn": alreadyDrawn}, nil
+}
+
+// verifyDraw(handle: number, card: string, allowKeys: string[]) => boolean
+func verifyDraw(args []js.Value) (any, error) {
+	game, err := gameFor(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := needArgs(args, 3); err != nil {
+		return nil, err
+	}
+
+	return game.VerifyDraw(args[1].String(), stringSlice(args[2])...)
+}
+
+// state(handle: number) => string
+func state(args []js.Value) (any, error) {
+	game, err := gameFor(args)
+	if err != nil {
+		return nil, err
+	}
+	return game.State(), nil
+}
+
+func gameFor(args []js.Value) (*trustdraw.Game, error) {
+	if err := needArgs(args, 1); err != nil {
+		return nil, err
+	}
+	if args[0].Type() != js.TypeNumber {
+		return nil, fmt.Errorf("game handle must be a number")
+	}
+
+	game, ok := games[args[0].Int()]
+	if !ok {
+		return nil, fmt.Errorf("no open game with handle %d", args[0].Int())
+	}
+	return game, nil
+}
+
+func needArgs(args []js.Value, n int) error {
+	if len(args) < n {
+		return fmt.Errorf("%d arguments needed, %d given", n, len(args))
+	}
+	return nil
+}
+
+// stringSlice converts a JavaScript array (or a single string) into a slice of strings.
+func stringSlice(value js.Value) []string {
+	if value.Type() == js.TypeString {
+		return []string{value.String()}
+	}
+
+	strs := make([]string, value.Length())
+	for i := range strs {
+		strs[i] = value.Index(i).String()
+	}
+	return strs
+}
@@ -0,0 +1,66 @@
+import { test } from 'node:test';
+import assert from 'node:assert/strict';
+import { createPrivateKey } from 'node:crypto';
+import { readFile } from 'node:fs/promises';
+
+import { load } from '../dist/trustdraw.mjs';
+
+const testData = new URL('../../../test_data/', import.meta.url);
+const key = (name) => readFile(new URL(name, testData), 'utf8');
+
+const trustdraw = await load(await readFile(new URL('../dist/trustdraw.wasm', import.meta.url)));
+
+const dealerPrv = await key('dealer.pem');
+const dealerPub = await key('dealer.pub.pem');
+const player1Prv = await key('player1.pem');
+const player2Prv = await key('player2.pem');
+const playerPubs = [await key('player1.pub.pem'), await key('player2.pub.pem')];
+
+test('deals and verifies an in-built deck', () => {
+  const deal = trustdraw.deal('standard52-fr', dealerPrv, playerPubs);
+  assert.deepEqual(trustdraw.verifyDeal(deal, dealerPub), { cards: 52, players: 2 });
+});
+
+test('deals a custom list of cards', () => {
+  const deal = trustdraw.deal(['A', 'B', 'C'], dealerPrv, playerPubs);
+  assert.deepEqual(trustdraw.verifyDeal(deal, dealerPub), { cards: 3, players: 2 });
+});
+
+test('accepts JWK keys', () => {
+  const jwk = (pem) => JSON.stringify(createPrivateKey(pem).export({ format: 'jwk' }));
+
+  const deal = trustdraw.deal(['A', 'B'], jwk(dealerPrv), playerPubs);
+  assert.equal(trustdraw.verifyDeal(deal, dealerPub).cards, 2);
+
+  const game = trustdraw.openGame(deal, jwk(player1Prv));
+  assert.equal(game.player, 1);
+  trustdraw.closeGame(game.handle);
+});
+
+test('allows, draws and verifies a card', () => {
+  const deal = trustdraw.deal('scrabble-en', dealerPrv, playerPubs);
+  const alice = trustdraw.openGame(deal, player1Prv);
+  const bob = trustdraw.openGame(deal, player2Prv, '');
+  assert.equal(alice.id, bob.id);
+  assert.equal(bob.player, 2);
+
+  const allowKey = trustdraw.allowDraw(bob.handle, 1);
+  const drawn = trustdraw.draw(alice.handle, [allowKey]);
+  assert.equal(drawn.alreadyDrawn, false);
+
+  assert.equal(trustdraw.verifyDraw(bob.handle, drawn.card, [drawn.allowKey]), true);
+  assert.equal(trustdraw.verifyDraw(bob.handle, 'not a tile', [drawn.allowKey]), false);
+
+  const reopened = trustdraw.openGame(deal, player1Prv, trustdraw.state(alice.handle));
+  assert.equal(trustdraw.draw(reopened.handle, [allowKey]).alreadyDrawn, true);
+
+  for (const game of [alice, bob, reopened]) {
+    trustdraw.closeGame(game.handle);
+  }
+});
+
+test('throws errors', () => {
+  assert.throws(() => trustdraw.verifyDeal('not a deal', dealerPub), /deal file not valid/);
+  assert.throws(() => trustdraw.openGame('TrustDraw/v1.0', dealerPub), /not a private key/);
+  assert.throws(() => trustdraw.state(9999), /no open game/);
+});
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/jphastings/trustdraw/agent"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// agentCmd represents the agent command
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Holds decrypted private keys, like ssh-agent",
+	Long: `The agent holds decrypted player and dealer keys in memory, and decrypts and signs with them when other trustdraw commands ask it to, so your keys never need to sit on disk in the clear.
+
+Once a key has been added to the agent, give any command the path to the matching public key instead of the private key.`,
+}
+
+// agentServeCmd represents the agent serve command
+var agentServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs the agent on a Unix socket",
+	Long:  `Runs the agent until it is interrupted, forgetting every key it holds when it stops. Set the environment variable it prints so that other commands can find it.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket := cmd.Flag("socket").Value.String()
+		if socket == "" {
+			socket = filepath.Join(os.TempDir(), fmt.Sprintf("trustdraw-%d", os.Getuid()), "agent.sock")
+		}
+
+		listener, err := agent.Listen(socket)
+		if err != nil {
+			return err
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-stop
+			// Closing the listener removes the socket, and ends Serve
+			_ = listener.Close()
+		}()
+
+		fmt.Printf("%s=%s; export %s;\n", agent.SocketEnv, socket, agent.SocketEnv)
+		_, _ = fmt.Fprintf(os.Stderr, "Agent listening on %s\n", socket)
+
+		if err := agent.NewServer().Serve(listener); !errors.Is(err, net.ErrClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+// agentAddCmd represents the agent add command
+var agentAddCmd = &cobra.Command{
+	Use:   "add privateKey…",
+	Short: "Adds private keys to the running agent",
+	Long:  `Loads each private key (asking for its passphrase if it is encrypted) and gives it to the running agent to hold.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := agentFromEnv()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		for _, path := range args {
+			prv, err := cmdhelpers.LoadPrivateKey(path)
+			if err != nil {
+				return err
+			}
+
+			fingerprint, err := client.Add(prv, filepath.Base(path))
+			if err != nil {
+				return fmt.Errorf("could not add %s to the agent: %w", path, err)
+			}
+			fmt.Printf("✅ Added %s (%s)\n", path, fingerprint)
+		}
+		return nil
+	},
+}
+
+// agentListCmd represents the agent list command
+var agentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the keys the running agent holds",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := agentFromEnv()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		keys, err := client.Keys()
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			_, _ = fmt.Fprintln(os.Stderr, "The agent holds no keys")
+		}
+		for _, key := range keys {
+			fmt.Printf("%s %s %s\n", key.Fingerprint, key.Type, key.Comment)
+		}
+		return nil
+	},
+}
+
+// agentRemoveCmd represents the agent remove command
+var agentRemoveCmd = &cobra.Command{
+	Use:   "remove [fingerprint…]",
+	Short: "Makes the running agent forget keys",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all"); all == (len(args) > 0) {
+			return fmt.Errorf("give either the fingerprints of keys to remove, or --all")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := agentFromEnv()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			if err := client.RemoveAll(); err != nil {
+				return err
+			}
+			fmt.Println("✅ Removed every key")
+			return nil
+		}
+
+		for _, fingerprint := range args {
+			if err := client.Remove(fingerprint); err != nil {
+				return fmt.Errorf("could not remove %s: %w", fingerprint, err)
+			}
+			fmt.Printf("✅ Removed %s\n", fingerprint)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.AddCommand(agentServeCmd)
+	agentCmd.AddCommand(agentAddCmd)
+	agentCmd.AddCommand(agentListCmd)
+	agentCmd.AddCommand(agentRemoveCmd)
+
+	agentServeCmd.Flags().String("socket", "", "The path of the Unix socket to listen on (default: a private directory in the temp dir)")
+	agentRemoveCmd.Flags().Bool("all", false, "Remove every key")
+}
+
+func agentFromEnv() (*agent.Client, error) {
+	client, ok := agent.FromEnv()
+	if !ok {
+		return nil, fmt.Errorf("no agent is running (%s is not set)", agent.SocketEnv)
+	}
+	return client, nil
+}
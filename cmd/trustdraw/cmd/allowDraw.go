@@ -15,10 +15,18 @@ import (
 
 // allowDrawCmd represents the allow-draw command
 var allowDrawCmd = &cobra.Command{
-	Use:   "allow-draw dealFile playerPrivateKey playerNumber",
+	Use:   "allow-draw dealFile playerPrivateKey [player]",
 	Short: "Allows a specified player to draw a card",
-	Long:  `Retrieves the allowKey that can be shared with the other player(s) to allow them to draw a card.`,
-	Args:  cobra.ExactArgs(3),
+	Long:  `Retrieves the allowKey that can be shared with the other player(s) to allow them to draw a card. The player can be given by name (with --to) or number. The card is the next to be drawn, unless --from picks the bottom card, a random one (with the last roll, which must be a 1dN roll for the N cards left), or a card number everyone has agreed on (which must be in --pile, if given).`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.RangeArgs(2, 3)(cmd, args); err != nil {
+			return err
+		}
+		if to := cmd.Flag("to").Value.String(); (to == "") == (len(args) == 2) {
+			return fmt.Errorf("give the player to allow to draw, either as an argument or with --to")
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		deal, err := os.Open(args[0])
 		if err != nil {
@@ -31,7 +39,7 @@ var allowDrawCmd = &cobra.Command{
 		}
 
 		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
-		state, stateFileMade, err := cmdhelpers.ReadOrMake(stateFile)
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
 		if err != nil {
 			return fmt.Errorf("the statefile was not writeable: %w", err)
 		}
@@ -39,28 +47,32 @@ var allowDrawCmd = &cobra.Command{
 			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
 		}
 
-		game, err := trustdraw.OpenGame(deal, playerPrv, state)
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
 		if err != nil {
 			return err
 		}
 
-		intendedPlayer, err := strconv.Atoi(args[2])
+		who := cmd.Flag("to").Value.String()
+		if len(args) == 3 {
+			who = args[2]
+		}
+		intendedPlayer, err := game.FindPlayer(who)
 		if err != nil {
-			return fmt.Errorf("player number must be an integer")
+			return err
 		}
-		if intendedPlayer < 1 || intendedPlayer > game.Players {
-			return fmt.Errorf("player #%d is not a part of this game (there are %d players)", intendedPlayer, game.Players)
+
+		from, err := drawSelector(game, cmd.Flag("from").Value.String(), cmd.Flag("pile").Value.String())
+		if err != nil {
+			return err
 		}
 
-		allowKey, err := game.AllowDraw(trustdraw.PlayerNumber(intendedPlayer))
+		allowKey, err := game.AllowDrawFrom(intendedPlayer, from)
 		if err == trustdraw.ErrNoCardsLeft {
 			_, _ = fmt.Fprintf(os.Stderr, "❌ There are no cards left to draw\n")
 		} else if err != nil {
 			return fmt.Errorf("could not get allowKey: %w", err)
-		}
-
-		if err := os.WriteFile(stateFile, []byte(game.State()), 0600); err != nil {
-			return fmt.Errorf("could not save game state: %w", err)
+		} else {
+			_, _ = fmt.Fprintf(os.Stderr, "Allowing %s to draw a card\n", game.PlayerName(intendedPlayer))
 		}
 
 		fmt.Print(allowKey)
@@ -70,4 +82,30 @@ var allowDrawCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(allowDrawCmd)
+
+	allowDrawCmd.Flags().String("to", "", "The name (or number) of the player to allow to draw")
+	allowDrawCmd.Flags().String("pile", "", "The pile to draw from, rather than the main deck")
+	allowDrawCmd.Flags().String("from", "top", "Where in the deck to draw from: top, bottom, random, or a card number")
+}
+
+// drawSelector reads where in the deck, or which pile, to draw from.
+func drawSelector(game *trustdraw.Game, from, pile string) (trustdraw.DrawSelector, error) {
+	switch from {
+	case "top":
+		return trustdraw.DrawTop.In(pile), nil
+	case "bottom":
+		return trustdraw.DrawBottom.In(pile), nil
+	case "random":
+		rolls := game.Rolls()
+		if len(rolls) == 0 {
+			return trustdraw.DrawSelector{}, fmt.Errorf("a random draw needs a roll, of 1d%d", game.RemainingIn(pile))
+		}
+		return trustdraw.DrawRandom(rolls[len(rolls)-1]).In(pile), nil
+	}
+
+	card, err := strconv.Atoi(from)
+	if err != nil || card < 1 {
+		return trustdraw.DrawSelector{}, fmt.Errorf("--from must be top, bottom, random or a card number, not %s", from)
+	}
+	return trustdraw.DrawCard(card - 1).In(pile), nil
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Reveals the burnt and discarded cards, once the game is over",
+}
+
+// auditKeysCmd represents the audit keys command
+var auditKeysCmd = &cobra.Command{
+	Use:   "keys dealFile playerPrivateKey",
+	Short: "Shows your allowKeys for every burnt and discarded card, to share once the game is over",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		allowKeys, err := game.AuditKeys()
+		if err != nil {
+			return err
+		}
+		if len(allowKeys) == 0 {
+			_, _ = fmt.Fprintln(os.Stderr, "No cards have been burnt or discarded")
+			return nil
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share these with the other players once the game is over")
+		fmt.Println(strings.Join(allowKeys, " "))
+		return nil
+	},
+}
+
+// auditRevealCmd represents the audit reveal command
+var auditRevealCmd = &cobra.Command{
+	Use:   "reveal dealFile playerPrivateKey allowKey…",
+	Short: "Reveals the burnt and discarded cards, with every other player's allowKeys for them",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		audited, err := game.Audit(args[2:]...)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The cards could not be revealed: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, card := range audited {
+			how := "Discarded"
+			if card.Burnt {
+				how = "Burnt"
+			}
+			fmt.Printf("%s card %d: %s\n", how, card.CardID+1, card.Card)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditKeysCmd)
+	auditCmd.AddCommand(auditRevealCmd)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/spf13/cobra"
+)
+
+// burnCmd represents the burn command
+var burnCmd = &cobra.Command{
+	Use:   "burn dealFile playerPrivateKey [cards]",
+	Short: "Burns cards from the deck, without anyone seeing them",
+	Long:  `Records cards (like 3 or 3-5,9) as burnt from the deck, or the next card to be drawn (from --pile, if given) if none are given. Share the burn this prints with the other players, who must apply it with 'trustdraw burn apply'. The cards can be revealed once the game is over with 'trustdraw audit'.`,
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		var cards []int
+		if len(args) == 3 {
+			if cards, err = trustdraw.ParseCardIDs(args[2]); err != nil {
+				return err
+			}
+		} else if cards = game.Top(cmd.Flag("pile").Value.String(), 1); len(cards) == 0 {
+			return trustdraw.ErrNoCardsLeft
+		}
+
+		burn, err := game.Burn(cards...)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "🔥 Burnt card %s\n", trustdraw.FormatCardIDs(cards))
+		_, _ = fmt.Fprintln(os.Stderr, "Share this burn with the other players, who must apply it")
+		fmt.Println(burn)
+		return nil
+	},
+}
+
+// burnApplyCmd represents the burn apply command
+var burnApplyCmd = &cobra.Command{
+	Use:   "apply dealFile playerPrivateKey burn",
+	Short: "Applies another player's burn of cards from the deck",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := game.ApplyBurn(args[2]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The burn could not be applied: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("✅ The burn has been applied, the cards can't be drawn")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(burnCmd)
+	burnCmd.AddCommand(burnApplyCmd)
+
+	burnCmd.Flags().String("pile", "", "The pile to burn the next card from, rather than the main deck")
+}
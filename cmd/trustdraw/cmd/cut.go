@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// cutCmd represents the cut command
+var cutCmd = &cobra.Command{
+	Use:   "cut dealFile playerPrivateKey",
+	Short: "Cuts the deck with the last roll",
+	Long:  `Cuts the deck (or --pile, if given) using the last roll, which must be a 1dN roll for the N cards left, so the dealer can't know which cards will be drawn first. Every player must cut with the same roll.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		pile := cmd.Flag("pile").Value.String()
+		rolls := game.Rolls()
+		if len(rolls) == 0 {
+			return fmt.Errorf("a cut needs a roll, of 1d%d", game.RemainingIn(pile))
+		}
+		roll := rolls[len(rolls)-1]
+		if err := game.Cut(pile, roll); err != nil {
+			return err
+		}
+		fmt.Printf("✂️ Cut %d cards from the top to the bottom of the deck\n", roll.Results[0]-1)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cutCmd)
+
+	cutCmd.Flags().String("pile", "", "The pile to cut, rather than the main deck")
+}
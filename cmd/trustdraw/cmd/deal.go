@@ -4,10 +4,10 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"crypto/rsa"
 	"fmt"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/jphastings/trustdraw"
 	decks "github.com/jphastings/trustdraw/cards"
@@ -17,7 +17,7 @@ import (
 
 // dealCmd represents the deal command
 var dealCmd = &cobra.Command{
-	Use:   "deal deck dealerPrivateKey playerPublicKey playerPublicKey…",
+	Use:   "deal deck dealerPrivateKey [name=]playerPublicKey [name=]playerPublicKey… [--observer [name=]publicKey…] [--god [name=]publicKey…] [--escrow threshold] [--pile name=deck…]",
 	Short: "Produce a Deal file for the specified players",
 	Long:  `Produces a Deal file that holds all the information needed to hold a trustless game of cards for the players whose public keys afre provided.`,
 	Args:  cobra.MinimumNArgs(4),
@@ -32,20 +32,54 @@ var dealCmd = &cobra.Command{
 			return err
 		}
 
-		playerPubs := make([]*rsa.PublicKey, len(args)-2)
+		players := make([]trustdraw.Player, len(args)-2)
 		for i, arg := range args[2:] {
-			playerPub, err := cmdhelpers.LoadPlayerPublicKey(arg)
+			name, keyPath := cmdhelpers.SplitNamedKey(arg)
+			playerPub, err := cmdhelpers.LoadPlayerPublicKey(keyPath)
 			if err != nil {
 				return err
 			}
-			playerPubs[i] = playerPub
+			players[i] = trustdraw.Player{Name: name, PublicKey: playerPub}
 		}
 
-		if err := trustdraw.Deal(os.Stdout, cards, dealerPrv, playerPubs...); err != nil {
+		for _, role := range []trustdraw.Role{trustdraw.RoleObserver, trustdraw.RoleGod} {
+			flag := "observer"
+			if role == trustdraw.RoleGod {
+				flag = "god"
+			}
+			observerArgs, _ := cmd.Flags().GetStringArray(flag)
+			for _, arg := range observerArgs {
+				name, keyPath := cmdhelpers.SplitNamedKey(arg)
+				observerPub, err := cmdhelpers.LoadObserverPublicKey(keyPath)
+				if err != nil {
+					return err
+				}
+				players = append(players, trustdraw.Player{Name: name, PublicKey: observerPub, Role: role})
+			}
+		}
+
+		escrow, _ := cmd.Flags().GetInt("escrow")
+		opts := trustdraw.DealOptions{Escrow: escrow}
+		pileArgs, _ := cmd.Flags().GetStringArray("pile")
+		for _, arg := range pileArgs {
+			name, deck, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("piles must be given as name=deck, not %s", arg)
+			}
+			pileCards, err := decks.Load(deck)
+			if err != nil {
+				return err
+			}
+			opts.Piles = append(opts.Piles, trustdraw.Pile{Name: name, Cards: pileCards})
+		}
+		if err := trustdraw.DealWith(os.Stdout, cards, dealerPrv, opts, players...); err != nil {
 			return err
 		}
 
 		_, _ = fmt.Fprintf(os.Stderr, "\nDeal file with %d shuffled cards written to stdout\n", len(cards))
+		for _, pile := range opts.Piles {
+			_, _ = fmt.Fprintf(os.Stderr, "…and a pile of %d called %s\n", len(pile.Cards), pile.Name)
+		}
 		return nil
 	},
 }
@@ -53,6 +87,11 @@ var dealCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(dealCmd)
 
+	dealCmd.Flags().StringArray("observer", nil, "An observer's public key, as [name=]path (repeatable)")
+	dealCmd.Flags().StringArray("god", nil, "A god observer's public key, as [name=]path (repeatable)")
+	dealCmd.Flags().Int("escrow", 0, "How many players are needed to recover the key shares of a player who leaves (2 or more); that many players colluding can read every hand")
+	dealCmd.Flags().StringArray("pile", nil, "A pile to deal alongside the deck, as name=deck (repeatable)")
+
 	dealCmd.SetUsageFunc(func(cmd *cobra.Command) error {
 		fmt.Fprintf(os.Stderr, `Usage: %s %s
 
@@ -62,19 +101,48 @@ func init() {
             containing a list of 'card' names. They cannot be longer than 16
             bytes, and must be one per line (\n).
 
-<dealerKey> The path to an Ed25519 private key in PEM format, used for signing
-            the deck file. Generate a new Ed25519 key pair with:
-              $ openssl genpkey -algorithm ed25519 > dealer.pem
+<dealerKey> The path to an RSA or Ed25519 private key, used for signing the
+            deck file. Generate a new dealer key pair with:
+              $ trustdraw keygen dealer
+
+<playerKey> Each must be the path to an RSA (at least 1024 bits long) or
+            Ed25519 public key. Two or more player keys can be specified.
+            Generate a new player key pair with:
+              $ trustdraw keygen player playerX
+            And share playerX.pub.pem with the dealer.
+
+            SSH keys work too: a player can share ~/.ssh/id_ed25519.pub, or
+            be named by username if a <username>.keys file (like the one at
+            https://github.com/<username>.keys) is in the current directory.
+
+            Players are named after their key files (alice.pub.pem is
+            "alice"), or can be named explicitly with name=path. The names
+            are listed in the deal file, and can be used in place of player
+            numbers, eg. with allow-draw --to alice.
+
+--observer  The public key of someone who can watch the game, but not play
+            it, eg. a commentator. Observers can see cards that players
+            reveal with every player's allowKey for them.
+
+--god       The public key of an observer who, once the game is over, can
+            also be given every player's key stack (with trustdraw release)
+            to see every card.
+
+--escrow    Splits each player's key stack amongst the others, so that this
+            many of them can recover the key shares of a player who leaves
+            the game, and carry on (with trustdraw escrow). It must be at
+            least 2 and fewer than the number of players, so needs 3 or more
+            players. Beware: that many players colluding can read every hand.
+
+--pile      Deals another pile alongside the deck, as name=deck, shuffled on
+            its own, eg. a bonus deck. Draw from it with allow-draw --pile.
 
-<playerKey> Each must be the path to an RSA public key, at least 1024 bits
-            long, in PEM format. Two or more player keys can be specified.
-            Generate a private key with:
-              $ openssl genpkey -algorithm rsa > playerX.pem
-            And extract the public key with:
-              $ openssl rsa -in playerX.pem -pubout -out playerX.pub.pem
+Keys can be PEM (PKCS #8, PKCS #1 or PKIX), OpenSSH or JWK encoded. Check
+what a key is with:
+  $ trustdraw key show playerX.pem
 
-The dealer must publish their public key for the players to trust the deck:
-  $ openssl pkey -in dealer.pem -pubout -out dealer.pub.pem
+The dealer must publish their public key (dealer.pub.pem) for the players to
+trust the deck.
 
 In-build decks:
 
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jphastings/trustdraw/dealer"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// dealerCmd represents the dealer command
+var dealerCmd = &cobra.Command{
+	Use:   "dealer",
+	Short: "Runs a dealer as a service",
+}
+
+// dealerServeCmd represents the dealer serve command
+var dealerServeCmd = &cobra.Command{
+	Use:   "serve dealerPrivateKey",
+	Short: "Runs a dealer that deals games for lobbies of players over HTTP",
+	Long: `Runs an HTTP/JSON dealer. Players open a lobby for one of the in-built decks and join it with their public keys, signing the join request with the matching private key; once the lobby is full the dealer shuffles, signs and publishes the deal file.
+
+Every deal, including any later reshuffles the players ask for, is signed with the same dealer key. Lobbies are held in memory, so are lost when the dealer stops.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dealerPrv, err := cmdhelpers.LoadDealerPrivateKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		addr := cmd.Flag("addr").Value.String()
+		server := dealer.NewServer(dealerPrv)
+
+		_, _ = fmt.Fprintf(os.Stderr, "Dealer listening on %s\n", addr)
+		return http.ListenAndServe(addr, server)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dealerCmd)
+	dealerCmd.AddCommand(dealerServeCmd)
+	dealerServeCmd.Flags().String("addr", "localhost:8081", "The address to listen on")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/spf13/cobra"
+)
+
+// discardCmd represents the discard command
+var discardCmd = &cobra.Command{
+	Use:   "discard dealFile playerPrivateKey card",
+	Short: "Discards a card face-down, without anyone seeing it",
+	Long:  `Records a card in your hand (given as the card, or its number) as discarded without being shown. Share the discard this prints with the other players, who must apply it with 'trustdraw discard apply'. The card can be revealed once the game is over with 'trustdraw audit'.`,
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		cards, err := trustdraw.ParseCardIDs(args[2])
+		if err != nil {
+			hand, handErr := game.Hand()
+			if handErr != nil {
+				return handErr
+			}
+			for _, held := range hand {
+				if held.Card == args[2] {
+					cards, err = []int{held.CardID}, nil
+					break
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s isn't a card number, or a card in your hand", args[2])
+		}
+
+		discard, err := game.DiscardHidden(cards...)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "🗑️ Discarded card %s\n", trustdraw.FormatCardIDs(cards))
+		_, _ = fmt.Fprintln(os.Stderr, "Share this discard with the other players, who must apply it")
+		fmt.Println(discard)
+		return nil
+	},
+}
+
+// discardApplyCmd represents the discard apply command
+var discardApplyCmd = &cobra.Command{
+	Use:   "apply dealFile playerPrivateKey discard",
+	Short: "Applies another player's face-down discard from their hand",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := game.ApplyDiscard(args[2]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The discard could not be applied: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("✅ The discard has been applied, the cards can't be played")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(discardCmd)
+	discardCmd.AddCommand(discardApplyCmd)
+}
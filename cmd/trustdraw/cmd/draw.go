@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jphastings/trustdraw"
 	"github.com/jphastings/trustdraw/internal/cmdhelpers"
@@ -29,7 +30,7 @@ var drawCmd = &cobra.Command{
 		}
 
 		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
-		state, stateFileMade, err := cmdhelpers.ReadOrMake(stateFile)
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
 		if err != nil {
 			return fmt.Errorf("the statefile was not writeable: %w", err)
 		}
@@ -37,7 +38,7 @@ var drawCmd = &cobra.Command{
 			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
 		}
 
-		game, err := trustdraw.OpenGame(deal, playerPrv, state)
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
 		if err != nil {
 			return err
 		}
@@ -47,15 +48,22 @@ var drawCmd = &cobra.Command{
 			return err
 		}
 
-		if err := os.WriteFile(stateFile, []byte(game.State()), 0600); err != nil {
-			return fmt.Errorf("could not save game state: %w", err)
-		}
-
 		verb := "have drawn"
 		if alreadyDrawn {
 			verb = "previously drew"
 		}
-		fmt.Printf("You %s: %s\nProve with: %s\n", verb, card, allowKey)
+		fmt.Printf("You (%s) %s: %s\nProve with: %s\n", game.PlayerName(game.PlayerNumber()), verb, card, allowKey)
+		if len(game.Observers()) > 0 {
+			hand, err := game.Hand()
+			if err != nil {
+				return err
+			}
+			for _, held := range hand {
+				if held.AllowKey == allowKey {
+					fmt.Printf("Show observers with: %s\n", strings.Join(held.AllowKeys, " "))
+				}
+			}
+		}
 		return nil
 	},
 }
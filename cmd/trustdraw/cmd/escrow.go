@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// escrowCmd represents the escrow command
+var escrowCmd = &cobra.Command{
+	Use:   "escrow",
+	Short: "Recovers the key shares of a player who has left the game",
+	Long:  `In games dealt with --escrow, each player's key stack is split amongst the others. If a player leaves, enough of the remaining players can each make a recovery share, and put them together to recover the departed player's key shares for the cards still in the deck, so draws can carry on without them.`,
+}
+
+// escrowShareCmd represents the escrow share command
+var escrowShareCmd = &cobra.Command{
+	Use:   "share dealFile playerPrivateKey departedPlayer",
+	Short: "Makes your share towards recovering a departed player's key shares",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		departed, err := game.FindPlayer(args[2])
+		if err != nil {
+			return err
+		}
+		share, err := game.RecoveryShare(departed)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Share this with the other players, %d of you are needed to recover %s's key shares\n", game.Escrow(), game.PlayerName(departed))
+		fmt.Println(share)
+		return nil
+	},
+}
+
+// escrowRecoverCmd represents the escrow recover command
+var escrowRecoverCmd = &cobra.Command{
+	Use:   "recover dealFile playerPrivateKey departedPlayer recoveryShare…",
+	Short: "Recovers a departed player's key shares from the other players' recovery shares",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		departed, err := game.FindPlayer(args[2])
+		if err != nil {
+			return err
+		}
+		if err := game.Recover(departed, args[3:]...); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ Could not recover %s's key shares: %v\n", game.PlayerName(departed), err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Recovered %s's key shares, draws no longer need their allowKeys\n", game.PlayerName(departed))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(escrowCmd)
+	escrowCmd.AddCommand(escrowShareCmd)
+	escrowCmd.AddCommand(escrowRecoverCmd)
+}
+
+// openStoredGame opens a game, with its state, for the escrow and seat commands, returning the player's key too.
+func openStoredGame(cmd *cobra.Command, dealPath, playerKeyPath string) (*trustdraw.Game, crypto.Signer, error) {
+	deal, err := os.Open(dealPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer deal.Close()
+
+	playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(playerKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), dealPath, playerKeyPath)
+	store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("the statefile was not writeable: %w", err)
+	}
+	if stateFileMade {
+		_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
+	}
+
+	game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
+	return game, playerPrv, err
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw/agent"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// keyCmd represents the key command
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Inspects and converts keys",
+	Long: `Inspects and converts player and dealer keys.
+
+Keys can be read as PEM encoded PKCS #8, PKCS #1, SEC 1 or PKIX keys, OpenSSH private keys or authorized_keys lines (like ~/.ssh/id_ed25519 and id_ed25519.pub), or JWKs. Encrypted PKCS #8 and OpenSSH keys are decrypted with a passphrase you'll be asked for.`,
+}
+
+// keyShowCmd represents the key show command
+var keyShowCmd = &cobra.Command{
+	Use:   "show keyFile",
+	Short: "Describes a key, with its fingerprint",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, format, err := cmdhelpers.LoadKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Key:         %s\n", cmdhelpers.DescribeKey(key))
+		fmt.Printf("Format:      %s\n", format)
+
+		if cmdhelpers.IsUsableKey(key) {
+			fmt.Println("Usable:      as a player or dealer key")
+		} else {
+			fmt.Println("Usable:      no, TrustDraw only uses RSA and Ed25519 keys")
+		}
+
+		fingerprint, err := agent.Fingerprint(cmdhelpers.PublicKeyOf(key))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Fingerprint: %s\n", fingerprint)
+		return nil
+	},
+}
+
+// keyExportCmd represents the key export command
+var keyExportCmd = &cobra.Command{
+	Use:   "export keyFile",
+	Short: "Writes a key out in another format",
+	Long:  `Writes the key to stdout as a PEM (PKCS #8 or PKIX), JWK or OpenSSH key. With --public only the public key is written, ready to share with the dealer or other players.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, _, err := cmdhelpers.LoadKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		public, _ := cmd.Flags().GetBool("public")
+		if public {
+			key = cmdhelpers.PublicKeyOf(key)
+		}
+
+		var passphrase []byte
+		if encrypt, _ := cmd.Flags().GetBool("encrypt"); encrypt {
+			if public {
+				return fmt.Errorf("public keys can't be encrypted")
+			}
+			if passphrase, err = cmdhelpers.ReadNewPassphrase("New passphrase: "); err != nil {
+				return err
+			}
+		}
+
+		format := cmd.Flag("format").Value.String()
+		keyBytes, err := cmdhelpers.EncodeKey(key, format, passphrase)
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(keyBytes)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keyCmd)
+	keyCmd.AddCommand(keyShowCmd)
+	keyCmd.AddCommand(keyExportCmd)
+
+	keyExportCmd.Flags().Bool("public", false, "Only export the public key")
+	keyExportCmd.Flags().String("format", cmdhelpers.FormatPEM, "The format to write: pem, jwk or openssh")
+	keyExportCmd.Flags().Bool("encrypt", false, "Encrypt the exported private key with a new passphrase")
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw/agent"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// keygenCmd represents the keygen command
+var keygenCmd = &cobra.Command{
+	Use:   "keygen player|dealer [name]",
+	Short: "Generates a new player or dealer key pair",
+	Long: `Generates a new key pair: by default an RSA key for a player, or an Ed25519 key for a dealer, though either can use either type. The private key is written to <name>.pem and the public key, which can be shared with anyone, to <name>.pub.pem.
+
+The name defaults to the role.`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"player", "dealer"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		role := args[0]
+		name := role
+		if len(args) > 1 {
+			name = args[1]
+		}
+		prvPath, pubPath := name+".pem", name+".pub.pem"
+
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			for _, path := range []string{prvPath, pubPath} {
+				if _, err := os.Stat(path); err == nil {
+					return fmt.Errorf("%s already exists, use --force to replace it", path)
+				}
+			}
+		}
+
+		if role != "player" && role != "dealer" {
+			return fmt.Errorf("the role must be 'player' or 'dealer', not '%s'", role)
+		}
+		keyType := cmd.Flag("type").Value.String()
+		if keyType == "" {
+			keyType = map[string]string{"player": "rsa", "dealer": "ed25519"}[role]
+		}
+
+		var prv any
+		switch keyType {
+		case "rsa":
+			bits, _ := cmd.Flags().GetInt("bits")
+			if bits < 2048 {
+				return fmt.Errorf("RSA keys must be at least 2048 bits long")
+			}
+			rsaPrv, err := rsa.GenerateKey(crand.Reader, bits)
+			if err != nil {
+				return err
+			}
+			prv = rsaPrv
+		case "ed25519":
+			_, edPrv, err := ed25519.GenerateKey(crand.Reader)
+			if err != nil {
+				return err
+			}
+			prv = edPrv
+		default:
+			return fmt.Errorf("the key type must be 'rsa' or 'ed25519', not '%s'", keyType)
+		}
+
+		var passphrase []byte
+		if encrypt, _ := cmd.Flags().GetBool("encrypt"); encrypt {
+			var err error
+			if passphrase, err = cmdhelpers.ReadNewPassphrase(fmt.Sprintf("Passphrase for %s: ", prvPath)); err != nil {
+				return err
+			}
+		}
+
+		prvBytes, err := cmdhelpers.EncodeKey(prv, cmdhelpers.FormatPEM, passphrase)
+		if err != nil {
+			return err
+		}
+		pub := cmdhelpers.PublicKeyOf(prv)
+		pubBytes, err := cmdhelpers.EncodeKey(pub, cmdhelpers.FormatPEM, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(prvPath, prvBytes, 0600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(pubPath, pubBytes, 0644); err != nil {
+			return err
+		}
+
+		fingerprint, err := agent.Fingerprint(pub)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Generated %s in %s, and its public key in %s\n", cmdhelpers.DescribeKey(prv), prvPath, pubPath)
+		fmt.Printf("Fingerprint: %s\n", fingerprint)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keygenCmd)
+
+	keygenCmd.Flags().String("type", "", "The type of key to make: rsa or ed25519 (default: rsa for players, ed25519 for dealers)")
+	keygenCmd.Flags().Int("bits", 2048, "The size of an RSA key")
+	keygenCmd.Flags().Bool("encrypt", false, "Encrypt the private key with a passphrase")
+	keygenCmd.Flags().Bool("force", false, "Replace existing key files")
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// messageCmd represents the message command
+var messageCmd = &cobra.Command{
+	Use:   "message",
+	Short: "Packs and opens turn messages",
+	Long:  `Turn messages bundle the allowKeys, reveals, plays, moves, rolls, steals, reorders, burns and discards of a whole turn into one signed file that can be sent to the other players by any means.`,
+}
+
+// messagePackCmd represents the message pack command
+var messagePackCmd = &cobra.Command{
+	Use:   "pack dealFile playerPrivateKey",
+	Short: "Packs a signed turn message for the other players",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, playerPrv, err := openGameStateless(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		msg := trustdraw.TurnMessage{
+			GameID:    game.ID(),
+			From:      game.PlayerNumber(),
+			AllowKeys: make(map[trustdraw.PlayerNumber][]string),
+		}
+
+		recipientPubs := make(map[trustdraw.PlayerNumber]crypto.PublicKey)
+		keyFlags, _ := cmd.Flags().GetStringArray("key")
+		for _, keyFlag := range keyFlags {
+			player, keyPath, err := splitPlayerFlag(keyFlag, game)
+			if err != nil {
+				return err
+			}
+			if recipientPubs[player], err = cmdhelpers.LoadPlayerPublicKey(keyPath); err != nil {
+				return err
+			}
+		}
+
+		allowFlags, _ := cmd.Flags().GetStringArray("allow")
+		for _, allowFlag := range allowFlags {
+			player, allowKeys, err := splitPlayerFlag(allowFlag, game)
+			if err != nil {
+				return err
+			}
+			msg.AllowKeys[player] = append(msg.AllowKeys[player], strings.Split(allowKeys, ",")...)
+		}
+
+		if msg.Reveals, err = cardProofFlags(cmd, "reveal"); err != nil {
+			return err
+		}
+		if msg.Plays, err = cardProofFlags(cmd, "play"); err != nil {
+			return err
+		}
+		msg.Moves, _ = cmd.Flags().GetStringArray("move")
+		msg.Rolls, _ = cmd.Flags().GetStringArray("roll")
+		msg.Steals, _ = cmd.Flags().GetStringArray("steal")
+		msg.Reorders, _ = cmd.Flags().GetStringArray("reorder")
+		msg.Burns, _ = cmd.Flags().GetStringArray("burn")
+		msg.Discards, _ = cmd.Flags().GetStringArray("discard")
+
+		return msg.Pack(os.Stdout, playerPrv, recipientPubs)
+	},
+}
+
+// messageOpenCmd represents the message open command
+var messageOpenCmd = &cobra.Command{
+	Use:   "open dealFile playerPrivateKey senderPublicKey [messageFile]",
+	Short: "Opens a turn message sent by another player",
+	Long:  `Checks the signature on a turn message (read from stdin if no file is given), and shows its contents along with any allowKeys addressed to you.`,
+	Args:  cobra.RangeArgs(3, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, playerPrv, err := openGameStateless(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		senderPub, err := cmdhelpers.LoadPlayerPublicKey(args[2])
+		if err != nil {
+			return err
+		}
+
+		var in io.Reader = os.Stdin
+		if len(args) == 4 {
+			file, err := os.Open(args[3])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			in = file
+		}
+
+		msg, err := trustdraw.ReadTurnMessage(in, senderPub)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ This is not a valid turn message: %v\n", err)
+			os.Exit(1)
+		}
+		if msg.GameID != game.ID() {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ This turn message is for a different game (%s)\n", msg.GameID)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Turn message from %s\n", game.PlayerName(msg.From))
+
+		_, allowKeys, err := msg.OpenAllowKeys(playerPrv)
+		if err != nil {
+			return err
+		}
+		for _, allowKey := range allowKeys {
+			fmt.Printf("allowKey for you: %s\n", allowKey)
+		}
+		for _, reveal := range msg.Reveals {
+			fmt.Printf("Revealed: %s (prove with: %s)\n", reveal.Card, reveal.AllowKey)
+		}
+		for _, play := range msg.Plays {
+			fmt.Printf("Played: %s (prove with: %s)\n", play.Card, play.AllowKey)
+		}
+		for _, move := range msg.Moves {
+			fmt.Printf("Move: %s\n", move)
+		}
+		for _, roll := range msg.Rolls {
+			fmt.Printf("Roll: %s\n", roll)
+		}
+		for _, steal := range msg.Steals {
+			fmt.Printf("Steal: %s\n", steal)
+		}
+		for _, reorder := range msg.Reorders {
+			fmt.Printf("Reorder: %s\n", reorder)
+		}
+		for _, burn := range msg.Burns {
+			fmt.Printf("Burn: %s\n", burn)
+		}
+		for _, discard := range msg.Discards {
+			fmt.Printf("Discard: %s\n", discard)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(messageCmd)
+	messageCmd.AddCommand(messagePackCmd)
+	messageCmd.AddCommand(messageOpenCmd)
+
+	messagePackCmd.Flags().StringArray("key", nil, "A recipient's public key, as player=path, where player is their name or number (repeatable)")
+	messagePackCmd.Flags().StringArray("allow", nil, "allowKeys for a recipient, as player=allowKey,allowKey… (repeatable)")
+	messagePackCmd.Flags().StringArray("reveal", nil, "A card to reveal to everyone, as card=allowKey (repeatable)")
+	messagePackCmd.Flags().StringArray("play", nil, "A card being played, as card=allowKey (repeatable)")
+	messagePackCmd.Flags().StringArray("move", nil, "A free-form game move, eg. \"JOKED 8D 50\" (repeatable)")
+	messagePackCmd.Flags().StringArray("roll", nil, "A roll commitment or reveal, from trustdraw roll (repeatable)")
+	messagePackCmd.Flags().StringArray("steal", nil, "A steal offer, pick or reveal, from trustdraw steal (repeatable)")
+	messagePackCmd.Flags().StringArray("reorder", nil, "A new order for cards you peeked at, from trustdraw peek reorder (repeatable)")
+	messagePackCmd.Flags().StringArray("burn", nil, "A burn of cards from the deck, from trustdraw burn (repeatable)")
+	messagePackCmd.Flags().StringArray("discard", nil, "A face-down discard from your hand, from trustdraw discard (repeatable)")
+}
+
+// openGameStateless opens the deal for the given player without touching their state file,
+// for commands that only need to know which game and player they're dealing with.
+func openGameStateless(dealPath, playerKeyPath string) (*trustdraw.Game, crypto.Signer, error) {
+	deal, err := os.Open(dealPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer deal.Close()
+
+	playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(playerKeyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	game, err := trustdraw.OpenGame(deal, playerPrv, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return game, playerPrv, nil
+}
+
+// splitPlayerFlag splits a "player=value" flag, where the player is given by name or number.
+func splitPlayerFlag(flag string, game *trustdraw.Game) (trustdraw.PlayerNumber, string, error) {
+	who, value, ok := strings.Cut(flag, "=")
+	if !ok {
+		return 0, "", fmt.Errorf("'%s' must be in the form player=value", flag)
+	}
+
+	player, err := game.FindPlayer(who)
+	if err != nil {
+		return 0, "", err
+	}
+	return player, value, nil
+}
+
+// cardProofFlags reads "card=allowKey" flags. allowKeys never contain '=', so cards may.
+func cardProofFlags(cmd *cobra.Command, name string) ([]trustdraw.CardProof, error) {
+	flags, _ := cmd.Flags().GetStringArray(name)
+
+	proofs := make([]trustdraw.CardProof, len(flags))
+	for i, flag := range flags {
+		split := strings.LastIndex(flag, "=")
+		if split == -1 {
+			return nil, fmt.Errorf("--%s '%s' must be in the form card=allowKey", name, flag)
+		}
+		proofs[i] = trustdraw.CardProof{Card: flag[:split], AllowKey: flag[split+1:]}
+	}
+
+	return proofs, nil
+}
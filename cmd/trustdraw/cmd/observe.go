@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// observeCmd represents the observe command
+var observeCmd = &cobra.Command{
+	Use:   "observe dealFile observerPrivateKey [release…]",
+	Short: "Watches a game as an observer",
+	Long:  `Shows who is playing a game you're observing. Cards that players reveal with every player's allowKey can be checked with verify-draw. God observers can add the key stacks players release to them (with trustdraw release); once every player's has been added, every card in the deck is shown.`,
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		observerPrv, err := cmdhelpers.LoadPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, observerPrv)
+		if err != nil {
+			return fmt.Errorf("the statefile was not writeable: %w", err)
+		}
+		if stateFileMade {
+			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
+		}
+
+		game, err := trustdraw.OpenGameWithStore(deal, observerPrv, store)
+		if err != nil {
+			return err
+		}
+		if game.Role() == trustdraw.RolePlayer {
+			return fmt.Errorf("you are playing this game, not observing it")
+		}
+
+		var players []string
+		for _, entry := range game.Roster() {
+			players = append(players, game.PlayerName(entry.Player))
+		}
+		fmt.Printf("You are observing as %s. Players: %s\n", game.PlayerName(game.PlayerNumber()), strings.Join(players, ", "))
+
+		if game.Role() != trustdraw.RoleGod {
+			if len(args) > 2 {
+				return fmt.Errorf("only god observers can be given released key stacks")
+			}
+			return nil
+		}
+
+		for _, release := range args[2:] {
+			player, err := game.AddRelease(release, observerPrv)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✅ Added %s's key stack\n", game.PlayerName(player))
+		}
+
+		released := make(map[trustdraw.PlayerNumber]bool)
+		for _, player := range game.Releases() {
+			released[player] = true
+		}
+		var waiting []string
+		for _, entry := range game.Roster() {
+			if !released[entry.Player] {
+				waiting = append(waiting, game.PlayerName(entry.Player))
+			}
+		}
+		if len(waiting) > 0 {
+			fmt.Printf("Waiting for key stacks from: %s\n", strings.Join(waiting, ", "))
+			return nil
+		}
+
+		cards, err := game.Cards()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("The deck, in order: %s\n", strings.Join(cards, "  "))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(observeCmd)
+}
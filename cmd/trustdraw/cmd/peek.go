@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/spf13/cobra"
+)
+
+// peekCmd represents the peek command
+var peekCmd = &cobra.Command{
+	Use:   "peek",
+	Short: "Looks at the next cards to be drawn, without drawing them",
+	Long:  `Every other player allows a player to peek at the next cards to be drawn, who can then look at them, and put them back in a different order. Every player must apply the reorder, so draws are allowed in the new order.`,
+}
+
+// peekAllowCmd represents the peek allow command
+var peekAllowCmd = &cobra.Command{
+	Use:   "allow dealFile playerPrivateKey player count",
+	Short: "Allows a player to peek at the next cards to be drawn",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		intended, err := game.FindPlayer(args[2])
+		if err != nil {
+			return err
+		}
+		count, err := strconv.Atoi(args[3])
+		if err != nil {
+			return fmt.Errorf("count must be a number, not %s", args[3])
+		}
+
+		allowKeys, err := game.AllowPeek(intended, cmd.Flag("pile").Value.String(), count)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Allowing %s to peek at %d cards\n", game.PlayerName(intended), count)
+		fmt.Println(strings.Join(allowKeys, " "))
+		return nil
+	},
+}
+
+// peekLookCmd represents the peek look command
+var peekLookCmd = &cobra.Command{
+	Use:   "look dealFile playerPrivateKey allowKey…",
+	Short: "Looks at the next cards to be drawn, with every other player's allowKeys for them",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		peeked, err := game.Peek(args[2:]...)
+		if err != nil {
+			return err
+		}
+
+		for _, card := range peeked {
+			fmt.Printf("Card %d: %s\n", card.CardID+1, card.Card)
+		}
+		return nil
+	},
+}
+
+// peekReorderCmd represents the peek reorder command
+var peekReorderCmd = &cobra.Command{
+	Use:   "reorder dealFile playerPrivateKey cards",
+	Short: "Puts the cards you peeked at back in a new order, like 5,3,4",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		order, err := trustdraw.ParseCardIDs(args[2])
+		if err != nil {
+			return err
+		}
+
+		reorder, err := game.Reorder(order...)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share this reorder with the other players, who must apply it")
+		fmt.Println(reorder)
+		return nil
+	},
+}
+
+// peekApplyCmd represents the peek apply command
+var peekApplyCmd = &cobra.Command{
+	Use:   "apply dealFile playerPrivateKey reorder",
+	Short: "Applies another player's reorder of the cards they peeked at",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := game.ApplyReorder(args[2]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The reorder could not be applied: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("✅ The reorder has been applied, draws will be allowed in the new order")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(peekCmd)
+	peekCmd.AddCommand(peekAllowCmd)
+	peekCmd.AddCommand(peekLookCmd)
+	peekCmd.AddCommand(peekReorderCmd)
+	peekCmd.AddCommand(peekApplyCmd)
+
+	peekAllowCmd.Flags().String("pile", "", "The pile to peek at, rather than the main deck")
+}
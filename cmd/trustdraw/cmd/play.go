@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+const playHelp = `Commands:
+  hand                         Show the cards in your hand
+  allow player [count]         Get allowKeys so a player (named, or by number) can draw (count) cards
+  draw allowKey…               Draw a card with the allowKeys other players gave you
+  verify player card allowKey…
+                               Verify a card another player says they drew
+  reveal [card]                Show the allowKeys that prove the cards in your hand
+  state                        Show how many cards each player holds
+  help                         Show this help
+  quit                         Leave the game (state is saved after every action)
+`
+
+// playCmd represents the play command
+var playCmd = &cobra.Command{
+	Use:   "play dealFile playerPrivateKey",
+	Short: "Plays a game interactively",
+	Long:  `Opens the game once and keeps it open, offering commands to allow draws, draw, verify and reveal cards. Game state is saved after every action.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+		if err != nil {
+			return fmt.Errorf("the statefile was not writeable: %w", err)
+		}
+		if stateFileMade {
+			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
+		}
+
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
+		if err != nil {
+			return err
+		}
+
+		session := &playSession{game: game, out: os.Stdout}
+		fmt.Printf("You are %s, player %d of %d. Type 'help' for commands.\n", game.PlayerName(game.PlayerNumber()), game.PlayerNumber(), game.Players)
+		return session.run(os.Stdin)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(playCmd)
+}
+
+// playSession is an interactive session with an open game.
+type playSession struct {
+	game *trustdraw.Game
+	out  io.Writer
+}
+
+func (s *playSession) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(s.out)
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return nil
+		}
+
+		if err := s.do(fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(s.out, "❌ %v\n", err)
+		}
+	}
+}
+
+// do runs one command. The game saves its own state after any change.
+func (s *playSession) do(command string, args []string) error {
+	var err error
+	switch command {
+	case "hand":
+		err = s.hand()
+	case "allow":
+		err = s.allow(args)
+	case "draw":
+		err = s.draw(args)
+	case "verify":
+		err = s.verify(args)
+	case "reveal":
+		err = s.reveal(args)
+	case "state":
+		s.state()
+	case "help":
+		fmt.Fprint(s.out, playHelp)
+	default:
+		err = fmt.Errorf("unknown command '%s', type 'help' for commands", command)
+	}
+	return err
+}
+
+func (s *playSession) hand() error {
+	hand, err := s.game.Hand()
+	if err != nil {
+		return err
+	}
+	if len(hand) == 0 {
+		fmt.Fprintln(s.out, "Your hand is empty")
+		return nil
+	}
+
+	cards := make([]string, len(hand))
+	for i, held := range hand {
+		cards[i] = held.Card
+	}
+	fmt.Fprintf(s.out, "Your hand: %s\n", strings.Join(cards, "  "))
+	return nil
+}
+
+func (s *playSession) allow(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: allow player [count]")
+	}
+
+	player, err := s.game.FindPlayer(args[0])
+	if err != nil {
+		return err
+	}
+	count := 1
+	if len(args) == 2 {
+		if count, err = strconv.Atoi(args[1]); err != nil || count < 1 {
+			return fmt.Errorf("count must be a positive integer")
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		allowKey, err := s.game.AllowDraw(player)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "allowKey for %s: %s\n", s.game.PlayerName(player), allowKey)
+	}
+	return nil
+}
+
+func (s *playSession) draw(args []string) error {
+	card, allowKey, alreadyDrawn, err := s.game.Draw(args...)
+	if err != nil {
+		return err
+	}
+
+	verb := "have drawn"
+	if alreadyDrawn {
+		verb = "previously drew"
+	}
+	fmt.Fprintf(s.out, "You %s: %s\nProve with: %s\n", verb, card, allowKey)
+	return nil
+}
+
+func (s *playSession) verify(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: verify player card allowKey…")
+	}
+
+	player, err := s.game.FindPlayer(args[0])
+	if err != nil {
+		return err
+	}
+
+	valid, err := s.game.VerifyDrawBy(player, args[1], args[2:]...)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("this was not a valid draw by %s", s.game.PlayerName(player))
+	}
+
+	fmt.Fprintf(s.out, "✅ This was a valid draw by %s\n", s.game.PlayerName(player))
+	return nil
+}
+
+func (s *playSession) reveal(args []string) error {
+	hand, err := s.game.Hand()
+	if err != nil {
+		return err
+	}
+
+	shown := 0
+	for _, held := range hand {
+		if len(args) > 0 && held.Card != strings.Join(args, " ") {
+			continue
+		}
+		fmt.Fprintf(s.out, "%s  Prove with: %s\n", held.Card, held.AllowKey)
+		shown++
+	}
+
+	if shown == 0 && len(args) > 0 {
+		return fmt.Errorf("'%s' is not in your hand", strings.Join(args, " "))
+	}
+	return nil
+}
+
+func (s *playSession) state() {
+	holdings := s.game.Holdings()
+	fmt.Fprintf(s.out, "Cards left to draw: %d\n", holdings[0])
+	for _, entry := range s.game.Roster() {
+		who := fmt.Sprintf("Player %d", entry.Player)
+		if entry.Name != "" {
+			who = entry.Name
+		}
+		if entry.Player == s.game.PlayerNumber() {
+			who += " (you)"
+		}
+		fmt.Fprintf(s.out, "%s holds %d\n", who, holdings[entry.Player])
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release dealFile playerPrivateKey godPublicKey",
+	Short: "Releases your key stack to a god observer, once the game is over",
+	Long:  `Encrypts your key stack so that only the given god observer can read it. Once every player has released theirs, the god observer can see every card in the deck. You can't allow draws or draw cards in this game afterwards.`,
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+
+		playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+		godPub, err := cmdhelpers.LoadObserverPublicKey(args[2])
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+		if err != nil {
+			return fmt.Errorf("the statefile was not writeable: %w", err)
+		}
+		if stateFileMade {
+			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
+		}
+
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
+		if err != nil {
+			return err
+		}
+
+		release, err := game.Release(godPub)
+		if err != nil {
+			return err
+		}
+
+		god := "the god observer"
+		if fingerprint, err := trustdraw.Fingerprint(godPub); err == nil {
+			for _, observer := range game.Observers() {
+				if observer.Fingerprint == fingerprint {
+					god = game.PlayerName(observer.Player)
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "Released your key stack to %s, the game is now over for you\n", god)
+		fmt.Println(release)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// rollCmd represents the roll command
+var rollCmd = &cobra.Command{
+	Use:   "roll",
+	Short: "Rolls dice that no player can choose the outcome of",
+	Long:  `Every player starts the roll, sharing a commitment to a random value. Once everyone has every commitment, each player reveals their value, and the dice are worked out from all of them together. Commitments and reveals can be sent in turn messages with --roll, so the roll is recorded in the transcript.`,
+}
+
+// rollStartCmd represents the roll start command
+var rollStartCmd = &cobra.Command{
+	Use:   "start dealFile playerPrivateKey dice",
+	Short: "Starts your part in a roll of dice, like 2d6 or d20",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, sides, err := parseDice(args[2])
+		if err != nil {
+			return err
+		}
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		commit, err := game.Roll(sides, count)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share this commitment with the other players, and reveal once you have all of theirs")
+		fmt.Println(commit)
+		return nil
+	},
+}
+
+// rollRevealCmd represents the roll reveal command
+var rollRevealCmd = &cobra.Command{
+	Use:   "reveal dealFile playerPrivateKey commitment…",
+	Short: "Reveals your value for the roll, given every other player's commitment",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		reveal, err := game.RevealRoll(args[2:]...)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share this reveal with the other players")
+		fmt.Println(reveal)
+		return nil
+	},
+}
+
+// rollFinishCmd represents the roll finish command
+var rollFinishCmd = &cobra.Command{
+	Use:   "finish dealFile playerPrivateKey reveal…",
+	Short: "Checks every other player's reveal, and shows the roll",
+	Args:  cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		roll, err := game.FinishRoll(args[2:]...)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The roll could not be checked: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🎲 Roll %d, %s\n", roll.Number, roll)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollCmd)
+	rollCmd.AddCommand(rollStartCmd)
+	rollCmd.AddCommand(rollRevealCmd)
+	rollCmd.AddCommand(rollFinishCmd)
+}
+
+// parseDice reads dice notation, like "2d6" or "d20".
+func parseDice(dice string) (count, sides int, err error) {
+	countText, sidesText, ok := strings.Cut(strings.ToLower(dice), "d")
+	if countText == "" {
+		countText = "1"
+	}
+	count, countErr := strconv.Atoi(countText)
+	sides, sidesErr := strconv.Atoi(sidesText)
+	if !ok || countErr != nil || sidesErr != nil {
+		return 0, 0, fmt.Errorf("dice must be given like 2d6 or d20, not %s", dice)
+	}
+	return count, sides, nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/spf13/cobra"
+)
+
+// seatCmd represents the seat command
+var seatCmd = &cobra.Command{
+	Use:   "seat",
+	Short: "Adds a player to a game in progress",
+	Long:  `A new player can join a game in progress with a seat: either one issued by the dealer, or a part offered by every player already in the game. The seat re-keys the cards still in the deck, so drawing them needs the new player's allowKey too. Add the seat to the deal file with 'seat add', and everyone must use the extended deal file from then on.`,
+}
+
+// seatUndrawnCmd represents the seat undrawn command
+var seatUndrawnCmd = &cobra.Command{
+	Use:   "undrawn dealFile playerPrivateKey",
+	Short: "Lists the cards still in the deck, for the dealer to re-key with 'seat issue'",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		undrawn := game.Undrawn()
+		if len(undrawn) == 0 {
+			return trustdraw.ErrNoCardsLeft
+		}
+		fmt.Println(trustdraw.FormatCardIDs(undrawn))
+		return nil
+	},
+}
+
+// seatOfferCmd represents the seat offer command
+var seatOfferCmd = &cobra.Command{
+	Use:   "offer dealFile playerPrivateKey [name=]newPlayerPublicKey",
+	Short: "Offers your part of a seat for a new player",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, playerPrv, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		newPlayer, err := loadNewPlayer(args[2])
+		if err != nil {
+			return err
+		}
+
+		part, err := game.OfferSeat(newPlayer, playerPrv)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Share this with the other players, every player's part is needed to seat %s\n", newPlayer.Name)
+		fmt.Println(part)
+		return nil
+	},
+}
+
+// seatIssueCmd represents the seat issue command
+var seatIssueCmd = &cobra.Command{
+	Use:   "issue dealFile dealerPrivateKey undrawnCards [name=]newPlayerPublicKey playerPublicKey…",
+	Short: "Issues a seat for a new player, as the dealer",
+	Long:  `Issues a seat for a new player that re-keys the given cards (from 'seat undrawn'), which every player must agree are still in the deck. The public keys of every player already in the game are needed, in player number order.`,
+	Args:  cobra.MinimumNArgs(5),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		dealerPrv, err := cmdhelpers.LoadDealerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+		cards, err := trustdraw.ParseCardIDs(args[2])
+		if err != nil {
+			return err
+		}
+		newPlayer, err := loadNewPlayer(args[3])
+		if err != nil {
+			return err
+		}
+		playerPubs := make([]crypto.PublicKey, len(args)-4)
+		for i, keyPath := range args[4:] {
+			_, keyPath = cmdhelpers.SplitNamedKey(keyPath)
+			if playerPubs[i], err = cmdhelpers.LoadPlayerPublicKey(keyPath); err != nil {
+				return err
+			}
+		}
+
+		seat, err := trustdraw.SeatPlayer(deal, dealerPrv, newPlayer, cards, playerPubs...)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Add this to the deal file with 'trustdraw seat add' to seat %s\n", newPlayer.Name)
+		fmt.Println(seat)
+		return nil
+	},
+}
+
+// seatAddCmd represents the seat add command
+var seatAddCmd = &cobra.Command{
+	Use:   "add dealFile seat…",
+	Short: "Adds a seat, or every player's part of one, to the deal file",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		if err := trustdraw.ExtendDeal(deal, os.Stdout, args[1:]...); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "\nExtended deal file written to stdout, every player must replace their deal file with it\n")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(seatCmd)
+	seatCmd.AddCommand(seatUndrawnCmd)
+	seatCmd.AddCommand(seatOfferCmd)
+	seatCmd.AddCommand(seatIssueCmd)
+	seatCmd.AddCommand(seatAddCmd)
+}
+
+// loadNewPlayer loads the public key of a player to seat, from a [name=]path argument.
+func loadNewPlayer(arg string) (trustdraw.Player, error) {
+	name, keyPath := cmdhelpers.SplitNamedKey(arg)
+	pub, err := cmdhelpers.LoadPlayerPublicKey(keyPath)
+	if err != nil {
+		return trustdraw.Player{}, err
+	}
+	return trustdraw.Player{Name: name, PublicKey: pub}, nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jphastings/trustdraw/relay"
+	"github.com/spf13/cobra"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs a relay server for hosting games",
+	Long: `Runs an HTTP server that stores deal files, relays turn messages between players (who authenticate with their game keys), and publishes each game's public transcript.
+
+The relay never learns any hidden cards. Games are held in memory, so are lost when the server stops.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr := cmd.Flag("addr").Value.String()
+		server := relay.NewServer(relay.NewMemoryBackend())
+
+		_, _ = fmt.Fprintf(os.Stderr, "Relay listening on %s\n", addr)
+		return http.ListenAndServe(addr, server)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("addr", "localhost:8080", "The address to listen on")
+}
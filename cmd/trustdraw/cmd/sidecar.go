@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jphastings/trustdraw/sidecar"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// sidecarCmd represents the sidecar command
+var sidecarCmd = &cobra.Command{
+	Use:   "sidecar",
+	Short: "Runs the TrustDraw library as a gRPC/Connect service",
+	Long: `Serves the TrustDraw service (see proto/trustdraw/v1/trustdraw.proto) so that game servers written in any language can deal, open games, allow and make draws, and subscribe to transcript events over localhost.
+
+The gRPC, gRPC-Web and Connect protocols are all supported. Open games are held in memory, so save their state before stopping the sidecar.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr := cmd.Flag("addr").Value.String()
+
+		mux := http.NewServeMux()
+		mux.Handle(sidecar.NewHandler())
+
+		_, _ = fmt.Fprintf(os.Stderr, "Sidecar listening on %s\n", addr)
+		// h2c lets gRPC clients use HTTP/2 without TLS, which is fine over localhost.
+		return http.ListenAndServe(addr, h2c.NewHandler(mux, &http2.Server{}))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sidecarCmd)
+	sidecarCmd.Flags().String("addr", "localhost:50051", "The address to listen on")
+}
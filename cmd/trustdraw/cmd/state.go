@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/jphastings/trustdraw/statestore"
+	"github.com/spf13/cobra"
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Moves game state between machines",
+	Long:  `Game state files are encrypted under a key derived from your private key, so they can only be read (or changed without detection) by you. Export a game's state to carry it to another machine holding the same key, and import it there.`,
+}
+
+// stateExportCmd represents the state export command
+var stateExportCmd = &cobra.Command{
+	Use:   "export dealFile playerPrivateKey [exportFile]",
+	Short: "Exports the encrypted state of a game",
+	Long:  `Writes the state of the game (to stdout if no file is given), still encrypted, after checking that it hasn't been tampered with.`,
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		if _, err := os.Stat(stateFile); err != nil {
+			return fmt.Errorf("could not read state file at %s: %w", stateFile, err)
+		}
+		store, _, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+		if err != nil {
+			return err
+		}
+
+		state, err := store.Load()
+		if err != nil {
+			return fmt.Errorf("could not load game state: %w", err)
+		}
+		exported, err := store.Seal(state)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 3 {
+			if err := os.WriteFile(args[2], []byte(exported), 0600); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "✅ Exported the game state to %s\n", args[2])
+			return nil
+		}
+		fmt.Println(exported)
+		return nil
+	},
+}
+
+// stateImportCmd represents the state import command
+var stateImportCmd = &cobra.Command{
+	Use:   "import dealFile playerPrivateKey [exportFile]",
+	Short: "Imports the state of a game exported on another machine",
+	Long:  `Checks the exported state (read from stdin if no file is given) belongs to this game and player, then saves it to this machine's state file. Unencrypted state files, made by older versions of TrustDraw, can be imported too.`,
+	Args:  cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+
+		var in io.Reader = os.Stdin
+		if len(args) == 3 {
+			file, err := os.Open(args[2])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			in = file
+		}
+		exported, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		store, _, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+		if err != nil {
+			return fmt.Errorf("the statefile was not writeable: %w", err)
+		}
+
+		state, err := store.Open(string(exported))
+		if errors.Is(err, statestore.ErrNotEncrypted) {
+			state = strings.TrimSpace(string(exported))
+		} else if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ This game state can't be imported: %v\n", err)
+			os.Exit(1)
+		}
+
+		if _, err := trustdraw.OpenGame(deal, playerPrv, state); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ This game state can't be imported: %v\n", err)
+			os.Exit(1)
+		}
+
+		// A state that can't be read (like a corrupt one) might still be the only copy of a game, so needs --force too.
+		current, err := store.Load()
+		if errors.Is(err, statestore.ErrNotEncrypted) {
+			// Older state files are plaintext, so can be compared as they are.
+			var plain []byte
+			plain, err = os.ReadFile(stateFile)
+			current = string(plain)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if err != nil && !force {
+			return fmt.Errorf("%s holds a game state that can't be read (%v), use --force to replace it", stateFile, err)
+		}
+		if err == nil && strings.TrimSpace(current) != "" && strings.TrimSpace(current) != state && !force {
+			return fmt.Errorf("%s already holds a different game state, use --force to replace it", stateFile)
+		}
+
+		if err := store.Save(state); err != nil {
+			return fmt.Errorf("could not save game state: %w", err)
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "✅ Imported the game state into %s\n", stateFile)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	stateImportCmd.Flags().Bool("force", false, "Replace the game state even if the state file already holds a different one")
+}
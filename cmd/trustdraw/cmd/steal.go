@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// stealCmd represents the steal command
+var stealCmd = &cobra.Command{
+	Use:   "steal",
+	Short: "Steals a random card from another player's hand",
+	Long:  `The victim offers their hand in a secret order, the thief picks a card from it, and the victim hands it over, revealing the order so everyone can check they didn't choose the card. Offers, picks and reveals can be sent in turn messages with --steal, so the steal is recorded in the transcript.`,
+}
+
+// stealOfferCmd represents the steal offer command
+var stealOfferCmd = &cobra.Command{
+	Use:   "offer dealFile playerPrivateKey thief",
+	Short: "Offers your hand, in a secret order, to the player stealing from you",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		thief, err := game.FindPlayer(args[2])
+		if err != nil {
+			return err
+		}
+
+		offer, err := game.OfferHand(thief)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "Share this offer with %s, so they can pick a card\n", game.PlayerName(thief))
+		fmt.Println(offer)
+		return nil
+	},
+}
+
+// stealPickCmd represents the steal pick command
+var stealPickCmd = &cobra.Command{
+	Use:   "pick dealFile playerPrivateKey offer",
+	Short: "Picks a card from the hand offered to you, at random unless --index is given",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		index, _ := cmd.Flags().GetInt("index")
+
+		pick, err := game.PickCard(args[2], index)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share this pick with every player")
+		fmt.Println(pick)
+		return nil
+	},
+}
+
+// stealHandOverCmd represents the steal hand-over command
+var stealHandOverCmd = &cobra.Command{
+	Use:   "hand-over dealFile playerPrivateKey pick",
+	Short: "Hands the picked card over to the thief",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		reveal, allowKeys, err := game.HandOver(args[2])
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "Share the reveal with every player, and give the allowKeys to the thief only")
+		fmt.Println(reveal)
+		fmt.Println(strings.Join(allowKeys, ","))
+		return nil
+	},
+}
+
+// stealTakeCmd represents the steal take command
+var stealTakeCmd = &cobra.Command{
+	Use:   "take dealFile playerPrivateKey reveal allowKey,allowKey…",
+	Short: "Takes the card you picked, with the allowKeys the victim handed over",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		card, allowKey, err := game.TakeCard(args[2], strings.Split(args[3], ",")...)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The steal could not be checked: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("You stole: %s\n", card)
+		_, _ = fmt.Fprintf(os.Stderr, "(Prove it with: %s)\n", allowKey)
+		return nil
+	},
+}
+
+// stealRecordCmd represents the steal record command
+var stealRecordCmd = &cobra.Command{
+	Use:   "record dealFile playerPrivateKey pick reveal",
+	Short: "Checks a steal between two other players, and records who holds the card now",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		game, _, err := openStoredGame(cmd, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		steal, err := game.RecordSteal(args[2], args[3])
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "❌ The steal could not be checked: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ %s stole card %d of %d from %s\n", game.PlayerName(steal.Thief), steal.Index, len(steal.Hand), game.PlayerName(steal.Victim))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stealCmd)
+	stealCmd.AddCommand(stealOfferCmd)
+	stealCmd.AddCommand(stealPickCmd)
+	stealCmd.AddCommand(stealHandOverCmd)
+	stealCmd.AddCommand(stealTakeCmd)
+	stealCmd.AddCommand(stealRecordCmd)
+
+	stealPickCmd.Flags().Int("index", 0, "The position of the card to pick, from 1 (picks at random if not given)")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/jphastings/trustdraw/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui dealFile playerPrivateKey",
+	Short: "Plays a game in a full-screen terminal interface",
+	Long:  `Shows your hand, the cards left in the deck and how many cards each other player holds. allowKeys you make are listed so they can be copied to the clipboard with one keystroke (using OSC 52, so it works over SSH).`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deal, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer deal.Close()
+
+		playerPrv, err := cmdhelpers.LoadPlayerPrivateKey(args[1])
+		if err != nil {
+			return err
+		}
+
+		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
+		store, stateFileMade, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
+		if err != nil {
+			return fmt.Errorf("the statefile was not writeable: %w", err)
+		}
+		if stateFileMade {
+			_, _ = fmt.Fprintf(os.Stderr, "Creating %s to hold game state…\n", stateFile)
+		}
+
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
+		if err != nil {
+			return err
+		}
+
+		_, err = tea.NewProgram(tui.New(game, os.Stderr), tea.WithAltScreen()).Run()
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -29,22 +29,31 @@ var verifyDrawCmd = &cobra.Command{
 		}
 
 		stateFile := cmdhelpers.StateFile(cmd.Flag("state").Value.String(), args[0], args[1])
-		state, err := os.ReadFile(stateFile)
+		store, _, err := cmdhelpers.StateStore(stateFile, deal, playerPrv)
 		if err != nil {
 			return fmt.Errorf("could not read state file at %s: %w", stateFile, err)
 		}
 
-		game, err := trustdraw.OpenGame(deal, playerPrv, string(state))
+		game, err := trustdraw.OpenGameWithStore(deal, playerPrv, store)
 		if err != nil {
 			return err
 		}
 
-		valid, err := game.VerifyDraw(args[2], args[3:]...)
+		// Players seated after the card was drawn don't know who drew it, so can only check the card.
+		drawer := game.GivenTo(args[3:]...)
+		var valid bool
+		if drawer != 0 {
+			valid, err = game.VerifyDrawBy(drawer, args[2], args[3:]...)
+		} else {
+			valid, err = game.VerifyDraw(args[2], args[3:]...)
+		}
 		if err != nil {
 			return err
 		}
 
-		if valid {
+		if valid && drawer != 0 {
+			_, _ = fmt.Fprintf(os.Stdout, "✅ This was a valid draw by %s\n", game.PlayerName(drawer))
+		} else if valid {
 			_, _ = fmt.Fprintf(os.Stdout, "✅ This was a valid draw\n")
 		} else {
 			_, _ = fmt.Fprintf(os.Stderr, "❌ This was not a valid draw\n")
@@ -5,8 +5,11 @@ import "crypto/aes"
 const (
 	rsaBits       = 1024
 	aesCipherSize = 16
+	x25519KeySize = 32
 	cardLength    = aes.BlockSize
 	maxCards      = 65536
-	// Chosen so the largest player number fits into 1 base64 encoded byte, with player 0 being reserved
-	maxPlayers = 191
 )
+
+// MaxPlayers is the most players (and observers) a game can be dealt to. It's chosen so the largest player number
+// fits into 1 base64 encoded byte, with player 0 being reserved.
+const MaxPlayers = 191
@@ -0,0 +1,94 @@
+package trustdraw
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// The deck can be cut once it's dealt, like a non-dealer cutting a real deck, so the dealer can't stack the top
+// cards: the cards they put first could end up anywhere. The players roll one die with as many sides as there are
+// cards left (see Game.Roll), and every player cuts the deck by the roll's result (see Game.Cut), moving that many
+// cards from the top of the deck to the bottom. The roll is random as long as any one player's value was, so the
+// dealer can't know where the cut will be.
+//
+// A cut only stops the dealer choosing which cards come first. The cards stay in the dealer's order, just starting
+// somewhere else, so patterns the dealer stacked (like every other card going to the same player) survive it. Once
+// the roll is revealed everyone, the dealer included, knows the draw order, and the dealer knows what every card is,
+// so a dealer colluding with a player can still know every hand.
+
+// deckCut is a cut this player has made, with the roll that chose it.
+type deckCut struct {
+	roll int
+	pile string
+}
+
+// Cut cuts the given pile ("" for the main deck) by the result of a roll every player took part in, which must be of
+// one die with as many sides as there are cards left in the pile. A roll of 1 leaves the pile as it was, and a roll of
+// N moves the top N-1 cards to the bottom. Every player must cut with the same roll.
+func (g *Game) Cut(pile string, roll DiceRoll) error {
+	if err := g.canPlay(); err != nil {
+		return err
+	}
+	if _, err := g.findPile(pile); err != nil {
+		return err
+	}
+	roll, err := g.usableRoll(roll)
+	if err != nil {
+		return err
+	}
+	order := g.drawOrder(pile)
+	if roll.Count != 1 || roll.Sides != len(order) {
+		return fmt.Errorf("a cut needs a roll of 1d%d, not %dd%d", len(order), roll.Count, roll.Sides)
+	}
+
+	// The cut pile is drawn before any cards reordered in other piles, which keep their order.
+	offset := roll.Results[0] - 1
+	oldOrder := g.order
+	g.order = append(append([]int(nil), order[offset:]...), order[:offset]...)
+	for _, cardID := range oldOrder {
+		if g.state[cardID] == 0 && g.PileOf(cardID) != pile {
+			g.order = append(g.order, cardID)
+		}
+	}
+	g.cuts = append(g.cuts, deckCut{roll: roll.Number, pile: pile})
+	g.usedRolls[roll.Number] = true
+	if err := g.Save(); err != nil {
+		g.order = oldOrder
+		g.cuts = g.cuts[:len(g.cuts)-1]
+		delete(g.usedRolls, roll.Number)
+		return fmt.Errorf("could not save game state: %w", err)
+	}
+	return nil
+}
+
+// cutStateLines encodes the cuts this player has made as state lines.
+func (g *Game) cutStateLines() []string {
+	var lines []string
+	for _, cut := range g.cuts {
+		line := "cut " + strconv.Itoa(cut.roll)
+		if cut.pile != "" {
+			line += " " + cut.pile
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// loadCutState reads a "cut" state line.
+func (g *Game) loadCutState(fields []string) error {
+	if len(fields) < 2 || len(fields) > 3 {
+		return fmt.Errorf("state holds an invalid cut")
+	}
+	roll, err := strconv.Atoi(fields[1])
+	if err != nil || roll < 1 {
+		return fmt.Errorf("state holds an invalid cut")
+	}
+	cut := deckCut{roll: roll}
+	if len(fields) == 3 {
+		cut.pile = fields[2]
+	}
+	g.cuts = append(g.cuts, cut)
+	// State saved before used rolls were recorded only lists the roll in its cut.
+	g.usedRolls[roll] = true
+	return nil
+}
@@ -0,0 +1,39 @@
+package trustdraw
+
+import "testing"
+
+func TestCut(t *testing.T) {
+	deal, prvs, _ := testDeal(t, 2, DealOptions{})
+	games := []*Game{openTestGame(t, deal, prvs[0], ""), openTestGame(t, deal, prvs[1], "")}
+	before := games[0].Top("", len(testDeck))
+	_, _, roll := testRoll(t, games, len(testDeck), 1)
+	for _, game := range games {
+		if err := game.Cut("", roll); err != nil {
+			t.Fatalf("could not cut: %v", err)
+		}
+	}
+
+	// The cut moves the top cards to the bottom, keeping their order.
+	offset := roll.Results[0] - 1
+	want := append(append([]int(nil), before[offset:]...), before[:offset]...)
+	for _, game := range []*Game{games[0], openTestGame(t, deal, prvs[0], games[0].State())} {
+		if got := game.Top("", len(testDeck)); !equalInts(got, want) {
+			t.Errorf("got order %v after cutting by %d, want %v", got, roll.Results[0], want)
+		}
+	}
+	if err := games[0].Cut("", roll); err == nil {
+		t.Errorf("the deck was cut twice with the same roll")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,8 +2,7 @@ package trustdraw
 
 import (
 	"bytes"
-	"crypto/ed25519"
-	"crypto/rsa"
+	"crypto"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -11,17 +10,82 @@ import (
 
 // Deal shuffles a set of 'cards', writing the deal file to the given deck io.Writer.
 // It will contain all the information needed for the players to draw cards as part
-// of a turn-based game without needing any further trust.
-func Deal(deck io.Writer, cards []string, dealerPrv ed25519.PrivateKey, playerPubs ...*rsa.PublicKey) error {
-	if err := validateDealArgs(cards, playerPubs); err != nil {
+// of a turn-based game without needing any further trust. The dealer's and players' keys can be RSA or Ed25519 keys.
+func Deal(deck io.Writer, cards []string, dealerPrv crypto.Signer, playerPubs ...crypto.PublicKey) error {
+	players := make([]Player, len(playerPubs))
+	for i, pub := range playerPubs {
+		players[i].PublicKey = pub
+	}
+	return DealPlayers(deck, cards, dealerPrv, players...)
+}
+
+// DealPlayers deals as Deal does, to players who can be given names. The deal file's roster lists each player's
+// name and key fingerprint, in player number order.
+//
+// Observers (with RoleObserver or RoleGod) can be dealt in too, after the players. Their key stacks can't allow draws,
+// only check the card keys that the players reveal.
+func DealPlayers(deck io.Writer, cards []string, dealerPrv crypto.Signer, roster ...Player) error {
+	return DealWith(deck, cards, dealerPrv, DealOptions{}, roster...)
+}
+
+// DealOptions are the optional extras a deal can be made with.
+type DealOptions struct {
+	// Escrow, if set, Shamir-splits each player's key stack amongst the other players, so that this many of them can
+	// recover the key shares of a player who leaves the game, and carry on drawing (see Game.Recover). It must be at
+	// least 2, and fewer than the number of players, so escrow needs 3 or more players. Any Escrow players who collude
+	// can recover every other player's key shares, and so read every hand.
+	Escrow int
+	// Piles are dealt alongside the main deck of cards, each shuffled on its own (see Pile).
+	Piles []Pile
+}
+
+// DealWith deals as DealPlayers does, with the given options.
+func DealWith(deck io.Writer, cards []string, dealerPrv crypto.Signer, opts DealOptions, roster ...Player) error {
+	if err := validateRoster(roster); err != nil {
+		return err
+	}
+	players := 0
+	playerPubs := make([]crypto.PublicKey, len(roster))
+	for i, player := range roster {
+		playerPubs[i] = player.PublicKey
+		if player.Role == RolePlayer {
+			players++
+		}
+	}
+	if err := validatePiles(opts.Piles); err != nil {
+		return err
+	}
+	shuffle(cards)
+	for _, pile := range opts.Piles {
+		pileCards := append([]string(nil), pile.Cards...)
+		shuffle(pileCards)
+		cards = append(cards[:len(cards):len(cards)], pileCards...)
+	}
+	if err := validateDealArgs(cards, playerPubs[:players]); err != nil {
+		return err
+	}
+	for i, pub := range playerPubs[players:] {
+		if err := checkKey(pub, fmt.Sprintf("observer %d's", players+i+1)); err != nil {
+			return err
+		}
+	}
+	if err := checkKey(dealerPrv.Public(), "the dealer's"); err != nil {
 		return err
 	}
+	// A threshold of 1 would give every player every other player's whole key stack.
+	if opts.Escrow != 0 && players < 3 {
+		return fmt.Errorf("escrow needs 3 or more players")
+	}
+	if opts.Escrow < 0 || opts.Escrow == 1 || opts.Escrow >= players {
+		return fmt.Errorf("the escrow threshold must be between 2 and %d", players-1)
+	}
 
 	deckData := make([][]byte, len(cards))
 	allPlayerData := make([][]byte, len(playerPubs))
-	players := len(playerPubs)
-	allCardKeys := make([][][]byte, players)
-	shuffle(cards)
+	allCardKeys := make([][][]byte, len(playerPubs))
+	for p := range allCardKeys {
+		allCardKeys[p] = make([][]byte, len(cards))
+	}
 
 	for i, card := range cards {
 		cardKeys, blk, err := generateCardKeys(players)
@@ -31,11 +95,22 @@ func Deal(deck io.Writer, cards []string, dealerPrv ed25519.PrivateKey, playerPu
 
 		deckData[i] = encryptCard(card, blk)
 		for p, key := range cardKeys {
-			if i == 0 {
-				allCardKeys[p] = make([][]byte, len(cards))
-			}
 			allCardKeys[p][i] = key
 		}
+		check := observerCheck(xor(cardKeys...))
+		for o := players; o < len(playerPubs); o++ {
+			allCardKeys[o][i] = check
+		}
+	}
+
+	if opts.Escrow > 0 {
+		escrowed, err := escrowStacks(allCardKeys[:players], opts.Escrow)
+		if err != nil {
+			return fmt.Errorf("unable to escrow the card keys: %w", err)
+		}
+		for p, extra := range escrowed {
+			allCardKeys[p] = append(allCardKeys[p], extra...)
+		}
 	}
 
 	for p, cardKeys := range allCardKeys {
@@ -49,8 +124,24 @@ func Deal(deck io.Writer, cards []string, dealerPrv ed25519.PrivateKey, playerPu
 	var sigBytes bytes.Buffer
 	writer := io.MultiWriter(&sigBytes, deck)
 
-	if _, err := fmt.Fprintf(writer, "TrustDraw/v%s\n\n", Version); err != nil {
-		return fmt.Errorf("unable to write the deck to the deal file: %w", err)
+	if _, err := fmt.Fprintf(writer, "TrustDraw/v%s\n", Version); err != nil {
+		return fmt.Errorf("unable to write the header to the deal file: %w", err)
+	}
+	if err := writeRoster(writer, roster); err != nil {
+		return fmt.Errorf("unable to write the roster to the deal file: %w", err)
+	}
+	if opts.Escrow > 0 {
+		if _, err := fmt.Fprintf(writer, "escrow %d\n", opts.Escrow); err != nil {
+			return fmt.Errorf("unable to write the header to the deal file: %w", err)
+		}
+	}
+	for _, pile := range opts.Piles {
+		if _, err := fmt.Fprintf(writer, "pile %s %d\n", pile.Name, len(pile.Cards)); err != nil {
+			return fmt.Errorf("unable to write the header to the deal file: %w", err)
+		}
+	}
+	if _, err := fmt.Fprintln(writer); err != nil {
+		return fmt.Errorf("unable to write the header to the deal file: %w", err)
 	}
 
 	for _, card := range deckData {
@@ -70,7 +161,10 @@ func Deal(deck io.Writer, cards []string, dealerPrv ed25519.PrivateKey, playerPu
 	}
 
 	// Write the signature to the deck file
-	sig := ed25519.Sign(dealerPrv, sigBytes.Bytes())
+	sig, err := signDeal(dealerPrv, sigBytes.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to sign the deal file: %w", err)
+	}
 	if _, err := fmt.Fprintf(deck, "\n%s", base64.RawStdEncoding.EncodeToString(sig)); err != nil {
 		return fmt.Errorf("unable to write the signature to the deck file: %w", err)
 	}
@@ -78,7 +172,7 @@ func Deal(deck io.Writer, cards []string, dealerPrv ed25519.PrivateKey, playerPu
 	return nil
 }
 
-func validateDealArgs(cards []string, playerPubs []*rsa.PublicKey) error {
+func validateDealArgs(cards []string, playerPubs []crypto.PublicKey) error {
 	if len(cards) > maxCards {
 		return fmt.Errorf("too many cards, max is %d", maxCards)
 	}
@@ -90,15 +184,13 @@ func validateDealArgs(cards []string, playerPubs []*rsa.PublicKey) error {
 	if len(playerPubs) < 2 {
 		return fmt.Errorf("two or more player keys are needed")
 	}
-	if len(playerPubs) > maxPlayers {
-		return fmt.Errorf("no more than %d players are allowed", maxPlayers)
+	if len(playerPubs) > MaxPlayers {
+		return fmt.Errorf("no more than %d players are allowed", MaxPlayers)
 	}
 
 	for i, pub := range playerPubs {
-		if pub.Size() < rsaBits/8 {
-			return fmt.Errorf(
-				"player %d's key is too small (%d bits), must be at least %d bits",
-				i+1, pub.Size()*8, rsaBits)
+		if err := checkKey(pub, fmt.Sprintf("player %d's", i+1)); err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,146 @@
+// Package dealer runs a dealer as a service: players join a lobby with their public keys, and once it is full
+// the dealer shuffles, signs and publishes the deal file.
+package dealer
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/cards"
+)
+
+var (
+	ErrLobbyNotFound = errors.New("lobby not found")
+	ErrLobbyFull     = errors.New("lobby is full")
+	ErrNotDealt      = errors.New("lobby has not been dealt yet")
+	ErrAlreadyJoined = errors.New("that key has already joined the lobby")
+)
+
+// Lobby gathers players for a game, and holds its deal once every seat is taken.
+type Lobby struct {
+	ID      string
+	Deck    string
+	Seats   int
+	Players []crypto.PublicKey
+	// Round counts the deals made for this lobby; it is 0 until the lobby is full, then 1 for the first deal.
+	Round int
+	Deal  []byte
+
+	cards      []string
+	reshuffles map[trustdraw.PlayerNumber][]string
+	// mu is held while the lobby is being read or changed, including while it's dealt.
+	mu sync.Mutex
+}
+
+func newLobby(id, deck string, seats int) (*Lobby, error) {
+	deckCards, ok := cards.LoadInBuilt(deck)
+	if !ok {
+		return nil, fmt.Errorf("unknown deck: %s", deck)
+	}
+	if seats < 2 {
+		return nil, fmt.Errorf("two or more players are needed")
+	}
+	if seats > trustdraw.MaxPlayers {
+		return nil, fmt.Errorf("no more than %d players are allowed", trustdraw.MaxPlayers)
+	}
+
+	return &Lobby{ID: id, Deck: deck, Seats: seats, cards: deckCards}, nil
+}
+
+// join seats a player, dealing the game if they took the last seat.
+func (l *Lobby) join(pub crypto.PublicKey, dealerPrv crypto.Signer) (trustdraw.PlayerNumber, error) {
+	if len(l.Players) == l.Seats {
+		return 0, ErrLobbyFull
+	}
+	for _, existing := range l.Players {
+		if existing.(interface{ Equal(crypto.PublicKey) bool }).Equal(pub) {
+			return 0, ErrAlreadyJoined
+		}
+	}
+
+	l.Players = append(l.Players, pub)
+	player := trustdraw.PlayerNumber(len(l.Players))
+
+	if len(l.Players) == l.Seats {
+		if err := l.deal(l.cards, dealerPrv); err != nil {
+			l.Players = l.Players[:len(l.Players)-1]
+			return 0, err
+		}
+	}
+
+	return player, nil
+}
+
+// requestReshuffle records a player's request for a fresh deal of the given cards. Once every player
+// has asked for a reshuffle of the same cards, the dealer deals them again for the same players.
+//
+// This is how cards are returned to the deck: the players agree on which cards (eg. those left undrawn,
+// plus any returned) make up the new deck.
+func (l *Lobby) requestReshuffle(player trustdraw.PlayerNumber, reshuffleCards []string, dealerPrv crypto.Signer) (bool, error) {
+	if l.Deal == nil {
+		return false, ErrNotDealt
+	}
+	if len(reshuffleCards) == 0 {
+		reshuffleCards = l.cards
+	}
+
+	if l.reshuffles == nil {
+		l.reshuffles = make(map[trustdraw.PlayerNumber][]string)
+	}
+	l.reshuffles[player] = reshuffleCards
+
+	for p := 1; p <= l.Seats; p++ {
+		requested, ok := l.reshuffles[trustdraw.PlayerNumber(p)]
+		if !ok || !sameCards(requested, reshuffleCards) {
+			return false, nil
+		}
+	}
+
+	if err := l.deal(reshuffleCards, dealerPrv); err != nil {
+		return false, err
+	}
+	l.reshuffles = nil
+	return true, nil
+}
+
+func (l *Lobby) deal(dealCards []string, dealerPrv crypto.Signer) error {
+	shuffled := append([]string(nil), dealCards...)
+
+	var deal bytes.Buffer
+	if err := trustdraw.Deal(&deal, shuffled, dealerPrv, l.Players...); err != nil {
+		return fmt.Errorf("unable to deal: %w", err)
+	}
+
+	l.Deal = deal.Bytes()
+	l.Round++
+	return nil
+}
+
+func (l *Lobby) playerKey(player trustdraw.PlayerNumber) (crypto.PublicKey, bool) {
+	if player < 1 || int(player) > len(l.Players) {
+		return nil, false
+	}
+	return l.Players[player-1], true
+}
+
+// sameCards checks whether two lists hold the same cards, in any order.
+func sameCards(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	a, b = append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
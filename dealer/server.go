@@ -0,0 +1,275 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto"
+	crand "crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/jphastings/trustdraw/internal/playerauth"
+)
+
+// maxBodySize limits the size of requests the dealer will accept.
+const maxBodySize = 1 << 20
+
+// Server is an http.Handler that runs lobbies and deals their games, all signed with one dealer key.
+//
+//	GET  /key                      The dealer's PEM encoded public key, for verifying deals
+//	POST /lobbies                  Open a lobby (CreateLobbyRequest)
+//	GET  /lobbies/{id}             The lobby's status (LobbyStatus)
+//	POST /lobbies/{id}/players     Join the lobby (JoinRequest, signed with the joining key)
+//	GET  /lobbies/{id}/deal        The lobby's latest deal file, once it is full
+//	POST /lobbies/{id}/reshuffle   Ask for a fresh deal (ReshuffleRequest, players only)
+type Server struct {
+	dealerPrv crypto.Signer
+
+	// mu guards the lobbies map; each lobby guards itself.
+	mu      sync.Mutex
+	lobbies map[string]*Lobby
+}
+
+// NewServer creates a dealer Server that signs its deals with the given key.
+func NewServer(dealerPrv crypto.Signer) *Server {
+	return &Server{
+		dealerPrv: dealerPrv,
+		lobbies:   make(map[string]*Lobby),
+	}
+}
+
+// CreateLobbyRequest is the body of a request to open a new lobby.
+type CreateLobbyRequest struct {
+	// Deck is the name of one of the in-built decks, eg. "scrabble-en".
+	Deck    string `json:"deck"`
+	Players int    `json:"players"`
+}
+
+// JoinRequest is the body of a request to join a lobby. The request must be signed (see playerauth.Sign) with the
+// private key for PublicKey, as player 0, which proves the player holds it. The signature covers the lobby's ID, so
+// it can't be used to join a different lobby.
+type JoinRequest struct {
+	// PublicKey is the player's PEM or OpenSSH encoded RSA or Ed25519 public key.
+	PublicKey string `json:"publicKey"`
+}
+
+// JoinResponse is the body of the response to JoinRequest.
+type JoinResponse struct {
+	Player trustdraw.PlayerNumber `json:"player"`
+}
+
+// ReshuffleRequest is the body of a request for a fresh deal for the same players.
+type ReshuffleRequest struct {
+	// Cards to deal; the lobby's whole deck if empty. Every player must ask for the same cards.
+	Cards []string `json:"cards,omitempty"`
+}
+
+// ReshuffleResponse is the body of the response to ReshuffleRequest.
+type ReshuffleResponse struct {
+	// Dealt is true when this request was the last one needed, and a new deal has been made.
+	Dealt bool `json:"dealt"`
+	Round int  `json:"round"`
+}
+
+// LobbyStatus describes a lobby.
+type LobbyStatus struct {
+	ID      string `json:"id"`
+	Deck    string `json:"deck"`
+	Players int    `json:"players"`
+	Joined  int    `json:"joined"`
+	Round   int    `json:"round"`
+	GameID  string `json:"gameId,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "key" && r.Method == http.MethodGet:
+		s.publicKey(w)
+	case len(parts) == 1 && parts[0] == "lobbies" && r.Method == http.MethodPost:
+		s.createLobby(w, r)
+	case len(parts) >= 2 && len(parts) <= 3 && parts[0] == "lobbies":
+		s.mu.Lock()
+		lobby, ok := s.lobbies[parts[1]]
+		s.mu.Unlock()
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{ErrLobbyNotFound.Error()})
+			return
+		}
+
+		// Each lobby has its own lock, so dealing one lobby's game doesn't hold up requests for the others.
+		lobby.mu.Lock()
+		defer lobby.mu.Unlock()
+
+		action := ""
+		if len(parts) == 3 {
+			action = parts[2]
+		}
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			writeJSON(w, http.StatusOK, status(lobby))
+		case action == "players" && r.Method == http.MethodPost:
+			s.join(w, r, lobby)
+		case action == "deal" && r.Method == http.MethodGet:
+			if lobby.Deal == nil {
+				writeJSON(w, http.StatusConflict, errorResponse{ErrNotDealt.Error()})
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write(lobby.Deal)
+		case action == "reshuffle" && r.Method == http.MethodPost:
+			s.reshuffle(w, r, lobby)
+		default:
+			writeJSON(w, http.StatusNotFound, errorResponse{"not found"})
+		}
+	default:
+		writeJSON(w, http.StatusNotFound, errorResponse{"not found"})
+	}
+}
+
+func (s *Server) publicKey(w http.ResponseWriter) {
+	der, err := x509.MarshalPKIXPublicKey(s.dealerPrv.Public())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	_ = pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func (s *Server) createLobby(w http.ResponseWriter, r *http.Request) {
+	var req CreateLobbyRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"request body is not valid JSON"})
+		return
+	}
+
+	id, err := lobbyID()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+		return
+	}
+
+	lobby, err := newLobby(id, req.Deck, req.Players)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	s.lobbies[id] = lobby
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/lobbies/"+id)
+	writeJSON(w, http.StatusCreated, status(lobby))
+}
+
+func (s *Server) join(w http.ResponseWriter, r *http.Request, lobby *Lobby) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"unable to read request body"})
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req JoinRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"request body is not valid JSON"})
+		return
+	}
+
+	pub, err := cmdhelpers.ParsePlayerPublicKey([]byte(req.PublicKey), "publicKey")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	// The joining player doesn't have a number yet, so the request is checked against the key it's joining with.
+	keyFor := func(trustdraw.PlayerNumber) (crypto.PublicKey, bool) { return pub, true }
+	if _, err := playerauth.Verify(r, keyFor); err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{"join request was not signed with publicKey"})
+		return
+	}
+
+	player, err := lobby.join(pub, s.dealerPrv)
+	switch {
+	case errors.Is(err, ErrLobbyFull), errors.Is(err, ErrAlreadyJoined):
+		writeJSON(w, http.StatusConflict, errorResponse{err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+	default:
+		writeJSON(w, http.StatusCreated, JoinResponse{Player: player})
+	}
+}
+
+func (s *Server) reshuffle(w http.ResponseWriter, r *http.Request, lobby *Lobby) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	player, err := playerauth.Verify(r, lobby.playerKey)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{err.Error()})
+		return
+	}
+
+	var req ReshuffleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"request body is not valid JSON"})
+		return
+	}
+
+	dealt, err := lobby.requestReshuffle(player, req.Cards, s.dealerPrv)
+	switch {
+	case errors.Is(err, ErrNotDealt):
+		writeJSON(w, http.StatusConflict, errorResponse{err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, ReshuffleResponse{Dealt: dealt, Round: lobby.Round})
+	}
+}
+
+func status(lobby *Lobby) LobbyStatus {
+	st := LobbyStatus{
+		ID:      lobby.ID,
+		Deck:    lobby.Deck,
+		Players: lobby.Seats,
+		Joined:  len(lobby.Players),
+		Round:   lobby.Round,
+	}
+	if lobby.Deal != nil {
+		st.GameID, _ = trustdraw.DealID(strings.NewReader(string(lobby.Deal)))
+	}
+	return st
+}
+
+func lobbyID() (string, error) {
+	id := make([]byte, 9)
+	if _, err := crand.Read(id); err != nil {
+		return "", fmt.Errorf("unable to make a lobby ID: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("unable to write response: %v", err)
+	}
+}
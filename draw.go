@@ -5,32 +5,139 @@ import (
 	"fmt"
 )
 
-var ErrNoCardsLeft = errors.New("no cards left to draw")
+var (
+	ErrNoCardsLeft = errors.New("no cards left to draw")
+	// ErrObserving is returned when an observer tries to allow a draw or draw a card.
+	ErrObserving = errors.New("observers can't allow draws or draw cards")
+	// ErrReleased is returned when a player tries to allow a draw or draw a card after releasing their key stack.
+	ErrReleased = errors.New("you have released your key stack, so the game is over")
+)
+
+// DrawSelector picks which card in the deck an allowKey is made for. Every player must use the same one.
+type DrawSelector struct {
+	kind   drawSelectorKind
+	pile   string
+	cardID int
+	roll   DiceRoll
+}
+
+type drawSelectorKind int
+
+const (
+	drawTop drawSelectorKind = iota
+	drawBottom
+	drawRandom
+	drawCard
+)
+
+var (
+	// DrawTop picks the next card to be drawn, as AllowDraw does.
+	DrawTop = DrawSelector{kind: drawTop}
+	// DrawBottom picks the last card to be drawn.
+	DrawBottom = DrawSelector{kind: drawBottom}
+)
+
+// DrawRandom picks a card from anywhere in the deck, using a roll every player took part in. The roll must be of one
+// die with as many sides as there are cards left in the deck (or pile), and each roll can only pick one card (or cut
+// the deck once, see Game.Cut).
+func DrawRandom(roll DiceRoll) DrawSelector {
+	return DrawSelector{kind: drawRandom, roll: roll}
+}
+
+// DrawCard picks the card with the given ID, which must still be in the main deck (or the pile given with In).
+func DrawCard(cardID int) DrawSelector {
+	return DrawSelector{kind: drawCard, cardID: cardID}
+}
+
+// In picks from the named pile (see Pile), rather than the main deck.
+func (s DrawSelector) In(pile string) DrawSelector {
+	s.pile = pile
+	return s
+}
+
+// pick returns the ID of the card the selector picks from the given draw order.
+func (s DrawSelector) pick(order []int) (int, error) {
+	if len(order) == 0 {
+		return 0, ErrNoCardsLeft
+	}
+	switch s.kind {
+	case drawBottom:
+		return order[len(order)-1], nil
+	case drawRandom:
+		if s.roll.Count != 1 || s.roll.Sides != len(order) {
+			return 0, fmt.Errorf("a random draw needs a roll of 1d%d, not %dd%d", len(order), s.roll.Count, s.roll.Sides)
+		}
+		return order[s.roll.Results[0]-1], nil
+	case drawCard:
+		if containsAny(order, s.cardID) {
+			return s.cardID, nil
+		}
+		if s.pile != "" {
+			return 0, fmt.Errorf("card %d isn't in the %s pile", s.cardID+1, s.pile)
+		}
+		return 0, fmt.Errorf("card %d isn't in the deck", s.cardID+1)
+	default:
+		return order[0], nil
+	}
+}
 
 // AllowDraw retrieves the allowKey for that will allow the specified player to draw a card.
 // An allowKey contains 2 bytes of card ID, followed by 16 bytes of the card's AES key.
 func (g *Game) AllowDraw(intended PlayerNumber) (string, error) {
+	return g.AllowDrawFrom(intended, DrawTop)
+}
+
+// AllowDrawFrom retrieves the allowKey that will allow the specified player to draw the card the selector picks.
+func (g *Game) AllowDrawFrom(intended PlayerNumber, from DrawSelector) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
 	if intended < 1 || intended > PlayerNumber(g.Players) {
 		return "", fmt.Errorf("player %d is not in this game", intended)
 	}
+	if _, departed := g.recovered[intended]; departed {
+		return "", fmt.Errorf("%s has left the game", g.PlayerName(intended))
+	}
 
-	for cardID, secretCard := range g.keys {
-		if g.state[cardID] != 0 {
-			continue
+	if _, err := g.findPile(from.pile); err != nil {
+		return "", err
+	}
+	if from.kind == drawRandom {
+		roll, err := g.usableRoll(from.roll)
+		if err != nil {
+			return "", err
 		}
-
-		g.state[cardID] = intended
-		return toAllowKey(cardID, secretCard), nil
+		from.roll = roll
+	}
+	cardID, err := from.pick(g.drawOrder(from.pile))
+	if err != nil {
+		return "", err
 	}
 
-	return "", ErrNoCardsLeft
+	g.state[cardID] = intended
+	if from.kind == drawRandom {
+		g.usedRolls[from.roll.Number] = true
+	}
+	if err := g.Save(); err != nil {
+		g.state[cardID] = 0
+		if from.kind == drawRandom {
+			delete(g.usedRolls, from.roll.Number)
+		}
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return toAllowKey(cardID, g.keys[cardID]), nil
 }
 
 // Draw uses the allowKeys shared by other players to draw the relevant card.
 func (g *Game) Draw(allowKeys ...string) (card string, allowKey string, alreadyDrawn bool, error error) {
-	if len(allowKeys) != g.Players-1 {
-		return "", "", false, fmt.Errorf("wrong number of allowKeys (%d needed, %d given)", g.Players, len(allowKeys))
+	if err := g.canPlay(); err != nil {
+		return "", "", false, err
 	}
+	// The allowKeys of players who have left the game can be left out, as their recovered key shares are used instead.
+	if needed := g.Players - 1 - len(g.recovered); len(allowKeys) < needed || len(allowKeys) > g.Players-1 {
+		return "", "", false, fmt.Errorf("wrong number of allowKeys (%d needed, %d given)", needed, len(allowKeys))
+	}
+	allowKeys = g.withRecovered(allowKeys)
 	cardID, cardKey, err := g.allowKeysToCardKey(allowKeys)
 	if err != nil {
 		return "", "", false, fmt.Errorf("could not re-create card key: %w", err)
@@ -41,17 +148,53 @@ func (g *Game) Draw(allowKeys ...string) (card string, allowKey string, alreadyD
 		return "", "", false, fmt.Errorf("could not decrypt card: %w", err)
 	}
 
-	alreadyDrawn = g.state[cardID] != 0
+	owner := g.state[cardID]
+	if owner == burnt || owner == discarded {
+		return "", "", false, fmt.Errorf("card %d has been burnt or discarded", cardID+1)
+	}
+	drawn, wasDrawn := g.drawn[cardID]
+	alreadyDrawn = owner != 0
 	g.state[cardID] = g.playerNumber
+	g.drawn[cardID] = append([]string(nil), allowKeys...)
+	if err := g.Save(); err != nil {
+		g.state[cardID] = owner
+		if wasDrawn {
+			g.drawn[cardID] = drawn
+		} else {
+			delete(g.drawn, cardID)
+		}
+		return "", "", false, fmt.Errorf("could not save game state: %w", err)
+	}
 
 	return card, toAllowKey(cardID, g.keys[cardID]), alreadyDrawn, nil
 }
 
+// canPlay checks that this player can still allow draws and draw cards.
+func (g *Game) canPlay() error {
+	switch {
+	case g.role != RolePlayer:
+		return ErrObserving
+	case g.released:
+		return ErrReleased
+	default:
+		return nil
+	}
+}
+
+// VerifyDraw checks that the given allowKeys decrypt the card another player says they drew, and that this player's
+// state records the card as given to a player. A player who peeked at a card (see AllowPeek) holds the same allowKeys
+// for it as the player who draws it, so they only prove a card was drawn once it's left the deck.
+// Observers need every player's allowKey for the card (see HeldCard.AllowKeys). They don't see draws being allowed,
+// so can't tell a drawn card from a peeked one.
 func (g *Game) VerifyDraw(testCard string, allowKeys ...string) (bool, error) {
 	cardID, blk, err := g.allowKeysToCardKey(allowKeys)
 	if err != nil {
 		return false, fmt.Errorf("could not re-create card key: %w", err)
 	}
+	// Players seated after the card left the deck can't know who holds it, so take the drawer's word for it.
+	if g.role == RolePlayer && g.state[cardID] != notInDeck && g.GivenTo(allowKeys...) == 0 {
+		return false, nil
+	}
 
 	realCard, err := g.decryptCard(cardID, blk)
 	if err != nil {
@@ -60,3 +203,30 @@ func (g *Game) VerifyDraw(testCard string, allowKeys ...string) (bool, error) {
 
 	return testCard == realCard, nil
 }
+
+// GivenTo returns the player that this player's state records the card for the given allowKeys as being given to,
+// or 0 if it isn't recorded as given to anyone.
+func (g *Game) GivenTo(allowKeys ...string) PlayerNumber {
+	cardID, err := allowKeysCardID(allowKeys)
+	if err != nil || cardID >= len(g.state) {
+		return 0
+	}
+	if owner := g.state[cardID]; owner >= 1 && owner <= PlayerNumber(g.Players) {
+		return owner
+	}
+	return 0
+}
+
+// VerifyDrawBy checks that the given allowKeys decrypt the card the given player says they drew,
+// and that this player's state records the card as having been given to them.
+func (g *Game) VerifyDrawBy(player PlayerNumber, testCard string, allowKeys ...string) (bool, error) {
+	cardID, err := allowKeysCardID(allowKeys)
+	if err != nil {
+		return false, err
+	}
+	if cardID >= len(g.state) || g.state[cardID] != player {
+		return false, nil
+	}
+
+	return g.VerifyDraw(testCard, allowKeys...)
+}
@@ -0,0 +1,100 @@
+package trustdraw
+
+import "testing"
+
+// allowTestDraw has every other player allow the given player to draw the card the selector picks, and draws it.
+func allowTestDraw(t *testing.T, games []*Game, drawer PlayerNumber, from DrawSelector) (string, error) {
+	t.Helper()
+	var allowKeys []string
+	for _, game := range games {
+		if game.PlayerNumber() == drawer {
+			continue
+		}
+		allowKey, err := game.AllowDrawFrom(drawer, from)
+		if err != nil {
+			return "", err
+		}
+		allowKeys = append(allowKeys, allowKey)
+	}
+	card, _, _, err := games[drawer-1].Draw(allowKeys...)
+	return card, err
+}
+
+// first picks the first card in the draw order.
+func first(order []int, _ DrawSelector) int {
+	return order[0]
+}
+
+func TestDrawSelectors(t *testing.T) {
+	tilesPile := Pile{Name: "tiles", Cards: []string{"A", "B", "C"}}
+	tests := []struct {
+		name string
+		from func(games []*Game) DrawSelector
+		// want picks the card that should be drawn from the draw order, or is nil if the draw should fail.
+		want func(order []int, from DrawSelector) int
+	}{
+		{"top", func([]*Game) DrawSelector { return DrawTop }, first},
+		{"bottom", func([]*Game) DrawSelector { return DrawBottom }, func(order []int, _ DrawSelector) int {
+			return order[len(order)-1]
+		}},
+		{"a card in the deck", func([]*Game) DrawSelector { return DrawCard(3) }, func([]int, DrawSelector) int {
+			return 3
+		}},
+		{"a card in a pile", func([]*Game) DrawSelector { return DrawCard(len(testDeck)).In("tiles") }, func([]int, DrawSelector) int {
+			return len(testDeck)
+		}},
+		{"a pile's card from the deck", func([]*Game) DrawSelector { return DrawCard(len(testDeck)) }, nil},
+		{"a deck card from a pile", func([]*Game) DrawSelector { return DrawCard(3).In("tiles") }, nil},
+		{"a card from a missing pile", func([]*Game) DrawSelector { return DrawTop.In("nope") }, nil},
+		{"a random card", func(games []*Game) DrawSelector {
+			_, _, roll := testRoll(t, games, len(testDeck), 1)
+			return DrawRandom(roll)
+		}, func(order []int, from DrawSelector) int {
+			return order[from.roll.Results[0]-1]
+		}},
+		{"a random card with the wrong dice", func(games []*Game) DrawSelector {
+			_, _, roll := testRoll(t, games, 6, 1)
+			return DrawRandom(roll)
+		}, nil},
+		{"a random card with a made up roll", func([]*Game) DrawSelector {
+			return DrawRandom(DiceRoll{Number: 1, Sides: len(testDeck), Count: 1, Results: []int{1}})
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, _ := testGames(t, 2, DealOptions{Piles: []Pile{tilesPile}})
+			from := tt.from(games)
+			order := games[0].drawOrder(from.pile)
+
+			card, err := allowTestDraw(t, games, 1, from)
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("drew %s", card)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not draw: %v", err)
+			}
+			want := tt.want(order, from)
+			if hand, _ := games[0].Hand(); len(hand) != 1 || hand[0].CardID != want {
+				t.Errorf("drew %v, want card %d", hand, want+1)
+			}
+		})
+	}
+}
+
+func TestRollsAreUsedOnce(t *testing.T) {
+	games, _ := testGames(t, 2, DealOptions{})
+	_, _, roll := testRoll(t, games, len(testDeck), 1)
+	if _, err := allowTestDraw(t, games, 1, DrawRandom(roll)); err != nil {
+		t.Fatalf("could not draw: %v", err)
+	}
+
+	if _, err := games[0].AllowDrawFrom(2, DrawRandom(roll)); err == nil {
+		t.Errorf("the same roll picked a second card")
+	}
+	if err := games[0].Cut("", roll); err == nil {
+		t.Errorf("the same roll picked a card and cut the deck")
+	}
+}
@@ -0,0 +1,278 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/jphastings/trustdraw/internal/shamir"
+)
+
+// escrowedStack is one player's escrow shares of another player's key stack, along with the values that check each
+// key share once it has been recovered.
+type escrowedStack struct {
+	shares [][]byte
+	checks [][]byte
+}
+
+// escrowCheck derives the value that checks a key share recovered from escrow shares, without revealing it.
+func escrowCheck(keyShare []byte) []byte {
+	sum := sha256.Sum256(append([]byte("TrustDraw escrow check\x00"), keyShare...))
+	return sum[:aesCipherSize]
+}
+
+// escrowStacks Shamir-splits each player's key stack amongst the other players, so that any threshold of them can
+// recover it. It returns what to add to the end of each player's key stack: for every other player in turn, their
+// escrow shares of that player's key stack, then the values that check it.
+func escrowStacks(stacks [][][]byte, threshold int) ([][][]byte, error) {
+	extra := make([][][]byte, len(stacks))
+	for p, stack := range stacks {
+		var holders []int
+		var xs []byte
+		for q := range stacks {
+			if q != p {
+				holders = append(holders, q)
+				xs = append(xs, byte(q+1))
+			}
+		}
+
+		shares := make([][][]byte, len(holders))
+		checks := make([][]byte, len(stack))
+		for cardID, keyShare := range stack {
+			split, err := shamir.Split(keyShare, xs, threshold)
+			if err != nil {
+				return nil, err
+			}
+			for h := range holders {
+				shares[h] = append(shares[h], split[h])
+			}
+			checks[cardID] = escrowCheck(keyShare)
+		}
+
+		for h, q := range holders {
+			extra[q] = append(extra[q], shares[h]...)
+			extra[q] = append(extra[q], checks...)
+		}
+	}
+	return extra, nil
+}
+
+// loadEscrow reads this player's escrow shares of the other players' key stacks, from after their own key stack.
+func (g *Game) loadEscrow(keys [][]byte) error {
+	cardCount := len(g.cards)
+	if len(keys) < 2*cardCount*(g.Players-1) {
+		return fmt.Errorf("the deal file is missing your escrow shares")
+	}
+
+	g.escrowed = make(map[PlayerNumber]escrowedStack)
+	for p := 1; p <= g.Players; p++ {
+		if PlayerNumber(p) == g.playerNumber {
+			continue
+		}
+		g.escrowed[PlayerNumber(p)] = escrowedStack{shares: keys[:cardCount], checks: keys[cardCount : 2*cardCount]}
+		keys = keys[2*cardCount:]
+	}
+	return nil
+}
+
+// Escrow returns the number of players needed to recover the key shares of a player who has left the game,
+// or 0 if the game was dealt without escrow.
+func (g *Game) Escrow() int {
+	return g.escrow
+}
+
+// Departed lists the players who have left the game, whose key shares have been recovered.
+func (g *Game) Departed() []PlayerNumber {
+	return sortedPlayers(g.recovered)
+}
+
+// withRecovered adds the recovered key shares of players who have left the game to the allowKeys for a card, as
+// allowKeys, so the drawer can prove the card to observers. allowKeys already given are left as they are.
+func (g *Game) withRecovered(allowKeys []string) []string {
+	cardID, err := allowKeysCardID(allowKeys)
+	if err != nil {
+		return allowKeys
+	}
+
+	withRecovered := append([]string(nil), allowKeys...)
+	for _, player := range sortedPlayers(g.recovered) {
+		share, ok := g.recovered[player][cardID]
+		if !ok {
+			continue
+		}
+		if allowKey := toAllowKey(cardID, share); !containsString(allowKeys, allowKey) {
+			withRecovered = append(withRecovered, allowKey)
+		}
+	}
+	return withRecovered
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RecoveryShare makes this player's share towards recovering the key shares of a player who has left the game, for
+// the cards still in the deck. Once Escrow players' recovery shares have been
+// collected, any of the remaining players can Recover the departed player's key shares, so draws can carry on.
+func (g *Game) RecoveryShare(departed PlayerNumber) (string, error) {
+	if err := g.checkRecovery(departed); err != nil {
+		return "", err
+	}
+
+	data := []byte{byte(g.playerNumber), byte(departed)}
+	for cardID, owner := range g.state {
+		if owner != 0 {
+			continue
+		}
+		data = binary.LittleEndian.AppendUint16(data, uint16(cardID))
+		data = append(data, g.escrowed[departed].shares[cardID]...)
+	}
+	return base64.RawStdEncoding.EncodeToString(data), nil
+}
+
+// Recover puts together the key shares of a player who has left the game from the other players' recovery shares
+// (this player's own is included automatically), checking every recovered key share against the deal. It covers the
+// cards still in the deck, so they can be drawn without the departed player, who can't be allowed to draw afterwards;
+// the recovery is recorded in this player's state.
+func (g *Game) Recover(departed PlayerNumber, recoveryShares ...string) error {
+	if err := g.checkRecovery(departed); err != nil {
+		return err
+	}
+	escrowed := g.escrowed[departed]
+
+	points := make(map[int]map[PlayerNumber][]byte)
+	for cardID, owner := range g.state {
+		if owner == 0 {
+			points[cardID] = map[PlayerNumber][]byte{g.playerNumber: escrowed.shares[cardID]}
+		}
+	}
+
+	given := make(map[PlayerNumber]bool)
+	for _, recoveryShare := range recoveryShares {
+		from, shares, err := g.parseRecoveryShare(recoveryShare, departed)
+		if err != nil {
+			return err
+		}
+		if from == g.playerNumber {
+			continue
+		}
+		if given[from] {
+			return fmt.Errorf("more than one recovery share was given from %s", g.PlayerName(from))
+		}
+		given[from] = true
+
+		for cardID, share := range shares {
+			if _, ok := points[cardID]; ok {
+				points[cardID][from] = share
+			}
+		}
+	}
+
+	recovered := make(map[int][]byte)
+	for _, cardID := range sortedCardIDs(points) {
+		cardPoints := points[cardID]
+		if len(cardPoints) < g.escrow {
+			return fmt.Errorf("only %d of the %d recovery shares needed cover card %d", len(cardPoints), g.escrow, cardID+1)
+		}
+		keyShare, culprit := recoverKeyShare(cardPoints, escrowed.checks[cardID], g.escrow)
+		if keyShare == nil && culprit != 0 {
+			return fmt.Errorf("could not recover card %d: %s's recovery share doesn't match the deal", cardID+1, g.PlayerName(culprit))
+		} else if keyShare == nil {
+			return fmt.Errorf("could not recover card %d: the recovery shares don't match the deal", cardID+1)
+		}
+		recovered[cardID] = keyShare
+	}
+
+	previous, wasRecovered := g.recovered[departed]
+	g.recovered[departed] = recovered
+	if err := g.Save(); err != nil {
+		if wasRecovered {
+			g.recovered[departed] = previous
+		} else {
+			delete(g.recovered, departed)
+		}
+		return fmt.Errorf("could not save game state: %w", err)
+	}
+	return nil
+}
+
+// checkRecovery checks that this player can help recover the key shares of the departed player.
+func (g *Game) checkRecovery(departed PlayerNumber) error {
+	switch {
+	case g.escrow == 0:
+		return fmt.Errorf("this game was dealt without escrow, so key shares can't be recovered")
+	case g.role != RolePlayer:
+		return ErrObserving
+	case departed < 1 || int(departed) > g.Players:
+		return fmt.Errorf("player %d is not in this game", departed)
+	case departed == g.playerNumber:
+		return fmt.Errorf("you can't recover your own key shares")
+	default:
+		return nil
+	}
+}
+
+// parseRecoveryShare reads a recovery share made by RecoveryShare, checking it is for the departed player.
+func (g *Game) parseRecoveryShare(recoveryShare string, departed PlayerNumber) (PlayerNumber, map[int][]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(recoveryShare)
+	if err != nil || len(data) < 2 || (len(data)-2)%(2+aesCipherSize) != 0 {
+		return 0, nil, fmt.Errorf("recovery share is badly formed")
+	}
+
+	from, forPlayer := PlayerNumber(data[0]), PlayerNumber(data[1])
+	if from < 1 || int(from) > g.Players || from == departed {
+		return 0, nil, fmt.Errorf("recovery share is from someone who can't give one")
+	}
+	if forPlayer != departed {
+		return 0, nil, fmt.Errorf("recovery share from %s is for %s, not %s", g.PlayerName(from), g.PlayerName(forPlayer), g.PlayerName(departed))
+	}
+
+	shares := make(map[int][]byte)
+	for rest := data[2:]; len(rest) > 0; rest = rest[2+aesCipherSize:] {
+		cardID := int(binary.LittleEndian.Uint16(rest[:2]))
+		if cardID >= len(g.cards) {
+			return 0, nil, fmt.Errorf("recovery share from %s is for a card that isn't in this deck", g.PlayerName(from))
+		}
+		shares[cardID] = rest[2 : 2+aesCipherSize]
+	}
+	return from, shares, nil
+}
+
+// recoverKeyShare combines the escrow shares each player gave for one card, checking the result. If it doesn't check
+// out, and there are more shares than needed, it returns the player whose share is wrong, if it can find them.
+func recoverKeyShare(points map[PlayerNumber][]byte, check []byte, threshold int) ([]byte, PlayerNumber) {
+	combine := func(leaveOut PlayerNumber) []byte {
+		var xs []byte
+		var ys [][]byte
+		for _, player := range sortedPlayers(points) {
+			if player != leaveOut {
+				xs = append(xs, byte(player))
+				ys = append(ys, points[player])
+			}
+		}
+		keyShare, err := shamir.Combine(xs, ys)
+		if err != nil || !bytes.Equal(escrowCheck(keyShare), check) {
+			return nil
+		}
+		return keyShare
+	}
+
+	if keyShare := combine(0); keyShare != nil {
+		return keyShare, 0
+	}
+	if len(points) > threshold {
+		for player := range points {
+			if combine(player) != nil {
+				return nil, player
+			}
+		}
+	}
+	return nil, 0
+}
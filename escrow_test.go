@@ -0,0 +1,131 @@
+package trustdraw
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestEscrowRecovery(t *testing.T) {
+	games, _ := testGames(t, 4, DealOptions{Escrow: 2})
+	testDraw(t, games, 2)
+
+	// Player 4 leaves, and players 1 and 3 recover their key shares.
+	share1, err := games[0].RecoveryShare(4)
+	if err != nil {
+		t.Fatalf("could not make a recovery share: %v", err)
+	}
+	share3, err := games[2].RecoveryShare(4)
+	if err != nil {
+		t.Fatalf("could not make a recovery share: %v", err)
+	}
+	for i, shares := range [][]string{{share3}, {share1}, {share1}} {
+		if err := games[i].Recover(4, shares...); err != nil {
+			t.Fatalf("player %d could not recover: %v", i+1, err)
+		}
+	}
+
+	// Draws carry on without player 4's allowKeys, and player 4 can't be allowed to draw.
+	card, allowKey, allowKeys := testDraw(t, games[:3], 1)
+	if len(allowKeys) != 2 {
+		t.Errorf("got %d allowKeys, want 2", len(allowKeys))
+	}
+	// Player 2 needs the drawer's allowKey and player 3's, as player 4's share was recovered.
+	if ok, err := games[1].VerifyDrawBy(1, card, allowKey, allowKeys[1]); err != nil || !ok {
+		t.Errorf("the draw couldn't be verified (%v)", err)
+	}
+	if _, err := games[0].AllowDraw(4); err == nil {
+		t.Errorf("a departed player was allowed to draw")
+	}
+	if got := games[0].Departed(); len(got) != 1 || got[0] != 4 {
+		t.Errorf("got departed players %v, want [4]", got)
+	}
+}
+
+func TestEscrowRecoveryTampering(t *testing.T) {
+	games, _ := testGames(t, 4, DealOptions{Escrow: 2})
+	drawn, _, _ := testDraw(t, games, 2)
+	share1, err := games[0].RecoveryShare(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	share2, err := games[1].RecoveryShare(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forPlayer3, err := games[0].RecoveryShare(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := base64.RawStdEncoding.DecodeString(share1)
+	data[len(data)-1] ^= 1
+	changed := base64.RawStdEncoding.EncodeToString(data)
+	data, _ = base64.RawStdEncoding.DecodeString(share1)
+	data[0] = 2
+	relabelled := base64.RawStdEncoding.EncodeToString(data)
+	data, _ = base64.RawStdEncoding.DecodeString(share1)
+	truncated := base64.RawStdEncoding.EncodeToString(data[:len(data)-1])
+
+	tests := []struct {
+		name    string
+		shares  []string
+		wantErr string
+	}{
+		{"no other shares", nil, "recovery shares needed"},
+		{"changed share", []string{changed}, "don't match the deal"},
+		{"changed share amongst enough good ones", []string{changed, share2}, "player 1's recovery share doesn't match"},
+		{"share from someone else", []string{relabelled}, "don't match the deal"},
+		{"share from the same player twice", []string{share1, share1}, "more than one recovery share"},
+		{"share for another player", []string{forPlayer3}, "is for player 3"},
+		{"badly formed share", []string{truncated}, "badly formed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := games[2].Recover(4, tt.shares...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+			if len(games[2].Departed()) != 0 {
+				t.Errorf("a failed recovery was recorded")
+			}
+		})
+	}
+
+	// Recovery only covers cards still in the deck, so doesn't reveal player 4's share of cards already drawn.
+	if err := games[2].Recover(4, share1); err != nil {
+		t.Fatalf("could not recover: %v", err)
+	}
+	if got, want := len(games[2].recovered[4]), games[2].Remaining(); got != want {
+		t.Errorf("%d key shares were recovered, want one for each of the %d cards left", got, want)
+	}
+	for cardID := range games[2].recovered[4] {
+		if games[2].state[cardID] != 0 {
+			t.Errorf("player 4's key share of card %d (%s was drawn) was recovered", cardID+1, drawn)
+		}
+	}
+}
+
+func TestEscrowDealLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		players int
+		escrow  int
+	}{
+		{"two players", 2, 2},
+		{"threshold of 1", 3, 1},
+		{"threshold of every player", 3, 3},
+		{"negative threshold", 3, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prvs := make([]Player, tt.players)
+			for i := range prvs {
+				prvs[i].PublicKey = testKey(t).Public()
+			}
+			if err := DealWith(&strings.Builder{}, append([]string(nil), testDeck...), testKey(t), DealOptions{Escrow: tt.escrow}, prvs...); err == nil {
+				t.Errorf("the deal was made")
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package trustdraw
 
 import (
-	"crypto/rsa"
+	"bytes"
+	"crypto"
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
@@ -12,19 +14,62 @@ import (
 type PlayerNumber int
 
 type Game struct {
+	id           string
 	playerNumber PlayerNumber
 	Players      int
+	roster       []RosterEntry
+	role         Role
 	cards        [][]byte
-	keys         [][]byte
+	// piles lists where the main deck, and each named pile, are in cards.
+	piles []pileRange
+	// keys is this player's key stack: their share of each card's key, or for observers, the value that checks it.
+	keys [][]byte
 
 	// state lists the player that each card has been given to.
-	// 0 means the card is still in the deck to be drawn.
+	// 0 means the card is still in the deck to be drawn, and burnt or discarded that it's gone (see Burn).
 	state []PlayerNumber
+	// drawn holds the allowKeys other players gave us for each card we drew, so we can look at our hand again later.
+	drawn map[int][]string
+	// released is true once this player has released their key stack to a god observer, ending the game for them.
+	released bool
+	// releases holds the key stacks players have released to this god observer.
+	releases map[PlayerNumber][][]byte
+
+	// escrow is the number of players needed to recover a departed player's key shares, or 0 if they can't be.
+	escrow int
+	// escrowed holds this player's escrow shares of each other player's key stack, and the values that check them.
+	escrowed map[PlayerNumber]escrowedStack
+	// recovered holds the key shares recovered for each player who has left the game, by card ID.
+	recovered map[PlayerNumber]map[int][]byte
+	// rolls lists the finished rolls this player took part in, and rolling is the roll they're taking part in now.
+	rolls   []DiceRoll
+	rolling *pendingRoll
+	// usedRolls holds the numbers of the rolls that have picked a card or cut the deck, which can't be used again.
+	usedRolls map[int]bool
+	// stolen counts the steals this player has recorded (see OfferHand). offered is this player's hand, offered to a
+	// thief, and picked is this player's pick from another player's offered hand.
+	stolen  int
+	offered *pendingOffer
+	picked  string
+	// order lists the IDs of cards put back on the deck by a player who peeked at them, which are drawn first (see
+	// Reorder), and peeked holds the cards each player was last allowed to peek at.
+	order  []int
+	peeked map[PlayerNumber][]int
+	// cuts lists the cuts this player has made to the deck, and the rolls that chose them (see Cut).
+	cuts []deckCut
+	// seated is the number of seats added to the deal file that this player has checked (see SeatPlayer).
+	seated int
+
+	// store is where the state is saved after every change, and stored is the state it was last known to hold.
+	store  StateStore
+	stored string
 }
 
 // OpenGame opens a deal file, returning a Deal that can be used to draw cards.
-// Make sure you have Verified the deck before using it.
-func OpenGame(dealFile io.Reader, playerPrv *rsa.PrivateKey, state string) (*Game, error) {
+// Make sure you have Verified the deck before using it. The player's key can be an *rsa.PrivateKey or an
+// ed25519.PrivateKey, or a key held elsewhere (like by an agent): a crypto.Decrypter for an RSA key, or a crypto.Signer
+// for an Ed25519 key that also has an X25519 ECDH method.
+func OpenGame(dealFile io.Reader, playerPrv crypto.Signer, state string) (*Game, error) {
 	stanzas, err := extractStanzas(dealFile)
 	if err != nil {
 		return nil, err
@@ -32,8 +77,16 @@ func OpenGame(dealFile io.Reader, playerPrv *rsa.PrivateKey, state string) (*Gam
 	cardCount := len(strings.Split(stanzas[1], "\n"))
 
 	game := Game{
-		Players: len(strings.Split(stanzas[2], "\n")),
-		cards:   make([][]byte, cardCount),
+		id:    dealID(stanzas[3]),
+		cards: make([][]byte, cardCount),
+	}
+	header, seats, err := readSeats(stanzas)
+	if err != nil {
+		return nil, err
+	}
+	game.roster, game.Players, game.escrow = header.roster, header.players, header.escrow
+	if game.piles, err = layoutPiles(header.piles, cardCount); err != nil {
+		return nil, err
 	}
 	if err := game.LoadState(state); err != nil {
 		return nil, fmt.Errorf("could not load game state: %w", err)
@@ -45,35 +98,131 @@ func OpenGame(dealFile io.Reader, playerPrv *rsa.PrivateKey, state string) (*Gam
 		}
 	}
 
+	// Players seated after the deal come before the observers, who are renumbered after them.
+	dealtPlayers := game.Players - len(seats)
 	for i, playerData := range strings.Split(stanzas[2], "\n") {
 		keyBlock, err := base64.RawStdEncoding.DecodeString(playerData)
 		if err != nil {
 			return nil, fmt.Errorf("player %d's data is invalid", i+1)
 		}
 
-		game.keys, err = decryptCardKeys(keyBlock, playerPrv, cardCount)
+		keys, err := decryptCardKeys(keyBlock, playerPrv, cardCount)
 		if err != nil {
 			// Not our player block, try the next one
 			continue
 		}
+		game.keys = keys[:cardCount]
+		if i >= dealtPlayers {
+			i += len(seats)
+		}
 		game.playerNumber = PlayerNumber(i + 1)
+		game.role = game.roster[i].Role
+		if game.role == RolePlayer && game.escrow > 0 {
+			if err := game.loadEscrow(keys[cardCount:]); err != nil {
+				return nil, err
+			}
+		}
 		break
 	}
+
+	joined := 0
 	if game.keys == nil {
-		return nil, fmt.Errorf("the deal file wasn't made for the given player public key")
+		n, keys, ok := takeSeat(seats, playerPrv, cardCount)
+		if !ok {
+			return nil, fmt.Errorf("the deal file wasn't made for the given player public key")
+		}
+		game.keys, game.playerNumber, joined = keys, seats[n].entry.Player, n+1
+	}
+	if listed := game.roster[game.playerNumber-1].Fingerprint; listed != "" {
+		if fingerprint, err := Fingerprint(playerPrv.Public()); err != nil || fingerprint != listed {
+			return nil, fmt.Errorf("the deal file's roster lists a different key for %s", game.PlayerName(game.playerNumber))
+		}
+	}
+	if err := game.seatPlayers(seats, joined, playerPrv); err != nil {
+		return nil, err
 	}
 
 	return &game, nil
 }
 
-// State produces a base64 encoded string that represents the current state of the game.
+// ID returns a short identifier for the deal this game was opened from.
+// Every player of the same deal will see the same ID.
+func (g *Game) ID() string {
+	return g.id
+}
+
+// PlayerNumber returns the number of the player this game was opened for.
+func (g *Game) PlayerNumber() PlayerNumber {
+	return g.playerNumber
+}
+
+// Role returns the part the player this game was opened for plays in it.
+func (g *Game) Role() Role {
+	return g.role
+}
+
+// DealID returns the identifier of the given deal file, as Game.ID would, without needing a player's key.
+func DealID(dealFile io.Reader) (string, error) {
+	stanzas, err := extractStanzas(dealFile)
+	if err != nil {
+		return "", err
+	}
+	return dealID(stanzas[3]), nil
+}
+
+// DealRoster lists the players of the given deal file (including any seated after the deal), as Game.Roster would,
+// without needing a player's key. The deal file isn't verified (see VerifyDeal).
+func DealRoster(dealFile io.Reader) ([]RosterEntry, error) {
+	stanzas, err := extractStanzas(dealFile)
+	if err != nil {
+		return nil, err
+	}
+	header, _, err := readSeats(stanzas)
+	if err != nil {
+		return nil, err
+	}
+	return header.roster[:header.players], nil
+}
+
+// State produces a string that represents the current state of the game.
+// The first line is base64 encoded, and lists the player each card has been given to. Further lines
+// hold the allowKeys of cards this player has drawn, so that they can be looked at again, whether this player has
+// released their key stack, how many seated players they've checked, their rolls, steals and peeks, and (for god observers) the key
+// stacks released to them.
 func (g *Game) State() string {
 	state := make([]byte, len(g.state))
 	for i, player := range g.state {
 		state[i] = byte(player)
 	}
 
-	return base64.RawStdEncoding.EncodeToString(state)
+	lines := []string{base64.RawStdEncoding.EncodeToString(state)}
+	for _, cardID := range sortedCardIDs(g.drawn) {
+		lines = append(lines, "drawn "+strings.Join(g.drawn[cardID], " "))
+	}
+	if g.released {
+		lines = append(lines, "released")
+	}
+	for _, player := range sortedPlayers(g.recovered) {
+		recovered := g.recovered[player]
+		allowKeys := make([]string, 0, len(recovered))
+		for _, cardID := range sortedCardIDs(recovered) {
+			allowKeys = append(allowKeys, toAllowKey(cardID, recovered[cardID]))
+		}
+		lines = append(lines, fmt.Sprintf("recovered %d %s", player, strings.Join(allowKeys, " ")))
+	}
+	if g.seated > 0 {
+		lines = append(lines, fmt.Sprintf("seated %d", g.seated))
+	}
+	lines = append(lines, g.rollStateLines()...)
+	lines = append(lines, g.stealStateLines()...)
+	lines = append(lines, g.peekStateLines()...)
+	lines = append(lines, g.cutStateLines()...)
+	for _, player := range sortedPlayers(g.releases) {
+		stack := base64.RawStdEncoding.EncodeToString(bytes.Join(g.releases[player], nil))
+		lines = append(lines, fmt.Sprintf("release %d %s", player, stack))
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 // LoadState loads the game state from a string encoded with State().
@@ -83,18 +232,174 @@ func (g *Game) LoadState(states string) error {
 		return fmt.Errorf("can't load state before the cards have been loaded")
 	}
 	g.state = make([]PlayerNumber, cardCount)
+	g.drawn = make(map[int][]string)
+	g.released = false
+	g.releases = make(map[PlayerNumber][][]byte)
+	g.recovered = make(map[PlayerNumber]map[int][]byte)
+	g.seated = 0
+	g.rolls = nil
+	g.rolling = nil
+	g.usedRolls = make(map[int]bool)
+	g.stolen = 0
+	g.offered = nil
+	g.picked = ""
+	g.order = nil
+	g.peeked = make(map[PlayerNumber][]int)
+	g.cuts = nil
 
-	state, err := base64.RawStdEncoding.DecodeString(states)
+	lines := strings.Split(strings.TrimSpace(states), "\n")
+	state, err := base64.RawStdEncoding.DecodeString(lines[0])
 	if err != nil {
 		return err
 	}
+	if len(state) > cardCount {
+		return fmt.Errorf("state is for a deck with more cards than this one")
+	}
 
 	for i, playerByte := range state {
-		if playerByte > byte(g.Players) {
+		if player := PlayerNumber(playerByte); player > PlayerNumber(g.Players) && player != notInDeck && player != burnt && player != discarded {
 			return fmt.Errorf("player %d is not in this game", playerByte)
 		}
 		g.state[i] = PlayerNumber(playerByte)
 	}
 
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "drawn":
+			cardID, err := allowKeysCardID(fields[1:])
+			if err != nil || cardID >= cardCount {
+				return fmt.Errorf("state holds an invalid drawn card")
+			}
+			g.drawn[cardID] = fields[1:]
+		case "recovered":
+			if len(fields) < 2 {
+				return fmt.Errorf("state holds invalid recovered key shares")
+			}
+			player, err := strconv.Atoi(fields[1])
+			if err != nil || player < 1 || player > g.Players {
+				return fmt.Errorf("state holds invalid recovered key shares")
+			}
+			recovered := make(map[int][]byte)
+			for _, allowKey := range fields[2:] {
+				cardID, share, err := fromAllowKey(allowKey)
+				if err != nil || cardID >= cardCount {
+					return fmt.Errorf("state holds invalid recovered key shares")
+				}
+				recovered[cardID] = share
+			}
+			g.recovered[PlayerNumber(player)] = recovered
+		case "released":
+			g.released = true
+		case "rolled", "rolling", "used":
+			if err := g.loadRollState(fields); err != nil {
+				return err
+			}
+		case "stolen", "offered", "picked":
+			if err := g.loadStealState(fields); err != nil {
+				return err
+			}
+		case "order", "peeked":
+			if err := g.loadPeekState(fields); err != nil {
+				return err
+			}
+		case "cut":
+			if err := g.loadCutState(fields); err != nil {
+				return err
+			}
+		case "seated":
+			seated, err := strconv.Atoi(strings.Join(fields[1:], " "))
+			if err != nil || seated < 0 {
+				return fmt.Errorf("state holds an invalid number of seated players")
+			}
+			g.seated = seated
+		case "release":
+			if len(fields) != 3 {
+				return fmt.Errorf("state holds an invalid released key stack")
+			}
+			player, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("state holds an invalid released key stack")
+			}
+			stackBytes, err := base64.RawStdEncoding.DecodeString(fields[2])
+			if err != nil {
+				return fmt.Errorf("state holds an invalid released key stack")
+			}
+			releaser, stack, err := checkRelease(player, stackBytes, g.Players, cardCount)
+			if err != nil {
+				return err
+			}
+			g.releases[releaser] = stack
+		default:
+			return fmt.Errorf("unknown state entry: %s", fields[0])
+		}
+	}
+
 	return nil
 }
+
+// HeldCard is a card in this player's hand.
+type HeldCard struct {
+	// CardID is the card's ID, as used by DiscardHidden.
+	CardID int
+	Card   string
+	// AllowKey is this player's allowKey for the card, which the other players can use to verify it.
+	AllowKey string
+	// AllowKeys are every player's allowKeys for the card, which observers need to see it.
+	AllowKeys []string
+}
+
+// Hand lists the cards this player has drawn and still holds, in the order they appear in the deck.
+func (g *Game) Hand() ([]HeldCard, error) {
+	var hand []HeldCard
+	for _, cardID := range sortedCardIDs(g.drawn) {
+		if g.state[cardID] != g.playerNumber {
+			continue
+		}
+
+		_, cardKey, err := g.allowKeysToCardKey(g.drawn[cardID])
+		if err != nil {
+			return nil, fmt.Errorf("could not re-create card key: %w", err)
+		}
+		card, err := g.decryptCard(cardID, cardKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt card: %w", err)
+		}
+
+		allowKey := toAllowKey(cardID, g.keys[cardID])
+		hand = append(hand, HeldCard{
+			CardID:    cardID,
+			Card:      card,
+			AllowKey:  allowKey,
+			AllowKeys: append([]string{allowKey}, g.drawn[cardID]...),
+		})
+	}
+
+	return hand, nil
+}
+
+// Remaining counts the cards still in the deck to be drawn.
+func (g *Game) Remaining() int {
+	return g.Holdings()[0]
+}
+
+// Holdings counts the cards each player has been given, according to this player's state.
+// The count for player 0 is the number of cards still in the deck. Players seated after the game began don't count
+// the cards that had already left the deck, and burnt or discarded cards aren't held by anyone.
+func (g *Game) Holdings() map[PlayerNumber]int {
+	holdings := make(map[PlayerNumber]int)
+	for p := 0; p <= g.Players; p++ {
+		holdings[PlayerNumber(p)] = 0
+	}
+	for _, player := range g.state {
+		if player == notInDeck || player == burnt || player == discarded {
+			continue
+		}
+		holdings[player]++
+	}
+	return holdings
+}
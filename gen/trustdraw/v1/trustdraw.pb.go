@@ -0,0 +1,1753 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: trustdraw/v1/trustdraw.proto
+
+// The TrustDraw service wraps the TrustDraw library, so that any language can deal and play
+// games over localhost by running trustdraw as a sidecar.
+
+package trustdrawv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TranscriptEvent_Kind int32
+
+const (
+	TranscriptEvent_KIND_UNSPECIFIED   TranscriptEvent_Kind = 0
+	TranscriptEvent_KIND_OPENED        TranscriptEvent_Kind = 1
+	TranscriptEvent_KIND_ALLOWED_DRAW  TranscriptEvent_Kind = 2
+	TranscriptEvent_KIND_DREW          TranscriptEvent_Kind = 3
+	TranscriptEvent_KIND_VERIFIED_DRAW TranscriptEvent_Kind = 4
+	TranscriptEvent_KIND_CLOSED        TranscriptEvent_Kind = 5
+)
+
+// Enum value maps for TranscriptEvent_Kind.
+var (
+	TranscriptEvent_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_OPENED",
+		2: "KIND_ALLOWED_DRAW",
+		3: "KIND_DREW",
+		4: "KIND_VERIFIED_DRAW",
+		5: "KIND_CLOSED",
+	}
+	TranscriptEvent_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED":   0,
+		"KIND_OPENED":        1,
+		"KIND_ALLOWED_DRAW":  2,
+		"KIND_DREW":          3,
+		"KIND_VERIFIED_DRAW": 4,
+		"KIND_CLOSED":        5,
+	}
+)
+
+func (x TranscriptEvent_Kind) Enum() *TranscriptEvent_Kind {
+	p := new(TranscriptEvent_Kind)
+	*p = x
+	return p
+}
+
+func (x TranscriptEvent_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TranscriptEvent_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_trustdraw_v1_trustdraw_proto_enumTypes[0].Descriptor()
+}
+
+func (TranscriptEvent_Kind) Type() protoreflect.EnumType {
+	return &file_trustdraw_v1_trustdraw_proto_enumTypes[0]
+}
+
+func (x TranscriptEvent_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TranscriptEvent_Kind.Descriptor instead.
+func (TranscriptEvent_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{19, 0}
+}
+
+// GameRef identifies an open game: the deal's ID, and the player it was opened for.
+type GameRef struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Player int32  `protobuf:"varint,2,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *GameRef) Reset() {
+	*x = GameRef{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameRef) ProtoMessage() {}
+
+func (x *GameRef) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameRef.ProtoReflect.Descriptor instead.
+func (*GameRef) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GameRef) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GameRef) GetPlayer() int32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+type DealRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Deck:
+	//	*DealRequest_DeckName
+	//	*DealRequest_Cards
+	Deck isDealRequest_Deck `protobuf_oneof:"deck"`
+	// The dealer's PEM, OpenSSH or JWK encoded RSA or Ed25519 private key.
+	DealerPrivateKey string `protobuf:"bytes,3,opt,name=dealer_private_key,json=dealerPrivateKey,proto3" json:"dealer_private_key,omitempty"`
+	// The players' PEM, OpenSSH or JWK encoded RSA or Ed25519 public keys, in player number order.
+	PlayerPublicKeys []string `protobuf:"bytes,4,rep,name=player_public_keys,json=playerPublicKeys,proto3" json:"player_public_keys,omitempty"`
+}
+
+func (x *DealRequest) Reset() {
+	*x = DealRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DealRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DealRequest) ProtoMessage() {}
+
+func (x *DealRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DealRequest.ProtoReflect.Descriptor instead.
+func (*DealRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{1}
+}
+
+func (m *DealRequest) GetDeck() isDealRequest_Deck {
+	if m != nil {
+		return m.Deck
+	}
+	return nil
+}
+
+func (x *DealRequest) GetDeckName() string {
+	if x, ok := x.GetDeck().(*DealRequest_DeckName); ok {
+		return x.DeckName
+	}
+	return ""
+}
+
+func (x *DealRequest) GetCards() *Cards {
+	if x, ok := x.GetDeck().(*DealRequest_Cards); ok {
+		return x.Cards
+	}
+	return nil
+}
+
+func (x *DealRequest) GetDealerPrivateKey() string {
+	if x != nil {
+		return x.DealerPrivateKey
+	}
+	return ""
+}
+
+func (x *DealRequest) GetPlayerPublicKeys() []string {
+	if x != nil {
+		return x.PlayerPublicKeys
+	}
+	return nil
+}
+
+type isDealRequest_Deck interface {
+	isDealRequest_Deck()
+}
+
+type DealRequest_DeckName struct {
+	// The name of one of the in-built decks, eg. "standard52-fr".
+	DeckName string `protobuf:"bytes,1,opt,name=deck_name,json=deckName,proto3,oneof"`
+}
+
+type DealRequest_Cards struct {
+	// A list of cards to deal.
+	Cards *Cards `protobuf:"bytes,2,opt,name=cards,proto3,oneof"`
+}
+
+func (*DealRequest_DeckName) isDealRequest_Deck() {}
+
+func (*DealRequest_Cards) isDealRequest_Deck() {}
+
+type Cards struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards []string `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+}
+
+func (x *Cards) Reset() {
+	*x = Cards{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Cards) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cards) ProtoMessage() {}
+
+func (x *Cards) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cards.ProtoReflect.Descriptor instead.
+func (*Cards) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cards) GetCards() []string {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type DealResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deal   string `protobuf:"bytes,1,opt,name=deal,proto3" json:"deal,omitempty"`
+	GameId string `protobuf:"bytes,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+}
+
+func (x *DealResponse) Reset() {
+	*x = DealResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DealResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DealResponse) ProtoMessage() {}
+
+func (x *DealResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DealResponse.ProtoReflect.Descriptor instead.
+func (*DealResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DealResponse) GetDeal() string {
+	if x != nil {
+		return x.Deal
+	}
+	return ""
+}
+
+func (x *DealResponse) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+type VerifyDealRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deal string `protobuf:"bytes,1,opt,name=deal,proto3" json:"deal,omitempty"`
+	// The dealer's PEM, OpenSSH or JWK encoded RSA or Ed25519 public key.
+	DealerPublicKey string `protobuf:"bytes,2,opt,name=dealer_public_key,json=dealerPublicKey,proto3" json:"dealer_public_key,omitempty"`
+}
+
+func (x *VerifyDealRequest) Reset() {
+	*x = VerifyDealRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyDealRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDealRequest) ProtoMessage() {}
+
+func (x *VerifyDealRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDealRequest.ProtoReflect.Descriptor instead.
+func (*VerifyDealRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyDealRequest) GetDeal() string {
+	if x != nil {
+		return x.Deal
+	}
+	return ""
+}
+
+func (x *VerifyDealRequest) GetDealerPublicKey() string {
+	if x != nil {
+		return x.DealerPublicKey
+	}
+	return ""
+}
+
+type VerifyDealResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards   int32 `protobuf:"varint,1,opt,name=cards,proto3" json:"cards,omitempty"`
+	Players int32 `protobuf:"varint,2,opt,name=players,proto3" json:"players,omitempty"`
+}
+
+func (x *VerifyDealResponse) Reset() {
+	*x = VerifyDealResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyDealResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDealResponse) ProtoMessage() {}
+
+func (x *VerifyDealResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDealResponse.ProtoReflect.Descriptor instead.
+func (*VerifyDealResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyDealResponse) GetCards() int32 {
+	if x != nil {
+		return x.Cards
+	}
+	return 0
+}
+
+func (x *VerifyDealResponse) GetPlayers() int32 {
+	if x != nil {
+		return x.Players
+	}
+	return 0
+}
+
+type OpenGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deal string `protobuf:"bytes,1,opt,name=deal,proto3" json:"deal,omitempty"`
+	// The player's PEM, OpenSSH or JWK encoded RSA or Ed25519 private key.
+	PlayerPrivateKey string `protobuf:"bytes,2,opt,name=player_private_key,json=playerPrivateKey,proto3" json:"player_private_key,omitempty"`
+	// The state previously returned by GetState, or empty for a new game.
+	State string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *OpenGameRequest) Reset() {
+	*x = OpenGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenGameRequest) ProtoMessage() {}
+
+func (x *OpenGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenGameRequest.ProtoReflect.Descriptor instead.
+func (*OpenGameRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OpenGameRequest) GetDeal() string {
+	if x != nil {
+		return x.Deal
+	}
+	return ""
+}
+
+func (x *OpenGameRequest) GetPlayerPrivateKey() string {
+	if x != nil {
+		return x.PlayerPrivateKey
+	}
+	return ""
+}
+
+func (x *OpenGameRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type OpenGameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game    *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Players int32    `protobuf:"varint,2,opt,name=players,proto3" json:"players,omitempty"`
+}
+
+func (x *OpenGameResponse) Reset() {
+	*x = OpenGameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenGameResponse) ProtoMessage() {}
+
+func (x *OpenGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenGameResponse.ProtoReflect.Descriptor instead.
+func (*OpenGameResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OpenGameResponse) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *OpenGameResponse) GetPlayers() int32 {
+	if x != nil {
+		return x.Players
+	}
+	return 0
+}
+
+type CloseGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+}
+
+func (x *CloseGameRequest) Reset() {
+	*x = CloseGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloseGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseGameRequest) ProtoMessage() {}
+
+func (x *CloseGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseGameRequest.ProtoReflect.Descriptor instead.
+func (*CloseGameRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CloseGameRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+type CloseGameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CloseGameResponse) Reset() {
+	*x = CloseGameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloseGameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseGameResponse) ProtoMessage() {}
+
+func (x *CloseGameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseGameResponse.ProtoReflect.Descriptor instead.
+func (*CloseGameResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{9}
+}
+
+type AllowDrawRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	// The player who will draw the card.
+	Player int32 `protobuf:"varint,2,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *AllowDrawRequest) Reset() {
+	*x = AllowDrawRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllowDrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowDrawRequest) ProtoMessage() {}
+
+func (x *AllowDrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowDrawRequest.ProtoReflect.Descriptor instead.
+func (*AllowDrawRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AllowDrawRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *AllowDrawRequest) GetPlayer() int32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+type AllowDrawResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AllowKey string `protobuf:"bytes,1,opt,name=allow_key,json=allowKey,proto3" json:"allow_key,omitempty"`
+}
+
+func (x *AllowDrawResponse) Reset() {
+	*x = AllowDrawResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllowDrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowDrawResponse) ProtoMessage() {}
+
+func (x *AllowDrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowDrawResponse.ProtoReflect.Descriptor instead.
+func (*AllowDrawResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AllowDrawResponse) GetAllowKey() string {
+	if x != nil {
+		return x.AllowKey
+	}
+	return ""
+}
+
+type DrawRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game      *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	AllowKeys []string `protobuf:"bytes,2,rep,name=allow_keys,json=allowKeys,proto3" json:"allow_keys,omitempty"`
+}
+
+func (x *DrawRequest) Reset() {
+	*x = DrawRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawRequest) ProtoMessage() {}
+
+func (x *DrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawRequest.ProtoReflect.Descriptor instead.
+func (*DrawRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DrawRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *DrawRequest) GetAllowKeys() []string {
+	if x != nil {
+		return x.AllowKeys
+	}
+	return nil
+}
+
+type DrawResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Card string `protobuf:"bytes,1,opt,name=card,proto3" json:"card,omitempty"`
+	// This player's allowKey for the card, which proves the draw to the other players.
+	AllowKey     string `protobuf:"bytes,2,opt,name=allow_key,json=allowKey,proto3" json:"allow_key,omitempty"`
+	AlreadyDrawn bool   `protobuf:"varint,3,opt,name=already_drawn,json=alreadyDrawn,proto3" json:"already_drawn,omitempty"`
+}
+
+func (x *DrawResponse) Reset() {
+	*x = DrawResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawResponse) ProtoMessage() {}
+
+func (x *DrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawResponse.ProtoReflect.Descriptor instead.
+func (*DrawResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DrawResponse) GetCard() string {
+	if x != nil {
+		return x.Card
+	}
+	return ""
+}
+
+func (x *DrawResponse) GetAllowKey() string {
+	if x != nil {
+		return x.AllowKey
+	}
+	return ""
+}
+
+func (x *DrawResponse) GetAlreadyDrawn() bool {
+	if x != nil {
+		return x.AlreadyDrawn
+	}
+	return false
+}
+
+type VerifyDrawRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game      *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Card      string   `protobuf:"bytes,2,opt,name=card,proto3" json:"card,omitempty"`
+	AllowKeys []string `protobuf:"bytes,3,rep,name=allow_keys,json=allowKeys,proto3" json:"allow_keys,omitempty"`
+	// If set, the card must also be recorded as having been given to this player.
+	Player int32 `protobuf:"varint,4,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *VerifyDrawRequest) Reset() {
+	*x = VerifyDrawRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyDrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDrawRequest) ProtoMessage() {}
+
+func (x *VerifyDrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDrawRequest.ProtoReflect.Descriptor instead.
+func (*VerifyDrawRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VerifyDrawRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *VerifyDrawRequest) GetCard() string {
+	if x != nil {
+		return x.Card
+	}
+	return ""
+}
+
+func (x *VerifyDrawRequest) GetAllowKeys() []string {
+	if x != nil {
+		return x.AllowKeys
+	}
+	return nil
+}
+
+func (x *VerifyDrawRequest) GetPlayer() int32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+type VerifyDrawResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+}
+
+func (x *VerifyDrawResponse) Reset() {
+	*x = VerifyDrawResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyDrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDrawResponse) ProtoMessage() {}
+
+func (x *VerifyDrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDrawResponse.ProtoReflect.Descriptor instead.
+func (*VerifyDrawResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VerifyDrawResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetStateRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+type GetStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	// Cards still in the deck to be drawn.
+	Remaining int32 `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// How many cards each player holds, indexed by player number - 1.
+	Holdings []int32 `protobuf:"varint,3,rep,packed,name=holdings,proto3" json:"holdings,omitempty"`
+}
+
+func (x *GetStateResponse) Reset() {
+	*x = GetStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateResponse) ProtoMessage() {}
+
+func (x *GetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetStateResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *GetStateResponse) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *GetStateResponse) GetHoldings() []int32 {
+	if x != nil {
+		return x.Holdings
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game *GameRef `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubscribeRequest) GetGame() *GameRef {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+// TranscriptEvent is something that happened in an open game. Events never hold hidden information:
+// allowKeys and drawn cards are left out.
+type TranscriptEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seq  int64                `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Kind TranscriptEvent_Kind `protobuf:"varint,2,opt,name=kind,proto3,enum=trustdraw.v1.TranscriptEvent_Kind" json:"kind,omitempty"`
+	// The player a draw was allowed for, or whose draw was verified.
+	Player int32 `protobuf:"varint,3,opt,name=player,proto3" json:"player,omitempty"`
+	// The card whose draw was verified.
+	Card string `protobuf:"bytes,4,opt,name=card,proto3" json:"card,omitempty"`
+	// Whether the verified draw was valid.
+	Valid bool `protobuf:"varint,5,opt,name=valid,proto3" json:"valid,omitempty"`
+}
+
+func (x *TranscriptEvent) Reset() {
+	*x = TranscriptEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TranscriptEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranscriptEvent) ProtoMessage() {}
+
+func (x *TranscriptEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_trustdraw_v1_trustdraw_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranscriptEvent.ProtoReflect.Descriptor instead.
+func (*TranscriptEvent) Descriptor() ([]byte, []int) {
+	return file_trustdraw_v1_trustdraw_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TranscriptEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *TranscriptEvent) GetKind() TranscriptEvent_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return TranscriptEvent_KIND_UNSPECIFIED
+}
+
+func (x *TranscriptEvent) GetPlayer() int32 {
+	if x != nil {
+		return x.Player
+	}
+	return 0
+}
+
+func (x *TranscriptEvent) GetCard() string {
+	if x != nil {
+		return x.Card
+	}
+	return ""
+}
+
+func (x *TranscriptEvent) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+var File_trustdraw_v1_trustdraw_proto protoreflect.FileDescriptor
+
+var file_trustdraw_v1_trustdraw_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2f, 0x76, 0x31, 0x2f, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x22, 0x3a, 0x0a, 0x07,
+	0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x66, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0xbd, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x61,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x09, 0x64, 0x65, 0x63, 0x6b,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x64,
+	0x65, 0x63, 0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72,
+	0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x73, 0x48, 0x00, 0x52, 0x05, 0x63,
+	0x61, 0x72, 0x64, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x5f, 0x70,
+	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b,
+	0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x73,
+	0x42, 0x06, 0x0a, 0x04, 0x64, 0x65, 0x63, 0x6b, 0x22, 0x1d, 0x0a, 0x05, 0x43, 0x61, 0x72, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73, 0x22, 0x3b, 0x0a, 0x0c, 0x44, 0x65, 0x61, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x67,
+	0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61,
+	0x6d, 0x65, 0x49, 0x64, 0x22, 0x53, 0x0a, 0x11, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x65,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x61,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x12, 0x2a, 0x0a,
+	0x11, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x5f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0x44, 0x0a, 0x12, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x44, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x63, 0x61, 0x72, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x22,
+	0x69, 0x0a, 0x0f, 0x4f, 0x70, 0x65, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x5f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x10, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74,
+	0x65, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x57, 0x0a, 0x10, 0x4f, 0x70,
+	0x65, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29,
+	0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x66, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x22, 0x3d, 0x0a, 0x10, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x47, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61,
+	0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x66, 0x52, 0x04, 0x67, 0x61,
+	0x6d, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x55, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x6f, 0x77,
+	0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x67,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x66,
+	0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0x30,
+	0x0a, 0x11, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4b, 0x65, 0x79,
+	0x22, 0x57, 0x0a, 0x0b, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x29, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d,
+	0x65, 0x52, 0x65, 0x66, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x64, 0x0a, 0x0c, 0x44, 0x72, 0x61,
+	0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x61, 0x72,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c,
+	0x72, 0x65, 0x61, 0x64, 0x79, 0x5f, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x72, 0x65, 0x61, 0x64, 0x79, 0x44, 0x72, 0x61, 0x77, 0x6e, 0x22,
+	0x89, 0x01, 0x0a, 0x11, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x66, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x61, 0x72, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6b, 0x65,
+	0x79, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4b,
+	0x65, 0x79, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22, 0x2a, 0x0a, 0x12, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x22, 0x3c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x67, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x66, 0x52,
+	0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x62, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x08, 0x68, 0x6f, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x3d, 0x0a, 0x10, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a,
+	0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72,
+	0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x66, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x22, 0x9b, 0x02, 0x0a, 0x0f, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x73, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x36,
+	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22, 0x2e, 0x74,
+	0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x4b, 0x69, 0x6e, 0x64,
+	0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x61, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x61,
+	0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x22, 0x7c, 0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64,
+	0x12, 0x14, 0x0a, 0x10, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4f,
+	0x50, 0x45, 0x4e, 0x45, 0x44, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x4b, 0x49, 0x4e, 0x44, 0x5f,
+	0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x45, 0x44, 0x5f, 0x44, 0x52, 0x41, 0x57, 0x10, 0x02, 0x12, 0x0d,
+	0x0a, 0x09, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x44, 0x52, 0x45, 0x57, 0x10, 0x03, 0x12, 0x16, 0x0a,
+	0x12, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45, 0x44, 0x5f, 0x44,
+	0x52, 0x41, 0x57, 0x10, 0x04, 0x12, 0x0f, 0x0a, 0x0b, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x43, 0x4c,
+	0x4f, 0x53, 0x45, 0x44, 0x10, 0x05, 0x32, 0xb2, 0x05, 0x0a, 0x10, 0x54, 0x72, 0x75, 0x73, 0x74,
+	0x44, 0x72, 0x61, 0x77, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x04, 0x44,
+	0x65, 0x61, 0x6c, 0x12, 0x19, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x44, 0x65, 0x61, 0x6c, 0x12, 0x1f, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x65,
+	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44,
+	0x65, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x08, 0x4f,
+	0x70, 0x65, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64,
+	0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72,
+	0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x47,
+	0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x72, 0x61,
+	0x77, 0x12, 0x1e, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3d, 0x0a, 0x04, 0x44, 0x72, 0x61, 0x77, 0x12, 0x19, 0x2e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61,
+	0x77, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x72, 0x61, 0x77, 0x12,
+	0x1f, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x44, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x49, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1d,
+	0x2e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a,
+	0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1e, 0x2e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x75,
+	0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x3e, 0x5a, 0x3c, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x70, 0x68, 0x61, 0x73, 0x74,
+	0x69, 0x6e, 0x67, 0x73, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2f, 0x67,
+	0x65, 0x6e, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x2f, 0x76, 0x31, 0x3b,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x64, 0x72, 0x61, 0x77, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_trustdraw_v1_trustdraw_proto_rawDescOnce sync.Once
+	file_trustdraw_v1_trustdraw_proto_rawDescData = file_trustdraw_v1_trustdraw_proto_rawDesc
+)
+
+func file_trustdraw_v1_trustdraw_proto_rawDescGZIP() []byte {
+	file_trustdraw_v1_trustdraw_proto_rawDescOnce.Do(func() {
+		file_trustdraw_v1_trustdraw_proto_rawDescData = protoimpl.X.CompressGZIP(file_trustdraw_v1_trustdraw_proto_rawDescData)
+	})
+	return file_trustdraw_v1_trustdraw_proto_rawDescData
+}
+
+var file_trustdraw_v1_trustdraw_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_trustdraw_v1_trustdraw_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_trustdraw_v1_trustdraw_proto_goTypes = []any{
+	(TranscriptEvent_Kind)(0),  // 0: trustdraw.v1.TranscriptEvent.Kind
+	(*GameRef)(nil),            // 1: trustdraw.v1.GameRef
+	(*DealRequest)(nil),        // 2: trustdraw.v1.DealRequest
+	(*Cards)(nil),              // 3: trustdraw.v1.Cards
+	(*DealResponse)(nil),       // 4: trustdraw.v1.DealResponse
+	(*VerifyDealRequest)(nil),  // 5: trustdraw.v1.VerifyDealRequest
+	(*VerifyDealResponse)(nil), // 6: trustdraw.v1.VerifyDealResponse
+	(*OpenGameRequest)(nil),    // 7: trustdraw.v1.OpenGameRequest
+	(*OpenGameResponse)(nil),   // 8: trustdraw.v1.OpenGameResponse
+	(*CloseGameRequest)(nil),   // 9: trustdraw.v1.CloseGameRequest
+	(*CloseGameResponse)(nil),  // 10: trustdraw.v1.CloseGameResponse
+	(*AllowDrawRequest)(nil),   // 11: trustdraw.v1.AllowDrawRequest
+	(*AllowDrawResponse)(nil),  // 12: trustdraw.v1.AllowDrawResponse
+	(*DrawRequest)(nil),        // 13: trustdraw.v1.DrawRequest
+	(*DrawResponse)(nil),       // 14: trustdraw.v1.DrawResponse
+	(*VerifyDrawRequest)(nil),  // 15: trustdraw.v1.VerifyDrawRequest
+	(*VerifyDrawResponse)(nil), // 16: trustdraw.v1.VerifyDrawResponse
+	(*GetStateRequest)(nil),    // 17: trustdraw.v1.GetStateRequest
+	(*GetStateResponse)(nil),   // 18: trustdraw.v1.GetStateResponse
+	(*SubscribeRequest)(nil),   // 19: trustdraw.v1.SubscribeRequest
+	(*TranscriptEvent)(nil),    // 20: trustdraw.v1.TranscriptEvent
+}
+var file_trustdraw_v1_trustdraw_proto_depIdxs = []int32{
+	3,  // 0: trustdraw.v1.DealRequest.cards:type_name -> trustdraw.v1.Cards
+	1,  // 1: trustdraw.v1.OpenGameResponse.game:type_name -> trustdraw.v1.GameRef
+	1,  // 2: trustdraw.v1.CloseGameRequest.game:type_name -> trustdraw.v1.GameRef
+	1,  // 3: trustdraw.v1.AllowDrawRequest.game:type_name -> trustdraw.v1.GameRef
+	1,  // 4: trustdraw.v1.DrawRequest.game:type_name -> trustdraw.v1.GameRef
+	1,  // 5: trustdraw.v1.VerifyDrawRequest.game:type_name -> trustdraw.v1.GameRef
+	1,  // 6: trustdraw.v1.GetStateRequest.game:type_name -> trustdraw.v1.GameRef
+	1,  // 7: trustdraw.v1.SubscribeRequest.game:type_name -> trustdraw.v1.GameRef
+	0,  // 8: trustdraw.v1.TranscriptEvent.kind:type_name -> trustdraw.v1.TranscriptEvent.Kind
+	2,  // 9: trustdraw.v1.TrustDrawService.Deal:input_type -> trustdraw.v1.DealRequest
+	5,  // 10: trustdraw.v1.TrustDrawService.VerifyDeal:input_type -> trustdraw.v1.VerifyDealRequest
+	7,  // 11: trustdraw.v1.TrustDrawService.OpenGame:input_type -> trustdraw.v1.OpenGameRequest
+	9,  // 12: trustdraw.v1.TrustDrawService.CloseGame:input_type -> trustdraw.v1.CloseGameRequest
+	11, // 13: trustdraw.v1.TrustDrawService.AllowDraw:input_type -> trustdraw.v1.AllowDrawRequest
+	13, // 14: trustdraw.v1.TrustDrawService.Draw:input_type -> trustdraw.v1.DrawRequest
+	15, // 15: trustdraw.v1.TrustDrawService.VerifyDraw:input_type -> trustdraw.v1.VerifyDrawRequest
+	17, // 16: trustdraw.v1.TrustDrawService.GetState:input_type -> trustdraw.v1.GetStateRequest
+	19, // 17: trustdraw.v1.TrustDrawService.Subscribe:input_type -> trustdraw.v1.SubscribeRequest
+	4,  // 18: trustdraw.v1.TrustDrawService.Deal:output_type -> trustdraw.v1.DealResponse
+	6,  // 19: trustdraw.v1.TrustDrawService.VerifyDeal:output_type -> trustdraw.v1.VerifyDealResponse
+	8,  // 20: trustdraw.v1.TrustDrawService.OpenGame:output_type -> trustdraw.v1.OpenGameResponse
+	10, // 21: trustdraw.v1.TrustDrawService.CloseGame:output_type -> trustdraw.v1.CloseGameResponse
+	12, // 22: trustdraw.v1.TrustDrawService.AllowDraw:output_type -> trustdraw.v1.AllowDrawResponse
+	14, // 23: trustdraw.v1.TrustDrawService.Draw:output_type -> trustdraw.v1.DrawResponse
+	16, // 24: trustdraw.v1.TrustDrawService.VerifyDraw:output_type -> trustdraw.v1.VerifyDrawResponse
+	18, // 25: trustdraw.v1.TrustDrawService.GetState:output_type -> trustdraw.v1.GetStateResponse
+	20, // 26: trustdraw.v1.TrustDrawService.Subscribe:output_type -> trustdraw.v1.TranscriptEvent
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_trustdraw_v1_trustdraw_proto_init() }
+func file_trustdraw_v1_trustdraw_proto_init() {
+	if File_trustdraw_v1_trustdraw_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_trustdraw_v1_trustdraw_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GameRef); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*DealRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Cards); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*DealResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyDealRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyDealResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*OpenGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*OpenGameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*CloseGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*CloseGameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*AllowDrawRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*AllowDrawResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*DrawRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DrawResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyDrawRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifyDrawResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*SubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_trustdraw_v1_trustdraw_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*TranscriptEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_trustdraw_v1_trustdraw_proto_msgTypes[1].OneofWrappers = []any{
+		(*DealRequest_DeckName)(nil),
+		(*DealRequest_Cards)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_trustdraw_v1_trustdraw_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_trustdraw_v1_trustdraw_proto_goTypes,
+		DependencyIndexes: file_trustdraw_v1_trustdraw_proto_depIdxs,
+		EnumInfos:         file_trustdraw_v1_trustdraw_proto_enumTypes,
+		MessageInfos:      file_trustdraw_v1_trustdraw_proto_msgTypes,
+	}.Build()
+	File_trustdraw_v1_trustdraw_proto = out.File
+	file_trustdraw_v1_trustdraw_proto_rawDesc = nil
+	file_trustdraw_v1_trustdraw_proto_goTypes = nil
+	file_trustdraw_v1_trustdraw_proto_depIdxs = nil
+}
@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: trustdraw/v1/trustdraw.proto
+
+// The TrustDraw service wraps the TrustDraw library, so that any language can deal and play
+// games over localhost by running trustdraw as a sidecar.
+package trustdrawv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/jphastings/trustdraw/gen/trustdraw/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TrustDrawServiceName is the fully-qualified name of the TrustDrawService service.
+	TrustDrawServiceName = "trustdraw.v1.TrustDrawService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TrustDrawServiceDealProcedure is the fully-qualified name of the TrustDrawService's Deal RPC.
+	TrustDrawServiceDealProcedure = "/trustdraw.v1.TrustDrawService/Deal"
+	// TrustDrawServiceVerifyDealProcedure is the fully-qualified name of the TrustDrawService's
+	// VerifyDeal RPC.
+	TrustDrawServiceVerifyDealProcedure = "/trustdraw.v1.TrustDrawService/VerifyDeal"
+	// TrustDrawServiceOpenGameProcedure is the fully-qualified name of the TrustDrawService's OpenGame
+	// RPC.
+	TrustDrawServiceOpenGameProcedure = "/trustdraw.v1.TrustDrawService/OpenGame"
+	// TrustDrawServiceCloseGameProcedure is the fully-qualified name of the TrustDrawService's
+	// CloseGame RPC.
+	TrustDrawServiceCloseGameProcedure = "/trustdraw.v1.TrustDrawService/CloseGame"
+	// TrustDrawServiceAllowDrawProcedure is the fully-qualified name of the TrustDrawService's
+	// AllowDraw RPC.
+	TrustDrawServiceAllowDrawProcedure = "/trustdraw.v1.TrustDrawService/AllowDraw"
+	// TrustDrawServiceDrawProcedure is the fully-qualified name of the TrustDrawService's Draw RPC.
+	TrustDrawServiceDrawProcedure = "/trustdraw.v1.TrustDrawService/Draw"
+	// TrustDrawServiceVerifyDrawProcedure is the fully-qualified name of the TrustDrawService's
+	// VerifyDraw RPC.
+	TrustDrawServiceVerifyDrawProcedure = "/trustdraw.v1.TrustDrawService/VerifyDraw"
+	// TrustDrawServiceGetStateProcedure is the fully-qualified name of the TrustDrawService's GetState
+	// RPC.
+	TrustDrawServiceGetStateProcedure = "/trustdraw.v1.TrustDrawService/GetState"
+	// TrustDrawServiceSubscribeProcedure is the fully-qualified name of the TrustDrawService's
+	// Subscribe RPC.
+	TrustDrawServiceSubscribeProcedure = "/trustdraw.v1.TrustDrawService/Subscribe"
+)
+
+// These variables are the protoreflect.Descriptor objects for the RPCs defined in this package.
+var (
+	trustDrawServiceServiceDescriptor          = v1.File_trustdraw_v1_trustdraw_proto.Services().ByName("TrustDrawService")
+	trustDrawServiceDealMethodDescriptor       = trustDrawServiceServiceDescriptor.Methods().ByName("Deal")
+	trustDrawServiceVerifyDealMethodDescriptor = trustDrawServiceServiceDescriptor.Methods().ByName("VerifyDeal")
+	trustDrawServiceOpenGameMethodDescriptor   = trustDrawServiceServiceDescriptor.Methods().ByName("OpenGame")
+	trustDrawServiceCloseGameMethodDescriptor  = trustDrawServiceServiceDescriptor.Methods().ByName("CloseGame")
+	trustDrawServiceAllowDrawMethodDescriptor  = trustDrawServiceServiceDescriptor.Methods().ByName("AllowDraw")
+	trustDrawServiceDrawMethodDescriptor       = trustDrawServiceServiceDescriptor.Methods().ByName("Draw")
+	trustDrawServiceVerifyDrawMethodDescriptor = trustDrawServiceServiceDescriptor.Methods().ByName("VerifyDraw")
+	trustDrawServiceGetStateMethodDescriptor   = trustDrawServiceServiceDescriptor.Methods().ByName("GetState")
+	trustDrawServiceSubscribeMethodDescriptor  = trustDrawServiceServiceDescriptor.Methods().ByName("Subscribe")
+)
+
+// TrustDrawServiceClient is a client for the trustdraw.v1.TrustDrawService service.
+type TrustDrawServiceClient interface {
+	// Deal shuffles a set of cards, producing a signed deal file for the given players.
+	Deal(context.Context, *connect.Request[v1.DealRequest]) (*connect.Response[v1.DealResponse], error)
+	// VerifyDeal checks that a deal file was made by the given dealer.
+	VerifyDeal(context.Context, *connect.Request[v1.VerifyDealRequest]) (*connect.Response[v1.VerifyDealResponse], error)
+	// OpenGame opens a deal file for one player, holding the game in memory until it is closed.
+	OpenGame(context.Context, *connect.Request[v1.OpenGameRequest]) (*connect.Response[v1.OpenGameResponse], error)
+	// CloseGame forgets an open game. Save its state first if you'll want to open it again.
+	CloseGame(context.Context, *connect.Request[v1.CloseGameRequest]) (*connect.Response[v1.CloseGameResponse], error)
+	// AllowDraw gets the allowKey that will let a player draw the next card.
+	AllowDraw(context.Context, *connect.Request[v1.AllowDrawRequest]) (*connect.Response[v1.AllowDrawResponse], error)
+	// Draw uses the allowKeys given by the other players to draw a card.
+	Draw(context.Context, *connect.Request[v1.DrawRequest]) (*connect.Response[v1.DrawResponse], error)
+	// VerifyDraw checks a card another player says they drew.
+	VerifyDraw(context.Context, *connect.Request[v1.VerifyDrawRequest]) (*connect.Response[v1.VerifyDrawResponse], error)
+	// GetState returns a game's state, to be stored and given to OpenGame later.
+	GetState(context.Context, *connect.Request[v1.GetStateRequest]) (*connect.Response[v1.GetStateResponse], error)
+	// Subscribe streams the transcript events of an open game as they happen, starting with any
+	// that have already happened.
+	Subscribe(context.Context, *connect.Request[v1.SubscribeRequest]) (*connect.ServerStreamForClient[v1.TranscriptEvent], error)
+}
+
+// NewTrustDrawServiceClient constructs a client for the trustdraw.v1.TrustDrawService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTrustDrawServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TrustDrawServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &trustDrawServiceClient{
+		deal: connect.NewClient[v1.DealRequest, v1.DealResponse](
+			httpClient,
+			baseURL+TrustDrawServiceDealProcedure,
+			connect.WithSchema(trustDrawServiceDealMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		verifyDeal: connect.NewClient[v1.VerifyDealRequest, v1.VerifyDealResponse](
+			httpClient,
+			baseURL+TrustDrawServiceVerifyDealProcedure,
+			connect.WithSchema(trustDrawServiceVerifyDealMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		openGame: connect.NewClient[v1.OpenGameRequest, v1.OpenGameResponse](
+			httpClient,
+			baseURL+TrustDrawServiceOpenGameProcedure,
+			connect.WithSchema(trustDrawServiceOpenGameMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		closeGame: connect.NewClient[v1.CloseGameRequest, v1.CloseGameResponse](
+			httpClient,
+			baseURL+TrustDrawServiceCloseGameProcedure,
+			connect.WithSchema(trustDrawServiceCloseGameMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		allowDraw: connect.NewClient[v1.AllowDrawRequest, v1.AllowDrawResponse](
+			httpClient,
+			baseURL+TrustDrawServiceAllowDrawProcedure,
+			connect.WithSchema(trustDrawServiceAllowDrawMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		draw: connect.NewClient[v1.DrawRequest, v1.DrawResponse](
+			httpClient,
+			baseURL+TrustDrawServiceDrawProcedure,
+			connect.WithSchema(trustDrawServiceDrawMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		verifyDraw: connect.NewClient[v1.VerifyDrawRequest, v1.VerifyDrawResponse](
+			httpClient,
+			baseURL+TrustDrawServiceVerifyDrawProcedure,
+			connect.WithSchema(trustDrawServiceVerifyDrawMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		getState: connect.NewClient[v1.GetStateRequest, v1.GetStateResponse](
+			httpClient,
+			baseURL+TrustDrawServiceGetStateProcedure,
+			connect.WithSchema(trustDrawServiceGetStateMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+		subscribe: connect.NewClient[v1.SubscribeRequest, v1.TranscriptEvent](
+			httpClient,
+			baseURL+TrustDrawServiceSubscribeProcedure,
+			connect.WithSchema(trustDrawServiceSubscribeMethodDescriptor),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// trustDrawServiceClient implements TrustDrawServiceClient.
+type trustDrawServiceClient struct {
+	deal       *connect.Client[v1.DealRequest, v1.DealResponse]
+	verifyDeal *connect.Client[v1.VerifyDealRequest, v1.VerifyDealResponse]
+	openGame   *connect.Client[v1.OpenGameRequest, v1.OpenGameResponse]
+	closeGame  *connect.Client[v1.CloseGameRequest, v1.CloseGameResponse]
+	allowDraw  *connect.Client[v1.AllowDrawRequest, v1.AllowDrawResponse]
+	draw       *connect.Client[v1.DrawRequest, v1.DrawResponse]
+	verifyDraw *connect.Client[v1.VerifyDrawRequest, v1.VerifyDrawResponse]
+	getState   *connect.Client[v1.GetStateRequest, v1.GetStateResponse]
+	subscribe  *connect.Client[v1.SubscribeRequest, v1.TranscriptEvent]
+}
+
+// Deal calls trustdraw.v1.TrustDrawService.Deal.
+func (c *trustDrawServiceClient) Deal(ctx context.Context, req *connect.Request[v1.DealRequest]) (*connect.Response[v1.DealResponse], error) {
+	return c.deal.CallUnary(ctx, req)
+}
+
+// VerifyDeal calls trustdraw.v1.TrustDrawService.VerifyDeal.
+func (c *trustDrawServiceClient) VerifyDeal(ctx context.Context, req *connect.Request[v1.VerifyDealRequest]) (*connect.Response[v1.VerifyDealResponse], error) {
+	return c.verifyDeal.CallUnary(ctx, req)
+}
+
+// OpenGame calls trustdraw.v1.TrustDrawService.OpenGame.
+func (c *trustDrawServiceClient) OpenGame(ctx context.Context, req *connect.Request[v1.OpenGameRequest]) (*connect.Response[v1.OpenGameResponse], error) {
+	return c.openGame.CallUnary(ctx, req)
+}
+
+// CloseGame calls trustdraw.v1.TrustDrawService.CloseGame.
+func (c *trustDrawServiceClient) CloseGame(ctx context.Context, req *connect.Request[v1.CloseGameRequest]) (*connect.Response[v1.CloseGameResponse], error) {
+	return c.closeGame.CallUnary(ctx, req)
+}
+
+// AllowDraw calls trustdraw.v1.TrustDrawService.AllowDraw.
+func (c *trustDrawServiceClient) AllowDraw(ctx context.Context, req *connect.Request[v1.AllowDrawRequest]) (*connect.Response[v1.AllowDrawResponse], error) {
+	return c.allowDraw.CallUnary(ctx, req)
+}
+
+// Draw calls trustdraw.v1.TrustDrawService.Draw.
+func (c *trustDrawServiceClient) Draw(ctx context.Context, req *connect.Request[v1.DrawRequest]) (*connect.Response[v1.DrawResponse], error) {
+	return c.draw.CallUnary(ctx, req)
+}
+
+// VerifyDraw calls trustdraw.v1.TrustDrawService.VerifyDraw.
+func (c *trustDrawServiceClient) VerifyDraw(ctx context.Context, req *connect.Request[v1.VerifyDrawRequest]) (*connect.Response[v1.VerifyDrawResponse], error) {
+	return c.verifyDraw.CallUnary(ctx, req)
+}
+
+// GetState calls trustdraw.v1.TrustDrawService.GetState.
+func (c *trustDrawServiceClient) GetState(ctx context.Context, req *connect.Request[v1.GetStateRequest]) (*connect.Response[v1.GetStateResponse], error) {
+	return c.getState.CallUnary(ctx, req)
+}
+
+// Subscribe calls trustdraw.v1.TrustDrawService.Subscribe.
+func (c *trustDrawServiceClient) Subscribe(ctx context.Context, req *connect.Request[v1.SubscribeRequest]) (*connect.ServerStreamForClient[v1.TranscriptEvent], error) {
+	return c.subscribe.CallServerStream(ctx, req)
+}
+
+// TrustDrawServiceHandler is an implementation of the trustdraw.v1.TrustDrawService service.
+type TrustDrawServiceHandler interface {
+	// Deal shuffles a set of cards, producing a signed deal file for the given players.
+	Deal(context.Context, *connect.Request[v1.DealRequest]) (*connect.Response[v1.DealResponse], error)
+	// VerifyDeal checks that a deal file was made by the given dealer.
+	VerifyDeal(context.Context, *connect.Request[v1.VerifyDealRequest]) (*connect.Response[v1.VerifyDealResponse], error)
+	// OpenGame opens a deal file for one player, holding the game in memory until it is closed.
+	OpenGame(context.Context, *connect.Request[v1.OpenGameRequest]) (*connect.Response[v1.OpenGameResponse], error)
+	// CloseGame forgets an open game. Save its state first if you'll want to open it again.
+	CloseGame(context.Context, *connect.Request[v1.CloseGameRequest]) (*connect.Response[v1.CloseGameResponse], error)
+	// AllowDraw gets the allowKey that will let a player draw the next card.
+	AllowDraw(context.Context, *connect.Request[v1.AllowDrawRequest]) (*connect.Response[v1.AllowDrawResponse], error)
+	// Draw uses the allowKeys given by the other players to draw a card.
+	Draw(context.Context, *connect.Request[v1.DrawRequest]) (*connect.Response[v1.DrawResponse], error)
+	// VerifyDraw checks a card another player says they drew.
+	VerifyDraw(context.Context, *connect.Request[v1.VerifyDrawRequest]) (*connect.Response[v1.VerifyDrawResponse], error)
+	// GetState returns a game's state, to be stored and given to OpenGame later.
+	GetState(context.Context, *connect.Request[v1.GetStateRequest]) (*connect.Response[v1.GetStateResponse], error)
+	// Subscribe streams the transcript events of an open game as they happen, starting with any
+	// that have already happened.
+	Subscribe(context.Context, *connect.Request[v1.SubscribeRequest], *connect.ServerStream[v1.TranscriptEvent]) error
+}
+
+// NewTrustDrawServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTrustDrawServiceHandler(svc TrustDrawServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	trustDrawServiceDealHandler := connect.NewUnaryHandler(
+		TrustDrawServiceDealProcedure,
+		svc.Deal,
+		connect.WithSchema(trustDrawServiceDealMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceVerifyDealHandler := connect.NewUnaryHandler(
+		TrustDrawServiceVerifyDealProcedure,
+		svc.VerifyDeal,
+		connect.WithSchema(trustDrawServiceVerifyDealMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceOpenGameHandler := connect.NewUnaryHandler(
+		TrustDrawServiceOpenGameProcedure,
+		svc.OpenGame,
+		connect.WithSchema(trustDrawServiceOpenGameMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceCloseGameHandler := connect.NewUnaryHandler(
+		TrustDrawServiceCloseGameProcedure,
+		svc.CloseGame,
+		connect.WithSchema(trustDrawServiceCloseGameMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceAllowDrawHandler := connect.NewUnaryHandler(
+		TrustDrawServiceAllowDrawProcedure,
+		svc.AllowDraw,
+		connect.WithSchema(trustDrawServiceAllowDrawMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceDrawHandler := connect.NewUnaryHandler(
+		TrustDrawServiceDrawProcedure,
+		svc.Draw,
+		connect.WithSchema(trustDrawServiceDrawMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceVerifyDrawHandler := connect.NewUnaryHandler(
+		TrustDrawServiceVerifyDrawProcedure,
+		svc.VerifyDraw,
+		connect.WithSchema(trustDrawServiceVerifyDrawMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceGetStateHandler := connect.NewUnaryHandler(
+		TrustDrawServiceGetStateProcedure,
+		svc.GetState,
+		connect.WithSchema(trustDrawServiceGetStateMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	trustDrawServiceSubscribeHandler := connect.NewServerStreamHandler(
+		TrustDrawServiceSubscribeProcedure,
+		svc.Subscribe,
+		connect.WithSchema(trustDrawServiceSubscribeMethodDescriptor),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/trustdraw.v1.TrustDrawService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TrustDrawServiceDealProcedure:
+			trustDrawServiceDealHandler.ServeHTTP(w, r)
+		case TrustDrawServiceVerifyDealProcedure:
+			trustDrawServiceVerifyDealHandler.ServeHTTP(w, r)
+		case TrustDrawServiceOpenGameProcedure:
+			trustDrawServiceOpenGameHandler.ServeHTTP(w, r)
+		case TrustDrawServiceCloseGameProcedure:
+			trustDrawServiceCloseGameHandler.ServeHTTP(w, r)
+		case TrustDrawServiceAllowDrawProcedure:
+			trustDrawServiceAllowDrawHandler.ServeHTTP(w, r)
+		case TrustDrawServiceDrawProcedure:
+			trustDrawServiceDrawHandler.ServeHTTP(w, r)
+		case TrustDrawServiceVerifyDrawProcedure:
+			trustDrawServiceVerifyDrawHandler.ServeHTTP(w, r)
+		case TrustDrawServiceGetStateProcedure:
+			trustDrawServiceGetStateHandler.ServeHTTP(w, r)
+		case TrustDrawServiceSubscribeProcedure:
+			trustDrawServiceSubscribeHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTrustDrawServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTrustDrawServiceHandler struct{}
+
+func (UnimplementedTrustDrawServiceHandler) Deal(context.Context, *connect.Request[v1.DealRequest]) (*connect.Response[v1.DealResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.Deal is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) VerifyDeal(context.Context, *connect.Request[v1.VerifyDealRequest]) (*connect.Response[v1.VerifyDealResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.VerifyDeal is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) OpenGame(context.Context, *connect.Request[v1.OpenGameRequest]) (*connect.Response[v1.OpenGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.OpenGame is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) CloseGame(context.Context, *connect.Request[v1.CloseGameRequest]) (*connect.Response[v1.CloseGameResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.CloseGame is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) AllowDraw(context.Context, *connect.Request[v1.AllowDrawRequest]) (*connect.Response[v1.AllowDrawResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.AllowDraw is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) Draw(context.Context, *connect.Request[v1.DrawRequest]) (*connect.Response[v1.DrawResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.Draw is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) VerifyDraw(context.Context, *connect.Request[v1.VerifyDrawRequest]) (*connect.Response[v1.VerifyDrawResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.VerifyDraw is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) GetState(context.Context, *connect.Request[v1.GetStateRequest]) (*connect.Response[v1.GetStateResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.GetState is not implemented"))
+}
+
+func (UnimplementedTrustDrawServiceHandler) Subscribe(context.Context, *connect.Request[v1.SubscribeRequest], *connect.ServerStream[v1.TranscriptEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("trustdraw.v1.TrustDrawService.Subscribe is not implemented"))
+}
@@ -2,8 +2,11 @@ package trustdraw
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
 	crand "crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -12,7 +15,10 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sort"
 	"strings"
+
+	"github.com/jphastings/trustdraw/internal/x25519"
 )
 
 func xor(keys ...[]byte) []byte {
@@ -26,7 +32,7 @@ func xor(keys ...[]byte) []byte {
 	return fullKey
 }
 
-// extractStanzas splits a deal file into its 4 stanzas, verifying that the declared
+// extractStanzas splits a deal file into its 4 stanzas, followed by any seats added to it, verifying that the declared
 // version is one this code can read.
 func extractStanzas(dealFile io.Reader) ([]string, error) {
 	data, err := io.ReadAll(dealFile)
@@ -35,7 +41,7 @@ func extractStanzas(dealFile io.Reader) ([]string, error) {
 	}
 
 	stanzas := strings.Split(string(data), "\n\n")
-	if len(stanzas) != 4 {
+	if len(stanzas) < 4 {
 		return nil, fmt.Errorf("deal file not valid")
 	}
 
@@ -49,7 +55,7 @@ func extractStanzas(dealFile io.Reader) ([]string, error) {
 // fromAllowKey converts an allowKey to a card ID and a card's AES key.
 func fromAllowKey(allowKey string) (int, []byte, error) {
 	akBytes, err := base64.RawStdEncoding.DecodeString(allowKey)
-	if err != nil {
+	if err != nil || len(akBytes) != 2+aesCipherSize {
 		return 0, nil, fmt.Errorf("invalid allowKey")
 	}
 
@@ -64,6 +70,39 @@ func toAllowKey(cardID int, secretCard []byte) string {
 	return base64.RawStdEncoding.EncodeToString(append(cardIDBytes, secretCard...))
 }
 
+// allowKeysCardID checks that all the allowKeys are for the same card, returning its ID.
+func allowKeysCardID(allowKeys []string) (int, error) {
+	if len(allowKeys) == 0 {
+		return 0, fmt.Errorf("no allowKeys given")
+	}
+
+	var cardID int
+	for i, allowKey := range allowKeys {
+		thisCardID, _, err := fromAllowKey(allowKey)
+		if err != nil {
+			return 0, err
+		}
+
+		if i == 0 {
+			cardID = thisCardID
+		} else if cardID != thisCardID {
+			return 0, fmt.Errorf("allowKeys are not for the same card")
+		}
+	}
+
+	return cardID, nil
+}
+
+// sortedCardIDs lists the card IDs used as keys of the given map, in order.
+func sortedCardIDs[T any](m map[int]T) []int {
+	cardIDs := make([]int, 0, len(m))
+	for cardID := range m {
+		cardIDs = append(cardIDs, cardID)
+	}
+	sort.Ints(cardIDs)
+	return cardIDs
+}
+
 // allowKeysToCardKey combines the allowKeys shared by other players to re-create the card key needed to decrypt the indicated card.
 func (d *Game) allowKeysToCardKey(allowKeys []string) (int, cipher.Block, error) {
 	keys := make([][]byte, len(allowKeys))
@@ -82,9 +121,36 @@ func (d *Game) allowKeysToCardKey(allowKeys []string) (int, cipher.Block, error)
 
 		keys[i] = secretCard
 	}
+	if cardID >= len(d.cards) {
+		return 0, nil, fmt.Errorf("allowKeys are for a card that isn't in this deck")
+	}
 
-	// Make cipher from all the keys XORed together with this user's key for this card.
-	cardKey, err := aes.NewCipher(xor(append(keys, d.keys[cardID])...))
+	var combined []byte
+	if d.role == RolePlayer {
+		// Make cipher from all the keys XORed together with this user's key for this card, and the recovered key shares
+		// of any players who have left. This user's own allowKey (or a recovered one) may be amongst them, if they were
+		// given every player's, so repeats are left out rather than cancelling themselves out.
+		shares := [][]byte{d.keys[cardID]}
+		for _, player := range sortedPlayers(d.recovered) {
+			if share, ok := d.recovered[player][cardID]; ok {
+				shares = append(shares, share)
+			}
+		}
+		for _, key := range keys {
+			if !containsShare(shares, key) {
+				shares = append(shares, key)
+			}
+		}
+		combined = xor(shares...)
+	} else {
+		// Observers have no share of their own, but can check that every player's shares make the card's key.
+		combined = xor(keys...)
+		if !bytes.Equal(observerCheck(combined), d.keys[cardID]) {
+			return 0, nil, fmt.Errorf("the allowKeys don't make the card's key, every player's allowKey for it is needed")
+		}
+	}
+
+	cardKey, err := aes.NewCipher(combined)
 	if err != nil {
 		return 0, nil, fmt.Errorf("internal error; could not re-create card key cipher")
 	}
@@ -92,6 +158,15 @@ func (d *Game) allowKeysToCardKey(allowKeys []string) (int, cipher.Block, error)
 	return cardID, cardKey, nil
 }
 
+func containsShare(shares [][]byte, share []byte) bool {
+	for _, s := range shares {
+		if bytes.Equal(s, share) {
+			return true
+		}
+	}
+	return false
+}
+
 // decryptCard turns decrypts the referenced card with the given cardKey.
 // TODO: Add an HMAC so I can know I've decrypted them properly
 func (g *Game) decryptCard(cardID int, cardKey cipher.Block) (string, error) {
@@ -101,25 +176,14 @@ func (g *Game) decryptCard(cardID int, cardKey cipher.Block) (string, error) {
 	return strings.Trim(string(card), "\x00"), nil
 }
 
-// decryptCardKeys decrypts the given card key block with the given player's RSA public key.
-func decryptCardKeys(playerData []byte, prv *rsa.PrivateKey, cardCount int) ([][]byte, error) {
-	encAESKey := playerData[:rsaBits/8]
-	cipherText := playerData[rsaBits/8:]
-
-	aesKey, err := rsa.DecryptOAEP(sha256.New(), crand.Reader, prv, encAESKey, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	blk, err := aes.NewCipher(aesKey)
+// decryptCardKeys decrypts the given card key block with the given player's private key. The key stack comes first,
+// but the block may hold more (like escrowed key shares) after it.
+func decryptCardKeys(playerData []byte, prv crypto.Signer, cardCount int) ([][]byte, error) {
+	plainText, err := unseal(playerData, prv)
 	if err != nil {
 		return nil, err
 	}
 
-	plainText := make([]byte, len(cipherText))
-	stream := cipher.NewCTR(blk, cipherText[:aes.BlockSize])
-	stream.XORKeyStream(plainText, cipherText[aes.BlockSize:])
-
 	keys := make([][]byte, len(plainText)/aesCipherSize)
 
 	var j int
@@ -129,7 +193,53 @@ func decryptCardKeys(playerData []byte, prv *rsa.PrivateKey, cardCount int) ([][
 		keys[i/aesCipherSize] = plainText[i:j]
 	}
 
-	return keys[0:cardCount], nil
+	if len(keys) < cardCount {
+		return nil, fmt.Errorf("key stack is too short")
+	}
+
+	return keys, nil
+}
+
+// unseal decrypts data that was encrypted with seal, for the holder of the given private key.
+func unseal(data []byte, prv crypto.Signer) ([]byte, error) {
+	switch pub := prv.Public().(type) {
+	case *rsa.PublicKey:
+		decrypter, ok := prv.(crypto.Decrypter)
+		if !ok {
+			return nil, fmt.Errorf("the player's RSA key can't decrypt")
+		}
+		return unsealRSA(data, pub, decrypter)
+	case ed25519.PublicKey:
+		return unsealX25519(data, pub, prv)
+	default:
+		return nil, fmt.Errorf("player keys must be RSA or Ed25519 keys")
+	}
+}
+
+// unsealRSA decrypts data that was encrypted with sealRSA. The key can be held elsewhere (eg. by an agent), as long
+// as it can decrypt RSA-OAEP.
+func unsealRSA(data []byte, pub *rsa.PublicKey, prv crypto.Decrypter) ([]byte, error) {
+	if len(data) < pub.Size()+aes.BlockSize {
+		return nil, fmt.Errorf("sealed data is too short")
+	}
+	encAESKey := data[:pub.Size()]
+	cipherText := data[pub.Size():]
+
+	aesKey, err := prv.Decrypt(crand.Reader, encAESKey, &rsa.OAEPOptions{Hash: crypto.SHA256})
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText := make([]byte, len(cipherText)-aes.BlockSize)
+	stream := cipher.NewCTR(blk, cipherText[:aes.BlockSize])
+	stream.XORKeyStream(plainText, cipherText[aes.BlockSize:])
+
+	return plainText, nil
 }
 
 // shuffle shuffles a slice in-place.
@@ -167,10 +277,33 @@ func generateCardKeys(n int) ([][]byte, cipher.Block, error) {
 	return playerKeys, blk, nil
 }
 
-// encryptCardKeys encrypts the given card keys for one player's eyes only, using the given RSA public key.
-func encryptCardKeys(cardKeys [][]byte, pub *rsa.PublicKey) ([]byte, error) {
-	plain := bytes.Join(cardKeys, nil)
+// observerCheck derives the value observers are given in place of a card key share, which lets them check a card key
+// put together from every player's share, without being a share itself.
+func observerCheck(cardKey []byte) []byte {
+	sum := sha256.Sum256(append([]byte("TrustDraw observer check\x00"), cardKey...))
+	return sum[:aesCipherSize]
+}
+
+// encryptCardKeys encrypts the given card keys for one player's eyes only, using the given public key.
+func encryptCardKeys(cardKeys [][]byte, pub crypto.PublicKey) ([]byte, error) {
+	return seal(bytes.Join(cardKeys, nil), pub)
+}
+
+// seal encrypts arbitrary data for one player's eyes only, using their RSA or Ed25519 public key.
+func seal(plain []byte, pub crypto.PublicKey) ([]byte, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return sealRSA(plain, k)
+	case ed25519.PublicKey:
+		return sealX25519(plain, k)
+	default:
+		return nil, fmt.Errorf("player keys must be RSA or Ed25519 keys")
+	}
+}
 
+// sealRSA encrypts data with an RSA public key.
+// (`AES-128-CTR` preceeded by `RSA(key)`)
+func sealRSA(plain []byte, pub *rsa.PublicKey) ([]byte, error) {
 	aesKey := make([]byte, aesCipherSize)
 	if _, err := crand.Read(aesKey); err != nil {
 		return nil, err
@@ -196,3 +329,93 @@ func encryptCardKeys(cardKeys [][]byte, pub *rsa.PublicKey) ([]byte, error) {
 
 	return append(asymKey, cipherText...), nil
 }
+
+// ecdhKey is a private key that can agree on a shared secret with X25519, like *ecdh.PrivateKey or an Ed25519 key
+// held by an agent.
+type ecdhKey interface {
+	ECDH(remote *ecdh.PublicKey) ([]byte, error)
+}
+
+// sealX25519 encrypts data with an Ed25519 public key, using a key agreed (with X25519) between it and a new
+// ephemeral key. (`AES-128-GCM` preceeded by the ephemeral public key)
+func sealX25519(plain []byte, pub ed25519.PublicKey) ([]byte, error) {
+	recipient, err := x25519.PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := ecdh.X25519().GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := x25519AEAD(shared, ephemeral.PublicKey(), recipient)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(ephemeral.PublicKey().Bytes(), make([]byte, aead.NonceSize()), plain, nil), nil
+}
+
+// unsealX25519 decrypts data that was encrypted with sealX25519. The key can be an ed25519.PrivateKey, or held
+// elsewhere (eg. by an agent) as long as it can perform X25519 ECDH.
+func unsealX25519(data []byte, pub ed25519.PublicKey, prv crypto.Signer) ([]byte, error) {
+	var key ecdhKey
+	switch k := prv.(type) {
+	case ed25519.PrivateKey:
+		x25519Prv, err := x25519.PrivateKey(k)
+		if err != nil {
+			return nil, err
+		}
+		key = x25519Prv
+	case ecdhKey:
+		key = k
+	default:
+		return nil, fmt.Errorf("the player's Ed25519 key can't decrypt")
+	}
+
+	if len(data) < x25519KeySize {
+		return nil, fmt.Errorf("sealed data is too short")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(data[:x25519KeySize])
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := x25519.PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := x25519AEAD(shared, ephemeral, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, aead.NonceSize()), data[x25519KeySize:], nil)
+}
+
+// x25519AEAD makes the cipher for data sealed with an agreed X25519 secret. As every ephemeral key (and so every
+// cipher key) is only used once, the nonce is always zero.
+func x25519AEAD(shared []byte, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	hash := sha256.New()
+	hash.Write(shared)
+	hash.Write(ephemeral.Bytes())
+	hash.Write(recipient.Bytes())
+
+	blk, err := aes.NewCipher(hash.Sum(nil)[:aesCipherSize])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(blk)
+}
+
+// dealID derives a short, URL-safe identifier for a deal from its signature stanza.
+func dealID(signature string) string {
+	sum := sha256.Sum256([]byte(signature))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
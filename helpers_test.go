@@ -0,0 +1,82 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+// testDeck is a small deck, so tests run quickly.
+var testDeck = []string{"A♠️", "2♠️", "3♠️", "4♠️", "5♠️", "6♠️", "7♠️", "8♠️", "9♠️", "10♠️"}
+
+// testKey makes a new Ed25519 key, which is much quicker to make than an RSA one.
+func testKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	_, prv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prv
+}
+
+// testDeal deals testDeck to the given number of players, returning the deal file, the players' private keys and the
+// dealer's.
+func testDeal(t *testing.T, players int, opts DealOptions) ([]byte, []crypto.Signer, crypto.Signer) {
+	t.Helper()
+	prvs := make([]crypto.Signer, players)
+	roster := make([]Player, players)
+	for i := range prvs {
+		prvs[i] = testKey(t)
+		roster[i] = Player{PublicKey: prvs[i].Public()}
+	}
+
+	dealerPrv := testKey(t)
+	var deal bytes.Buffer
+	if err := DealWith(&deal, append([]string(nil), testDeck...), dealerPrv, opts, roster...); err != nil {
+		t.Fatalf("could not deal: %v", err)
+	}
+	return deal.Bytes(), prvs, dealerPrv
+}
+
+// testGames deals testDeck to the given number of players, and opens the game for each of them.
+func testGames(t *testing.T, players int, opts DealOptions) ([]*Game, []crypto.Signer) {
+	t.Helper()
+	deal, prvs, _ := testDeal(t, players, opts)
+	games := make([]*Game, players)
+	for i, prv := range prvs {
+		games[i] = openTestGame(t, deal, prv, "")
+	}
+	return games, prvs
+}
+
+// openTestGame opens the deal for the holder of the given key, with the given state.
+func openTestGame(t *testing.T, deal []byte, prv crypto.Signer, state string) *Game {
+	t.Helper()
+	game, err := OpenGame(bytes.NewReader(deal), prv, state)
+	if err != nil {
+		t.Fatalf("could not open game: %v", err)
+	}
+	return game
+}
+
+// testDraw has every other player allow the given player to draw the next card, and draws it.
+func testDraw(t *testing.T, games []*Game, drawer PlayerNumber) (card string, allowKey string, allowKeys []string) {
+	t.Helper()
+	for _, game := range games {
+		if game.PlayerNumber() == drawer {
+			continue
+		}
+		allowKey, err := game.AllowDraw(drawer)
+		if err != nil {
+			t.Fatalf("player %d could not allow a draw: %v", game.PlayerNumber(), err)
+		}
+		allowKeys = append(allowKeys, allowKey)
+	}
+	card, allowKey, _, err := games[drawer-1].Draw(allowKeys...)
+	if err != nil {
+		t.Fatalf("player %d could not draw: %v", drawer, err)
+	}
+	return card, allowKey, allowKeys
+}
@@ -1,126 +1,212 @@
 package cmdhelpers
 
 import (
-	"crypto/ed25519"
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/agent"
+	"github.com/jphastings/trustdraw/statestore"
+	"golang.org/x/crypto/ssh"
 )
 
-func LoadDealerPrivateKey(path string) (ed25519.PrivateKey, error) {
-	pemBytes, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("could not read dealer key (%s): %w", path, err)
-	}
+// LoadDealerPrivateKey loads the dealer's private key, asking for its passphrase if it is encrypted.
+// If path holds the dealer's public key instead, the private key held by the running agent is used.
+func LoadDealerPrivateKey(path string) (crypto.Signer, error) {
+	return loadPrivateKey(path, "dealer")
+}
 
-	pemBlock, _ := pem.Decode(pemBytes)
-	if pemBlock == nil {
-		return nil, fmt.Errorf("invalid dealer PEM file (%s)", path)
-	}
+// ParseDealerPrivateKey parses a dealer key in any format ParseKey understands; path is only used to describe it in errors.
+func ParseDealerPrivateKey(keyBytes []byte, path string) (crypto.Signer, error) {
+	return parsePrivateKey(keyBytes, "dealer", path)
+}
 
-	if pemBlock.Type != "PRIVATE KEY" {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a private Ed25519 key", path)
-	}
+// LoadDealerPublicKey loads the dealer's public key. If path doesn't exist, but path.keys does (like the
+// github.com/<user>.keys list of someone's SSH keys), their first RSA or Ed25519 key is used.
+func LoadDealerPublicKey(path string) (crypto.PublicKey, error) {
+	return loadPublicKey(path, "dealer")
+}
 
-	key, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a private Ed25519 key", path)
-	}
+// ParseDealerPublicKey parses a dealer key in any format ParseKey understands; path is only used to describe it in errors.
+func ParseDealerPublicKey(keyBytes []byte, path string) (crypto.PublicKey, error) {
+	return parsePublicKey(keyBytes, "dealer", path)
+}
 
-	edKey, ok := key.(ed25519.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a private Ed25519 key", path)
-	}
+// LoadPlayerPrivateKey loads a player's private key, asking for its passphrase if it is encrypted.
+// If path holds the player's public key instead, the private key held by the running agent is used.
+func LoadPlayerPrivateKey(path string) (crypto.Signer, error) {
+	return loadPrivateKey(path, "player")
+}
 
-	return edKey, nil
+// ParsePlayerPrivateKey parses a player key in any format ParseKey understands; path is only used to describe it in errors.
+func ParsePlayerPrivateKey(keyBytes []byte, path string) (crypto.Signer, error) {
+	return parsePrivateKey(keyBytes, "player", path)
 }
 
-func LoadDealerPublicKey(path string) (ed25519.PublicKey, error) {
-	pemBytes, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("could not read dealer key (%s): %w", path, err)
-	}
+// LoadPlayerPublicKey loads a player's public key. If path doesn't exist, but path.keys does (like the
+// github.com/<user>.keys list of someone's SSH keys), their first RSA or Ed25519 key is used. This means
+// players can be named by username, given a directory of their .keys files.
+func LoadPlayerPublicKey(path string) (crypto.PublicKey, error) {
+	return loadPublicKey(path, "player")
+}
 
-	pemBlock, _ := pem.Decode(pemBytes)
-	if pemBlock == nil {
-		return nil, fmt.Errorf("invalid dealer PEM file (%s)", path)
-	}
+// ParsePlayerPublicKey parses a player key in any format ParseKey understands; path is only used to describe it in errors.
+func ParsePlayerPublicKey(keyBytes []byte, path string) (crypto.PublicKey, error) {
+	return parsePublicKey(keyBytes, "player", path)
+}
 
-	if pemBlock.Type != "PUBLIC KEY" {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a public Ed25519 key", path)
-	}
+// LoadObserverPublicKey loads an observer's public key, as LoadPlayerPublicKey does.
+func LoadObserverPublicKey(path string) (crypto.PublicKey, error) {
+	return loadPublicKey(path, "observer")
+}
 
-	key, err := x509.ParsePKIXPublicKey(pemBlock.Bytes)
+// LoadPrivateKey loads either a player's or the dealer's private key, asking for its passphrase if it is encrypted.
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	key, _, err := LoadKey(path)
 	if err != nil {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a public Ed25519 key", path)
+		return nil, err
 	}
-
-	edKey, ok := key.(ed25519.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("dealer PEM file (%s) is not a public Ed25519 key", path)
-	}
-
-	return edKey, nil
+	return usableKey[crypto.Signer](key, "", path, true)
 }
 
-func LoadPlayerPrivateKey(path string) (*rsa.PrivateKey, error) {
-	pemBytes, err := os.ReadFile(path)
+func loadPrivateKey(path, role string) (crypto.Signer, error) {
+	key, _, err := loadKey(path, role)
 	if err != nil {
-		return nil, fmt.Errorf("could not read player key (%s): %w", path, err)
+		return nil, err
 	}
 
-	pemBlock, _ := pem.Decode(pemBytes)
-	if pemBlock == nil {
-		return nil, fmt.Errorf("invalid player PEM file (%s)", path)
+	if IsUsableKey(key) && !IsPrivateKey(key) {
+		return agentKey(key, path)
 	}
+	return usableKey[crypto.Signer](key, role, path, true)
+}
 
-	if pemBlock.Type != "PRIVATE KEY" {
-		return nil, fmt.Errorf("player PEM file (%s) is not a private RSA key", path)
+func parsePrivateKey(keyBytes []byte, role, path string) (crypto.Signer, error) {
+	key, err := parseKey(keyBytes, role, path)
+	if err != nil {
+		return nil, err
 	}
+	return usableKey[crypto.Signer](key, role, path, true)
+}
 
-	key, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
+func loadPublicKey(path, role string) (crypto.PublicKey, error) {
+	keyBytes, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && filepath.Ext(path) == "" {
+		if keysBytes, keysErr := os.ReadFile(path + ".keys"); keysErr == nil {
+			keyBytes, err, path = keysBytes, nil, path+".keys"
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("player PEM file (%s) is not a private RSA key", path)
+		return nil, fmt.Errorf("could not read %s key (%s): %w", role, path, err)
 	}
 
-	rsaKey, ok := key.(*rsa.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("player PEM file (%s) is not a private RSA key", path)
+	return parsePublicKey(keyBytes, role, path)
+}
+
+func parsePublicKey(keyBytes []byte, role, path string) (crypto.PublicKey, error) {
+	key, err := parseKey(keyBytes, role, path)
+	if err != nil {
+		return nil, err
 	}
+	return usableKey[crypto.PublicKey](key, role, path, false)
+}
 
-	return rsaKey, nil
+// LoadKey loads a key of any kind, in any format ParseKey understands, asking for its passphrase if it is encrypted.
+// The name of the key's format is returned too.
+func LoadKey(path string) (key any, format string, err error) {
+	return loadKey(path, "")
 }
 
-func LoadPlayerPublicKey(path string) (*rsa.PublicKey, error) {
-	pemBytes, err := os.ReadFile(path)
+func loadKey(path, role string) (any, string, error) {
+	name := strings.TrimSpace(role + " key")
+
+	keyBytes, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("could not read player key (%s): %w", path, err)
+		return nil, "", fmt.Errorf("could not read %s (%s): %w", name, path, err)
 	}
 
-	pemBlock, _ := pem.Decode(pemBytes)
-	if pemBlock == nil {
-		return nil, fmt.Errorf("invalid player PEM file (%s)", path)
+	pemBlock, _ := pem.Decode(keyBytes)
+	switch {
+	case pemBlock != nil && pemBlock.Type == "ENCRYPTED PRIVATE KEY":
+		passphrase, err := ReadPassphrase(fmt.Sprintf("Passphrase for %s: ", path))
+		if err != nil {
+			return nil, "", err
+		}
+		der, err := DecryptPKCS8(pemBlock.Bytes, passphrase)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not decrypt %s (%s): %w", name, path, err)
+		}
+		key, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid %s (%s): %w", name, path, err)
+		}
+		return normalizeKey(key), "PKCS #8 (encrypted)", nil
+	case pemBlock != nil && pemBlock.Type == "OPENSSH PRIVATE KEY":
+		key, err := ssh.ParseRawPrivateKey(keyBytes)
+		var missing *ssh.PassphraseMissingError
+		if !errors.As(err, &missing) {
+			break
+		}
+
+		passphrase, err := ReadPassphrase(fmt.Sprintf("Passphrase for %s: ", path))
+		if err != nil {
+			return nil, "", err
+		}
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(keyBytes, passphrase)
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			err = ErrIncorrectPassphrase
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("could not decrypt %s (%s): %w", name, path, err)
+		}
+		return normalizeKey(key), "OpenSSH (encrypted)", nil
 	}
 
-	if pemBlock.Type != "PUBLIC KEY" {
-		return nil, fmt.Errorf("player PEM file (%s) is not a public RSA key", path)
+	key, format, err := ParseKey(keyBytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s (%s): %w", name, path, err)
 	}
+	return key, format, nil
+}
 
-	key, err := x509.ParsePKIXPublicKey(pemBlock.Bytes)
+func parseKey(keyBytes []byte, role, path string) (any, error) {
+	key, _, err := ParseKey(keyBytes)
 	if err != nil {
-		return nil, fmt.Errorf("player PEM file (%s) is not a public RSA key", path)
+		return nil, fmt.Errorf("invalid %s key (%s): %w", role, path, err)
 	}
+	return key, nil
+}
 
-	rsaKey, ok := key.(*rsa.PublicKey)
+// agentKey finds the private key for pub held by the running agent.
+func agentKey(pub crypto.PublicKey, path string) (*agent.Key, error) {
+	client, ok := agent.FromEnv()
 	if !ok {
-		return nil, fmt.Errorf("player PEM file (%s) is not a public RSA key", path)
+		return nil, fmt.Errorf("%s is a public key, but no agent is running to hold its private key (%s is not set)", path, agent.SocketEnv)
+	}
+
+	key, err := client.Key(pub)
+	if errors.Is(err, agent.ErrKeyNotFound) {
+		return nil, fmt.Errorf("the agent doesn't hold the private key for %s, add it with `trustdraw agent add`", path)
 	}
+	return key, err
+}
 
-	return rsaKey, nil
+// SplitNamedKey splits a "name=path" key argument. Without a name, the player is named after the key file, so
+// "keys/alice.pub.pem" and "alice" (for alice.keys) are both called "alice".
+func SplitNamedKey(arg string) (name, keyPath string) {
+	if name, keyPath, ok := strings.Cut(arg, "="); ok && !strings.ContainsRune(name, '/') {
+		return name, keyPath
+	}
+	return strings.SplitN(path.Base(arg), ".", 2)[0], arg
 }
 
 // StateFile returns a default name for the state file of a game/player combination
@@ -135,22 +221,41 @@ func StateFile(explicit, dealFilePath, playerKeyPath string) string {
 	return fmt.Sprintf("%s.%s.state", deal[0], player[0])
 }
 
-func ReadOrMake(path string) (string, bool, error) {
+// StateStore opens the encrypted state file at path for the given deal and player, creating it (and reporting that it
+// did) if it doesn't exist yet. The deal file is read to find its ID, then rewound.
+func StateStore(path string, dealFile io.ReadSeeker, playerPrv crypto.Signer) (*statestore.Encrypted, bool, error) {
+	made, err := makeStateFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	gameID, err := trustdraw.DealID(dealFile)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := dealFile.Seek(0, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+
+	store, err := statestore.NewEncrypted(statestore.NewFile(path), playerPrv, gameID)
+	return store, made, err
+}
+
+// makeStateFile checks the state file at path is writeable, creating it if it doesn't exist yet.
+func makeStateFile(path string) (bool, error) {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
 		file, err := os.Create(path)
 		if err != nil {
-			return "", false, err
+			return false, err
 		}
-		return "", true, file.Close()
+		return true, file.Close()
 	} else if err != nil {
-		return "", false, err
+		return false, err
 	}
 
 	if info.Mode().Perm()&0200 == 0 {
-		return "", false, fmt.Errorf("the path (%s) is not writeable", path)
+		return false, fmt.Errorf("the path (%s) is not writeable", path)
 	}
-
-	data, err := os.ReadFile(path)
-	return string(data), false, err
+	return false, nil
 }
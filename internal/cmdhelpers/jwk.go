@@ -0,0 +1,155 @@
+package cmdhelpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// jwk holds the fields of a JSON Web Key (RFC 7517) that TrustDraw keys use.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	// OKP (Ed25519) keys
+	X string `json:"x,omitempty"`
+	// RSA keys
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+	// Private part of both
+	D string `json:"d,omitempty"`
+}
+
+// IsJWK reports whether the given key data looks like a JSON Web Key rather than PEM.
+func IsJWK(data []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(data)), "{")
+}
+
+// ParseJWK parses a JSON Web Key, returning an ed25519.PrivateKey, ed25519.PublicKey, *rsa.PrivateKey or *rsa.PublicKey.
+func ParseJWK(data []byte) (any, error) {
+	var key jwk
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+
+	switch key.Kty {
+	case "OKP":
+		if key.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported JWK curve: %s", key.Crv)
+		}
+		x, err := jwkBytes(key.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("JWK Ed25519 public key is the wrong size")
+		}
+		if key.D == "" {
+			return ed25519.PublicKey(x), nil
+		}
+
+		d, err := jwkBytes(key.D, "d")
+		if err != nil {
+			return nil, err
+		}
+		if len(d) != ed25519.SeedSize {
+			return nil, fmt.Errorf("JWK Ed25519 private key is the wrong size")
+		}
+		return ed25519.NewKeyFromSeed(d), nil
+	case "RSA":
+		n, err := jwkInt(key.N, "n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := jwkInt(key.E, "e")
+		if err != nil {
+			return nil, err
+		}
+		pub := rsa.PublicKey{N: n, E: int(e.Int64())}
+		if key.D == "" {
+			return &pub, nil
+		}
+
+		prv := rsa.PrivateKey{PublicKey: pub}
+		if prv.D, err = jwkInt(key.D, "d"); err != nil {
+			return nil, err
+		}
+		for _, prime := range []struct{ value, name string }{{key.P, "p"}, {key.Q, "q"}} {
+			p, err := jwkInt(prime.value, prime.name)
+			if err != nil {
+				return nil, err
+			}
+			prv.Primes = append(prv.Primes, p)
+		}
+		if err := prv.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid JWK RSA private key: %w", err)
+		}
+		prv.Precompute()
+		return &prv, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type: %s", key.Kty)
+	}
+}
+
+// MarshalJWK encodes an ed25519.PrivateKey, ed25519.PublicKey, *rsa.PrivateKey or *rsa.PublicKey as a JSON Web Key.
+func MarshalJWK(key any) ([]byte, error) {
+	var out jwk
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		out = jwk{Kty: "OKP", Crv: "Ed25519", X: jwkEncode(k.Public().(ed25519.PublicKey)), D: jwkEncode(k.Seed())}
+	case ed25519.PublicKey:
+		out = jwk{Kty: "OKP", Crv: "Ed25519", X: jwkEncode(k)}
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("multi-prime RSA keys can't be encoded as JWKs")
+		}
+		k.Precompute()
+		out = jwk{
+			Kty: "RSA",
+			N:   jwkEncode(k.N.Bytes()),
+			E:   jwkEncode(big.NewInt(int64(k.E)).Bytes()),
+			D:   jwkEncode(k.D.Bytes()),
+			P:   jwkEncode(k.Primes[0].Bytes()),
+			Q:   jwkEncode(k.Primes[1].Bytes()),
+			DP:  jwkEncode(k.Precomputed.Dp.Bytes()),
+			DQ:  jwkEncode(k.Precomputed.Dq.Bytes()),
+			QI:  jwkEncode(k.Precomputed.Qinv.Bytes()),
+		}
+	case *rsa.PublicKey:
+		out = jwk{Kty: "RSA", N: jwkEncode(k.N.Bytes()), E: jwkEncode(big.NewInt(int64(k.E)).Bytes())}
+	default:
+		return nil, fmt.Errorf("%s can't be encoded as a JWK", DescribeKey(key))
+	}
+	return json.Marshal(out)
+}
+
+func jwkEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func jwkBytes(value, name string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("JWK is missing '%s'", name)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("JWK '%s' is badly formed", name)
+	}
+	return data, nil
+}
+
+func jwkInt(value, name string) (*big.Int, error) {
+	data, err := jwkBytes(value, name)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
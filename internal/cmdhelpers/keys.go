@@ -0,0 +1,257 @@
+package cmdhelpers
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ParseKey parses a key in any of the formats TrustDraw understands, returning it along with the name of its format.
+//
+// Private keys can be PEM encoded PKCS #8, PKCS #1 (RSA), SEC 1 (EC) or OpenSSH keys, or JWKs. Public keys can be
+// PEM encoded PKIX or PKCS #1 keys, OpenSSH authorized_keys lines (the first RSA or Ed25519 key is used, so
+// github.com/<user>.keys files can be used as they are), or JWKs. Encrypted keys must be decrypted first.
+//
+// The key is returned as an *rsa.PrivateKey, *rsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey,
+// *ecdsa.PrivateKey or *ecdsa.PublicKey.
+func ParseKey(data []byte) (key any, format string, err error) {
+	if IsJWK(data) {
+		key, err := ParseJWK(data)
+		return key, "JWK", err
+	}
+
+	if pemBlock, _ := pem.Decode(data); pemBlock != nil {
+		if strings.Contains(pemBlock.Headers["Proc-Type"], "ENCRYPTED") {
+			return nil, "", fmt.Errorf("legacy encrypted PEM keys aren't supported, convert it with `openssl pkcs8 -topk8`")
+		}
+
+		switch pemBlock.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
+			format = "PKCS #8"
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(pemBlock.Bytes)
+			format = "PKCS #1"
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(pemBlock.Bytes)
+			format = "SEC 1"
+		case "OPENSSH PRIVATE KEY":
+			key, err = ssh.ParseRawPrivateKey(data)
+			format = "OpenSSH"
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(pemBlock.Bytes)
+			format = "PKIX"
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(pemBlock.Bytes)
+			format = "PKCS #1"
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, "", fmt.Errorf("the key is encrypted")
+		default:
+			return nil, "", fmt.Errorf("unsupported PEM block type: %s", pemBlock.Type)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid %s key: %w", format, err)
+		}
+		return normalizeKey(key), format, nil
+	}
+
+	if key, err := parseAuthorizedKeys(data); err == nil {
+		return key, "OpenSSH", nil
+	}
+
+	return nil, "", fmt.Errorf("not a PEM, JWK or OpenSSH key")
+}
+
+// parseAuthorizedKeys parses one or more authorized_keys lines, like those in a github.com/<user>.keys file,
+// returning the first RSA or Ed25519 key. If there are none, the first key is returned.
+func parseAuthorizedKeys(data []byte) (any, error) {
+	var first any
+	for rest := data; len(rest) > 0; {
+		sshPub, _, _, next, err := ssh.ParseAuthorizedKey(rest)
+		if err != nil {
+			break
+		}
+		rest = next
+
+		cryptoPub, ok := sshPub.(ssh.CryptoPublicKey)
+		if !ok {
+			continue
+		}
+		key := normalizeKey(cryptoPub.CryptoPublicKey())
+		if IsUsableKey(key) {
+			return key, nil
+		}
+		if first == nil {
+			first = key
+		}
+	}
+
+	if first == nil {
+		return nil, fmt.Errorf("no OpenSSH keys found")
+	}
+	return first, nil
+}
+
+// normalizeKey makes sure Ed25519 keys aren't pointers, as some parsers return.
+func normalizeKey(key any) any {
+	switch k := key.(type) {
+	case *ed25519.PrivateKey:
+		return *k
+	case *ed25519.PublicKey:
+		return *k
+	}
+	return key
+}
+
+// usableKey checks that a parsed key is an RSA or Ed25519 key, and private or public as needed, explaining what it is
+// if not. T is the type to return it as, crypto.Signer or crypto.PublicKey.
+func usableKey[T any](key any, role, path string, private bool) (T, error) {
+	name := strings.TrimSpace(role + " key")
+	want := "public"
+	if private {
+		want = "private"
+	}
+
+	var none T
+	if !IsUsableKey(key) {
+		return none, fmt.Errorf("%s (%s) is not an RSA or Ed25519 key, it's %s", name, path, DescribeKey(key))
+	}
+	if IsPrivateKey(key) != private {
+		err := fmt.Errorf("%s (%s) is not a %s key, it's %s", name, path, want, DescribeKey(key))
+		if !private {
+			return none, fmt.Errorf("%w (share its public key, from `trustdraw key export --public %s`)", err, path)
+		}
+		return none, err
+	}
+	return key.(T), nil
+}
+
+// DescribeKey names the type of a parsed key, eg. "a 2048-bit RSA private key".
+func DescribeKey(key any) string {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return fmt.Sprintf("a %d-bit RSA private key", k.N.BitLen())
+	case *rsa.PublicKey:
+		return fmt.Sprintf("a %d-bit RSA public key", k.N.BitLen())
+	case ed25519.PrivateKey:
+		return "an Ed25519 private key"
+	case ed25519.PublicKey:
+		return "an Ed25519 public key"
+	case *ecdsa.PrivateKey:
+		return fmt.Sprintf("an ECDSA (%s) private key", k.Curve.Params().Name)
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("an ECDSA (%s) public key", k.Curve.Params().Name)
+	default:
+		return fmt.Sprintf("an unsupported key (%T)", key)
+	}
+}
+
+// IsUsableKey reports whether a parsed key is one TrustDraw can use: an RSA or Ed25519 key, which can belong to
+// either a player or the dealer.
+func IsUsableKey(key any) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPrivateKey reports whether a parsed key is a private key.
+func IsPrivateKey(key any) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey, *ecdsa.PrivateKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// PublicKeyOf returns the public key of a parsed private key, or the key itself if it is already public.
+func PublicKeyOf(key any) any {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	default:
+		return key
+	}
+}
+
+// The formats EncodeKey can write.
+const (
+	// FormatPEM is PKCS #8 for private keys, and PKIX for public keys.
+	FormatPEM     = "pem"
+	FormatJWK     = "jwk"
+	FormatOpenSSH = "openssh"
+)
+
+// EncodeKey encodes a parsed key in the given format. Private keys are encrypted with the passphrase, if one is given.
+func EncodeKey(key any, format string, passphrase []byte) ([]byte, error) {
+	if len(passphrase) > 0 && !IsPrivateKey(key) {
+		return nil, fmt.Errorf("only private keys can be encrypted")
+	}
+
+	switch format {
+	case FormatPEM:
+		if !IsPrivateKey(key) {
+			der, err := x509.MarshalPKIXPublicKey(key)
+			if err != nil {
+				return nil, err
+			}
+			return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(passphrase) == 0 {
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+		}
+		if der, err = EncryptPKCS8(der, passphrase); err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}), nil
+	case FormatJWK:
+		if len(passphrase) > 0 {
+			return nil, fmt.Errorf("JWKs can't be encrypted")
+		}
+		data, err := MarshalJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatOpenSSH:
+		if !IsPrivateKey(key) {
+			sshPub, err := ssh.NewPublicKey(key)
+			if err != nil {
+				return nil, err
+			}
+			return ssh.MarshalAuthorizedKey(sshPub), nil
+		}
+
+		var pemBlock *pem.Block
+		var err error
+		if len(passphrase) == 0 {
+			pemBlock, err = ssh.MarshalPrivateKey(key, "")
+		} else {
+			pemBlock, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", passphrase)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(pemBlock), nil
+	default:
+		return nil, fmt.Errorf("unknown key format: %s (use %s, %s or %s)", format, FormatPEM, FormatJWK, FormatOpenSSH)
+	}
+}
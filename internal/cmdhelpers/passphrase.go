@@ -0,0 +1,45 @@
+package cmdhelpers
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ReadPassphrase asks for a passphrase on the terminal (even if stdin is redirected), without echoing it.
+func ReadPassphrase(prompt string) ([]byte, error) {
+	tty := os.Stdin
+	if !term.IsTerminal(int(tty.Fd())) {
+		var err error
+		if tty, err = os.Open("/dev/tty"); err != nil {
+			return nil, fmt.Errorf("a passphrase is needed, but there is no terminal to ask for it on")
+		}
+		defer tty.Close()
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	return term.ReadPassword(int(tty.Fd()))
+}
+
+// ReadNewPassphrase asks for a new passphrase twice, making sure both match and that it isn't empty.
+func ReadNewPassphrase(prompt string) ([]byte, error) {
+	passphrase, err := ReadPassphrase(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("the passphrase can't be empty")
+	}
+
+	again, err := ReadPassphrase("Again, to confirm: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(passphrase, again) {
+		return nil, fmt.Errorf("the passphrases don't match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,119 @@
+// Package playerauth signs and checks HTTP requests made on behalf of a player, using their game key.
+package playerauth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jphastings/trustdraw"
+)
+
+const (
+	scheme = "TrustDraw"
+	// MaxSkew is how far a request's timestamp may be from the server's clock.
+	MaxSkew = 5 * time.Minute
+)
+
+// Sign adds an Authorization header to the request, proving it was made by the holder of the player's private key.
+// The request body is read and replaced so it can still be sent.
+func Sign(req *http.Request, player trustdraw.PlayerNumber, prv crypto.Signer) error {
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	digest := digest(req, now, body)
+	sig, err := trustdraw.SignDigest(prv, digest)
+	if err != nil {
+		return fmt.Errorf("unable to sign request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s player=%d, time=%d, signature=%s",
+		scheme, player, now, base64.RawStdEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// Verify checks the Authorization header of a request, returning the player who made it.
+// keyFor is used to look up the public key of the player the request claims to be from.
+func Verify(req *http.Request, keyFor func(trustdraw.PlayerNumber) (crypto.PublicKey, bool)) (trustdraw.PlayerNumber, error) {
+	header := req.Header.Get("Authorization")
+	params, ok := strings.CutPrefix(header, scheme+" ")
+	if !ok {
+		return 0, fmt.Errorf("missing %s authorization", scheme)
+	}
+
+	var (
+		player    int
+		timestamp int64
+		sig       []byte
+		err       error
+	)
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch key {
+		case "player":
+			player, err = strconv.Atoi(value)
+		case "time":
+			timestamp, err = strconv.ParseInt(value, 10, 64)
+		case "signature":
+			sig, err = base64.RawStdEncoding.DecodeString(value)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("authorization %s is badly formed", key)
+		}
+	}
+
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > MaxSkew || skew < -MaxSkew {
+		return 0, fmt.Errorf("authorization has expired")
+	}
+
+	pub, ok := keyFor(trustdraw.PlayerNumber(player))
+	if !ok {
+		return 0, fmt.Errorf("player %d is not in this game", player)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return 0, err
+	}
+	if err := trustdraw.VerifyDigest(pub, digest(req, timestamp, body), sig); err != nil {
+		return 0, fmt.Errorf("request was not signed by player %d", player)
+	}
+
+	return trustdraw.PlayerNumber(player), nil
+}
+
+// digest is the SHA-256 hash of the parts of a request that are signed.
+func digest(req *http.Request, timestamp int64, body []byte) []byte {
+	bodySum := sha256.Sum256(body)
+	sum := sha256.Sum256([]byte(fmt.Sprintf(
+		"%s\n%s\n%d\n%s", req.Method, req.URL.RequestURI(), timestamp, hex.EncodeToString(bodySum[:]))))
+	return sum[:]
+}
+
+// readBody reads a request's body, replacing it so it can be read again.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read request body: %w", err)
+	}
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
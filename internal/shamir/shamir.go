@@ -0,0 +1,121 @@
+// Package shamir splits secrets into shares with Shamir's secret sharing, so that any threshold number of the shares
+// can put the secret back together, but fewer reveal nothing about it. Each byte of the secret is shared separately,
+// with a polynomial over GF(2^8).
+package shamir
+
+import (
+	crand "crypto/rand"
+	"fmt"
+)
+
+// exp and log are the powers of the generator 3 in GF(2^8), with the AES reduction polynomial, and their inverse.
+var exp, log [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// Multiply by 3: x*2 (reduced) xor x.
+		double := x << 1
+		if x&0x80 != 0 {
+			double ^= 0x1b
+		}
+		x ^= double
+	}
+	exp[255] = exp[0]
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return exp[(int(log[a])+int(log[b]))%255]
+}
+
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return exp[(int(log[a])-int(log[b])+255)%255]
+}
+
+// Split shares the secret amongst the holders at each of the (distinct, non-zero) x coordinates, so that any
+// threshold of the shares can Combine to make the secret. The shares are returned in the same order as xs.
+func Split(secret []byte, xs []byte, threshold int) ([][]byte, error) {
+	if threshold < 1 || threshold > len(xs) {
+		return nil, fmt.Errorf("threshold must be between 1 and %d", len(xs))
+	}
+	if err := checkXs(xs); err != nil {
+		return nil, err
+	}
+
+	shares := make([][]byte, len(xs))
+	for i := range shares {
+		shares[i] = make([]byte, len(secret))
+	}
+
+	coefficients := make([]byte, threshold)
+	for b, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, err := crand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		for i, x := range xs {
+			// Horner's method, from the highest coefficient down.
+			var y byte
+			for c := threshold - 1; c >= 0; c-- {
+				y = mul(y, x) ^ coefficients[c]
+			}
+			shares[i][b] = y
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine puts a secret back together from shares made by Split, given the x coordinate of each. If fewer shares are
+// given than the threshold they were split with, the result is meaningless.
+func Combine(xs []byte, shares [][]byte) ([]byte, error) {
+	if len(xs) == 0 || len(xs) != len(shares) {
+		return nil, fmt.Errorf("an x coordinate is needed for each share")
+	}
+	if err := checkXs(xs); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, len(shares[0]))
+	for i, share := range shares {
+		if len(share) != len(secret) {
+			return nil, fmt.Errorf("shares must all be the same length")
+		}
+
+		// The Lagrange basis polynomial for this share, at x = 0.
+		basis := byte(1)
+		for j, x := range xs {
+			if j != i {
+				basis = mul(basis, div(x, x^xs[i]))
+			}
+		}
+		for b, y := range share {
+			secret[b] ^= mul(y, basis)
+		}
+	}
+
+	return secret, nil
+}
+
+func checkXs(xs []byte) error {
+	seen := make(map[byte]bool)
+	for _, x := range xs {
+		if x == 0 {
+			return fmt.Errorf("x coordinates can't be zero")
+		}
+		if seen[x] {
+			return fmt.Errorf("x coordinates must be distinct")
+		}
+		seen[x] = true
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("a sixteen byte k")
+	tests := []struct {
+		name      string
+		xs        []byte
+		threshold int
+	}{
+		{"2 of 2", []byte{1, 2}, 2},
+		{"2 of 3", []byte{1, 2, 3}, 2},
+		{"3 of 5", []byte{1, 2, 3, 4, 5}, 3},
+		{"1 of 1", []byte{7}, 1},
+		{"high xs", []byte{190, 191, 255}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := Split(secret, tt.xs, tt.threshold)
+			if err != nil {
+				t.Fatalf("could not split: %v", err)
+			}
+			if len(shares) != len(tt.xs) {
+				t.Fatalf("got %d shares, want %d", len(shares), len(tt.xs))
+			}
+
+			// Every set of threshold or more shares gives the secret back.
+			for set := 1; set < 1<<len(tt.xs); set++ {
+				var xs []byte
+				var ys [][]byte
+				for i := range tt.xs {
+					if set&(1<<i) != 0 {
+						xs = append(xs, tt.xs[i])
+						ys = append(ys, shares[i])
+					}
+				}
+				if len(xs) < tt.threshold {
+					continue
+				}
+				got, err := Combine(xs, ys)
+				if err != nil || !bytes.Equal(got, secret) {
+					t.Errorf("shares at %v gave %q (%v), want %q", xs, got, err, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestCombineTampered(t *testing.T) {
+	secret := []byte("a sixteen byte k")
+	shares, err := Split(secret, []byte{1, 2, 3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		xs     []byte
+		shares [][]byte
+	}{
+		{"changed share", []byte{1, 2}, [][]byte{shares[0], append([]byte{shares[1][0] ^ 1}, shares[1][1:]...)}},
+		{"wrong x", []byte{1, 3}, [][]byte{shares[0], shares[1]}},
+		{"too few shares", []byte{1}, [][]byte{shares[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Combine(tt.xs, tt.shares); err == nil && bytes.Equal(got, secret) {
+				t.Errorf("the secret was recovered")
+			}
+		})
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() error
+	}{
+		{"threshold of 0", func() error { _, err := Split([]byte("s"), []byte{1, 2}, 0); return err }},
+		{"threshold above the shares", func() error { _, err := Split([]byte("s"), []byte{1, 2}, 3); return err }},
+		{"zero x", func() error { _, err := Split([]byte("s"), []byte{0, 1}, 2); return err }},
+		{"repeated x", func() error { _, err := Split([]byte("s"), []byte{1, 1}, 2); return err }},
+		{"no shares", func() error { _, err := Combine(nil, nil); return err }},
+		{"missing x", func() error { _, err := Combine([]byte{1}, [][]byte{{1}, {2}}); return err }},
+		{"repeated share", func() error { _, err := Combine([]byte{1, 1}, [][]byte{{1}, {2}}); return err }},
+		{"uneven shares", func() error { _, err := Combine([]byte{1, 2}, [][]byte{{1}, {2, 3}}); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err() == nil {
+				t.Errorf("no error was returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Package tui is a full-screen terminal interface for playing a TrustDraw game.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jphastings/trustdraw"
+)
+
+// inputMode is what the text input at the bottom of the screen is currently being used for.
+type inputMode int
+
+const (
+	inputNone inputMode = iota
+	inputAllow
+	inputDraw
+)
+
+// pendingKey is an allowKey that has been made but not yet handed out.
+type pendingKey struct {
+	player   trustdraw.PlayerNumber
+	allowKey string
+	copied   bool
+}
+
+// Model is the bubbletea model for a game being played in the terminal.
+type Model struct {
+	game      *trustdraw.Game
+	clipboard io.Writer
+
+	hand     []trustdraw.HeldCard
+	pending  []pendingKey
+	selected int
+
+	mode   inputMode
+	input  string
+	status string
+	width  int
+}
+
+// New creates a Model for an open game; open it with a StateStore so its state is saved after every action.
+// Copied allowKeys are sent to the terminal's clipboard (with OSC 52) by writing to clipboard,
+// which works over SSH as long as the terminal supports it.
+func New(game *trustdraw.Game, clipboard io.Writer) *Model {
+	m := &Model{game: game, clipboard: clipboard}
+	m.refreshHand()
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.mode != inputNone {
+			m.updateInput(msg)
+			return m, nil
+		}
+		return m, m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+// updateKeys handles single keystroke commands.
+func (m *Model) updateKeys(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "esc":
+		return tea.Quit
+	case "a":
+		m.mode, m.input, m.status = inputAllow, "", ""
+	case "d":
+		m.mode, m.input, m.status = inputDraw, "", ""
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.selected < len(m.pending)-1 {
+			m.selected++
+		}
+	case "c", "y", "enter":
+		m.copySelected()
+	case "x", "backspace", "delete":
+		m.dropSelected()
+	}
+	return nil
+}
+
+// updateInput handles typing into the text input.
+func (m *Model) updateInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode, m.input = inputNone, ""
+	case tea.KeyEnter:
+		mode, input := m.mode, strings.TrimSpace(m.input)
+		m.mode, m.input = inputNone, ""
+		if mode == inputAllow {
+			m.allow(input)
+		} else {
+			m.draw(input)
+		}
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			runes := []rune(m.input)
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.input += " "
+	case tea.KeyRunes:
+		m.input += string(msg.Runes)
+	}
+}
+
+func (m *Model) allow(input string) {
+	player, err := m.game.FindPlayer(strings.TrimSpace(input))
+	if err != nil {
+		m.status = fmt.Sprintf("❌ %v", err)
+		return
+	}
+
+	allowKey, err := m.game.AllowDraw(player)
+	if err != nil {
+		m.status = fmt.Sprintf("❌ %v", err)
+		return
+	}
+
+	m.pending = append(m.pending, pendingKey{player: player, allowKey: allowKey})
+	m.selected = len(m.pending) - 1
+	m.status = fmt.Sprintf("Made an allowKey for %s, press c to copy it", m.game.PlayerName(player))
+}
+
+func (m *Model) draw(input string) {
+	card, _, alreadyDrawn, err := m.game.Draw(strings.Fields(input)...)
+	if err != nil {
+		m.status = fmt.Sprintf("❌ %v", err)
+		return
+	}
+	m.refreshHand()
+
+	if alreadyDrawn {
+		m.status = fmt.Sprintf("You previously drew %s", card)
+	} else {
+		m.status = fmt.Sprintf("You drew %s", card)
+	}
+}
+
+func (m *Model) copySelected() {
+	if len(m.pending) == 0 {
+		return
+	}
+
+	key := &m.pending[m.selected]
+	if _, err := osc52.New(key.allowKey).WriteTo(m.clipboard); err != nil {
+		m.status = fmt.Sprintf("❌ Could not copy: %v", err)
+		return
+	}
+	key.copied = true
+	m.status = fmt.Sprintf("Copied the allowKey for %s, press x once it's been handed out", m.game.PlayerName(key.player))
+}
+
+func (m *Model) dropSelected() {
+	if len(m.pending) == 0 {
+		return
+	}
+
+	m.pending = append(m.pending[:m.selected], m.pending[m.selected+1:]...)
+	if m.selected >= len(m.pending) && m.selected > 0 {
+		m.selected--
+	}
+}
+
+func (m *Model) refreshHand() {
+	hand, err := m.game.Hand()
+	if err != nil {
+		m.status = fmt.Sprintf("❌ Could not read your hand: %v", err)
+		return
+	}
+	m.hand = hand
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (m *Model) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "TrustDraw · game %s · you are %s, player %d of %d\n\n", m.game.ID(), m.game.PlayerName(m.game.PlayerNumber()), m.game.PlayerNumber(), m.game.Players)
+
+	b.WriteString("Your hand\n")
+	if len(m.hand) == 0 {
+		b.WriteString("  (empty)\n")
+	} else {
+		b.WriteString(m.wrapCards())
+	}
+
+	holdings := m.game.Holdings()
+	fmt.Fprintf(&b, "\nDeck: %d cards left to draw\n", holdings[0])
+	for _, entry := range m.game.Roster() {
+		if entry.Player == m.game.PlayerNumber() {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s holds %d\n", m.game.PlayerName(entry.Player), holdings[entry.Player])
+	}
+
+	b.WriteString("\nallowKeys to hand out\n")
+	if len(m.pending) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for i, key := range m.pending {
+		cursor := "  "
+		if i == m.selected {
+			cursor = "> "
+		}
+		copied := ""
+		if key.copied {
+			copied = " (copied)"
+		}
+		fmt.Fprintf(&b, "%sfor %s: %s%s\n", cursor, m.game.PlayerName(key.player), key.allowKey, copied)
+	}
+
+	b.WriteString("\n")
+	switch m.mode {
+	case inputAllow:
+		fmt.Fprintf(&b, "Allow a draw for player (name or number): %s█\n", m.input)
+	case inputDraw:
+		fmt.Fprintf(&b, "Draw with allowKeys: %s█\n", m.input)
+	default:
+		if m.status != "" {
+			b.WriteString(m.status + "\n")
+		} else {
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\na allow · d draw · ↑/↓ select · c copy · x handed out · q quit\n")
+	return b.String()
+}
+
+// wrapCards lays the cards in the hand out as glyphs, wrapping to the width of the terminal.
+func (m *Model) wrapCards() string {
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+
+	var b strings.Builder
+	line := " "
+	for _, held := range m.hand {
+		glyph := fmt.Sprintf(" [%s]", held.Card)
+		if len(line) > 1 && len([]rune(line+glyph)) > width {
+			b.WriteString(line + "\n")
+			line = " "
+		}
+		line += glyph
+	}
+	b.WriteString(line + "\n")
+
+	return b.String()
+}
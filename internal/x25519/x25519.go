@@ -0,0 +1,58 @@
+// Package x25519 converts Ed25519 keys into the X25519 keys for the same curve, so that Ed25519 keys (like the
+// id_ed25519 keys made by ssh-keygen) can be used to agree on encryption keys as well as to sign.
+package x25519
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+)
+
+// p is the prime order of the field Curve25519 is defined over, 2^255 - 19.
+var p = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// PrivateKey returns the X25519 private key for an Ed25519 private key: the clamped scalar an Ed25519 key
+// signs with is also a valid X25519 scalar.
+func PrivateKey(prv ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	if len(prv) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key")
+	}
+	hash := sha512.Sum512(prv.Seed())
+	return ecdh.X25519().NewPrivateKey(hash[:32])
+}
+
+// PublicKey returns the X25519 public key for an Ed25519 public key, by mapping its Edwards y coordinate to the
+// Montgomery u coordinate: u = (1 + y) / (1 - y).
+func PublicKey(pub ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key")
+	}
+
+	// y is little-endian, with the sign of x in the top bit
+	yBytes := make([]byte, len(pub))
+	for i, b := range pub {
+		yBytes[len(pub)-1-i] = b
+	}
+	yBytes[0] &= 0x7f
+	y := new(big.Int).SetBytes(yBytes)
+	if y.Cmp(p) >= 0 {
+		return nil, fmt.Errorf("invalid Ed25519 public key")
+	}
+
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, p)
+	if denominator.Sign() == 0 {
+		return nil, fmt.Errorf("invalid Ed25519 public key")
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, denominator.ModInverse(denominator, p))
+	u.Mod(u, p)
+
+	uBytes := u.FillBytes(make([]byte, 32))
+	for i, j := 0, len(uBytes)-1; i < j; i, j = i+1, j-1 {
+		uBytes[i], uBytes[j] = uBytes[j], uBytes[i]
+	}
+	return ecdh.X25519().NewPublicKey(uBytes)
+}
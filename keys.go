@@ -0,0 +1,79 @@
+package trustdraw
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+)
+
+// Players' and the dealer's keys can be RSA or Ed25519 keys, so the keys people already use for SSH can be used for
+// TrustDraw too. Public keys are *rsa.PublicKey or ed25519.PublicKey, and private keys are any crypto.Signer for
+// one of those (like *rsa.PrivateKey, ed25519.PrivateKey, or a key held by an agent).
+
+// checkKey checks that a public key is one TrustDraw can use, naming whose key it is in any error.
+func checkKey(pub crypto.PublicKey, whose string) error {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.Size() < rsaBits/8 {
+			return fmt.Errorf("%s key is too small (%d bits), must be at least %d bits", whose, k.Size()*8, rsaBits)
+		}
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return fmt.Errorf("%s key is not a valid Ed25519 key", whose)
+		}
+	default:
+		return fmt.Errorf("%s key must be an RSA or Ed25519 key", whose)
+	}
+	return nil
+}
+
+// SignDigest signs a SHA-256 digest with an RSA key (using RSA-PSS) or an Ed25519 key (signing the digest itself).
+func SignDigest(prv crypto.Signer, digest []byte) ([]byte, error) {
+	switch prv.Public().(type) {
+	case *rsa.PublicKey:
+		return prv.Sign(crand.Reader, digest, &rsa.PSSOptions{Hash: crypto.SHA256})
+	case ed25519.PublicKey:
+		return prv.Sign(crand.Reader, digest, crypto.Hash(0))
+	default:
+		return nil, fmt.Errorf("only RSA and Ed25519 keys can sign")
+	}
+}
+
+// VerifyDigest checks a signature made by SignDigest.
+func VerifyDigest(pub crypto.PublicKey, digest, sig []byte) error {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPSS(k, crypto.SHA256, digest, sig, nil)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, digest, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("only RSA and Ed25519 signatures can be checked")
+	}
+}
+
+// signDeal signs a deal file. Ed25519 dealers sign the whole file, others sign its SHA-256 digest.
+func signDeal(prv crypto.Signer, data []byte) ([]byte, error) {
+	if _, ok := prv.Public().(ed25519.PublicKey); ok {
+		return prv.Sign(crand.Reader, data, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(data)
+	return SignDigest(prv, digest[:])
+}
+
+// verifyDeal checks a signature made by signDeal.
+func verifyDeal(pub crypto.PublicKey, data, sig []byte) error {
+	if edPub, ok := pub.(ed25519.PublicKey); ok {
+		if !ed25519.Verify(edPub, data, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+	digest := sha256.Sum256(data)
+	return VerifyDigest(pub, digest[:], sig)
+}
@@ -0,0 +1,331 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const turnMessageVersion = "v1.0"
+
+// TurnMessage bundles everything a player needs to send to the others at the end of their turn,
+// so that a whole turn can be carried by any transport (email, chat, a file…).
+type TurnMessage struct {
+	// GameID is the ID of the deal this turn belongs to, see Game.ID.
+	GameID string
+	// From is the player who made this turn.
+	From PlayerNumber
+	// AllowKeys holds the allowKeys being handed to each player. Each player's allowKeys are
+	// encrypted so that only they can read them.
+	AllowKeys map[PlayerNumber][]string
+	// Reveals are cards shown to everyone, along with the sender's allowKey proving them.
+	Reveals []CardProof
+	// Plays are cards played this turn, along with the sender's allowKey proving them.
+	Plays []CardProof
+	// Moves are free-form game moves, eg. Scrabble notation like "JOKED 8D 50". One per line.
+	Moves []string
+	// Rolls are the sender's commitments to, and reveals for, dice rolls (see Game.Roll).
+	Rolls []string
+	// Steals are the sender's offers of their hand, picks from another player's, and reveals of the hands they offered
+	// (see Game.OfferHand).
+	Steals []string
+	// Reorders are the sender's new orders for cards they peeked at (see Game.Reorder).
+	Reorders []string
+	// Burns are the sender's burns of cards from the deck (see Game.Burn).
+	Burns []string
+	// Discards are the sender's face-down discards from their hand (see Game.DiscardHidden).
+	Discards []string
+
+	// sealed holds the still-encrypted allowKeys of a message that has been read.
+	sealed map[PlayerNumber][]byte
+}
+
+// CardProof is a card along with an allowKey that proves it was drawn.
+type CardProof struct {
+	Card     string
+	AllowKey string
+}
+
+// Pack writes the turn message to w, encrypting each player's allowKeys with their public key
+// and signing the whole message with the sender's private key.
+func (m *TurnMessage) Pack(w io.Writer, senderPrv crypto.Signer, recipientPubs map[PlayerNumber]crypto.PublicKey) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "TrustDraw-Turn/%s\ngame %s\nfrom %d\n\n", turnMessageVersion, m.GameID, m.From)
+
+	for _, player := range sortedPlayers(m.AllowKeys) {
+		pub, ok := recipientPubs[player]
+		if !ok {
+			return fmt.Errorf("no public key given for player %d", player)
+		}
+
+		var plain bytes.Buffer
+		for _, allowKey := range m.AllowKeys[player] {
+			if _, _, err := fromAllowKey(allowKey); err != nil {
+				return fmt.Errorf("allowKey for player %d is invalid: %w", player, err)
+			}
+			fmt.Fprintln(&plain, allowKey)
+		}
+
+		sealed, err := seal(plain.Bytes(), pub)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt allowKeys for player %d: %w", player, err)
+		}
+		fmt.Fprintf(&body, "allow %d %s\n", player, base64.RawStdEncoding.EncodeToString(sealed))
+	}
+	for _, reveal := range m.Reveals {
+		fmt.Fprintf(&body, "reveal %s %s\n", reveal.AllowKey, reveal.Card)
+	}
+	for _, play := range m.Plays {
+		fmt.Fprintf(&body, "play %s %s\n", play.AllowKey, play.Card)
+	}
+	for _, move := range m.Moves {
+		fmt.Fprintf(&body, "move %s\n", move)
+	}
+	for _, roll := range m.Rolls {
+		fmt.Fprintf(&body, "roll %s\n", roll)
+	}
+	for _, steal := range m.Steals {
+		fmt.Fprintf(&body, "steal %s\n", steal)
+	}
+	for _, reorder := range m.Reorders {
+		fmt.Fprintf(&body, "reorder %s\n", reorder)
+	}
+	for _, burn := range m.Burns {
+		fmt.Fprintf(&body, "burn %s\n", burn)
+	}
+	for _, discard := range m.Discards {
+		fmt.Fprintf(&body, "discard %s\n", discard)
+	}
+
+	sig, err := signTurn(senderPrv, body.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to sign the turn message: %w", err)
+	}
+
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return fmt.Errorf("unable to write the turn message: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "\n%s\n", base64.RawStdEncoding.EncodeToString(sig)); err != nil {
+		return fmt.Errorf("unable to write the turn message signature: %w", err)
+	}
+
+	return nil
+}
+
+// ReadTurnMessage parses a packed turn message, checking that it was signed by the holder of senderPub.
+// AllowKeys are left encrypted; use OpenAllowKeys to read the ones addressed to you.
+func ReadTurnMessage(r io.Reader, senderPub crypto.PublicKey) (*TurnMessage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimRight(string(data), "\n")
+	split := strings.LastIndex(text, "\n\n")
+	if split == -1 {
+		return nil, fmt.Errorf("turn message not valid")
+	}
+	body, sigText := text[:split+1], text[split+2:]
+
+	sig, err := base64.RawStdEncoding.DecodeString(sigText)
+	if err != nil {
+		return nil, fmt.Errorf("turn message signature is badly formed")
+	}
+	digest := sha256.Sum256([]byte(body))
+	if err := VerifyDigest(senderPub, digest[:], sig); err != nil {
+		return nil, fmt.Errorf("turn message was not signed by the given player")
+	}
+
+	return parseTurnMessage(body)
+}
+
+// OpenAllowKeys decrypts the allowKeys in this message that are addressed to the holder of the given private key,
+// returning the player number they were addressed to.
+func (m *TurnMessage) OpenAllowKeys(playerPrv crypto.Signer) (PlayerNumber, []string, error) {
+	for _, player := range sortedPlayers(m.sealed) {
+		plain, err := unseal(m.sealed[player], playerPrv)
+		if err != nil {
+			// Not addressed to us, try the next one
+			continue
+		}
+
+		allowKeys := strings.Fields(string(plain))
+		if m.AllowKeys == nil {
+			m.AllowKeys = make(map[PlayerNumber][]string)
+		}
+		m.AllowKeys[player] = allowKeys
+		return player, allowKeys, nil
+	}
+
+	return 0, nil, nil
+}
+
+// Recipients lists the players this message carries allowKeys for.
+func (m *TurnMessage) Recipients() []PlayerNumber {
+	if m.sealed != nil {
+		return sortedPlayers(m.sealed)
+	}
+	return sortedPlayers(m.AllowKeys)
+}
+
+func (m *TurnMessage) validate() error {
+	if m.GameID == "" || strings.ContainsAny(m.GameID, " \n") {
+		return fmt.Errorf("turn message needs a valid game ID")
+	}
+	if m.From < 1 || m.From > MaxPlayers {
+		return fmt.Errorf("player %d cannot send a turn message", m.From)
+	}
+
+	for _, proofs := range [][]CardProof{m.Reveals, m.Plays} {
+		for _, proof := range proofs {
+			if strings.Contains(proof.Card, "\n") {
+				return fmt.Errorf("card '%s' cannot contain a new line", proof.Card)
+			}
+			if _, _, err := fromAllowKey(proof.AllowKey); err != nil {
+				return fmt.Errorf("allowKey for card '%s' is invalid: %w", proof.Card, err)
+			}
+		}
+	}
+	for _, move := range m.Moves {
+		if move == "" || strings.Contains(move, "\n") {
+			return fmt.Errorf("moves must be a single, non-empty line")
+		}
+	}
+	for _, roll := range m.Rolls {
+		if _, _, _, _, _, err := parseRollCommit(roll); err == nil {
+			continue
+		}
+		if _, _, _, err := parseRollReveal(roll); err != nil {
+			return fmt.Errorf("'%s' is not a roll commitment or reveal", roll)
+		}
+	}
+	for _, steal := range m.Steals {
+		_, _, _, _, _, offerErr := parseStealOffer(steal)
+		_, _, pickErr := parseStealPick(steal)
+		_, _, _, _, revealErr := parseStealReveal(steal)
+		if offerErr != nil && pickErr != nil && revealErr != nil {
+			return fmt.Errorf("'%s' is not a steal offer, pick or reveal", steal)
+		}
+	}
+	for _, reorder := range m.Reorders {
+		if _, _, err := parseReorder(reorder); err != nil {
+			return fmt.Errorf("'%s' is not a reorder", reorder)
+		}
+	}
+	for _, burn := range m.Burns {
+		if _, gone, _, err := parseGone(burn); err != nil || gone != burnt {
+			return fmt.Errorf("'%s' is not a burn", burn)
+		}
+	}
+	for _, discard := range m.Discards {
+		if _, gone, _, err := parseGone(discard); err != nil || gone != discarded {
+			return fmt.Errorf("'%s' is not a discard", discard)
+		}
+	}
+
+	return nil
+}
+
+func parseTurnMessage(body string) (*TurnMessage, error) {
+	parts := strings.SplitN(body, "\n\n", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("turn message not valid")
+	}
+
+	m := &TurnMessage{sealed: make(map[PlayerNumber][]byte)}
+	for i, line := range strings.Split(parts[0], "\n") {
+		if i == 0 {
+			if line != "TrustDraw-Turn/"+turnMessageVersion {
+				return nil, fmt.Errorf("unknown turn message version: %s", line)
+			}
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "game":
+			m.GameID = value
+		case "from":
+			from, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("turn message sender is invalid")
+			}
+			m.From = PlayerNumber(from)
+		default:
+			return nil, fmt.Errorf("unknown turn message header: %s", key)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(parts[1], "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		kind, rest, _ := strings.Cut(line, " ")
+		switch kind {
+		case "allow":
+			playerText, sealedText, _ := strings.Cut(rest, " ")
+			player, err := strconv.Atoi(playerText)
+			if err != nil {
+				return nil, fmt.Errorf("allowKeys recipient is invalid")
+			}
+			sealed, err := base64.RawStdEncoding.DecodeString(sealedText)
+			if err != nil {
+				return nil, fmt.Errorf("allowKeys for player %d are badly formed", player)
+			}
+			m.sealed[PlayerNumber(player)] = sealed
+		case "reveal", "play":
+			allowKey, card, _ := strings.Cut(rest, " ")
+			proof := CardProof{Card: card, AllowKey: allowKey}
+			if kind == "reveal" {
+				m.Reveals = append(m.Reveals, proof)
+			} else {
+				m.Plays = append(m.Plays, proof)
+			}
+		case "move":
+			m.Moves = append(m.Moves, rest)
+		case "roll":
+			m.Rolls = append(m.Rolls, rest)
+		case "steal":
+			m.Steals = append(m.Steals, rest)
+		case "reorder":
+			m.Reorders = append(m.Reorders, rest)
+		case "burn":
+			m.Burns = append(m.Burns, rest)
+		case "discard":
+			m.Discards = append(m.Discards, rest)
+		default:
+			return nil, fmt.Errorf("unknown turn message entry: %s", kind)
+		}
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// signTurn signs the SHA-256 digest of a turn message body, with RSA-PSS for RSA keys.
+func signTurn(prv crypto.Signer, body []byte) ([]byte, error) {
+	digest := sha256.Sum256(body)
+	return SignDigest(prv, digest[:])
+}
+
+func sortedPlayers[T any](m map[PlayerNumber]T) []PlayerNumber {
+	players := make([]PlayerNumber, 0, len(m))
+	for player := range m {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i] < players[j] })
+	return players
+}
@@ -0,0 +1,121 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTurnMessageRoundTrip(t *testing.T) {
+	games, prvs := testGames(t, 3, DealOptions{})
+	card, allowKey, _ := testDraw(t, games, 1)
+	forBob, err := games[0].AllowDraw(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forCarol, err := games[0].AllowDraw(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := TurnMessage{
+		GameID:    games[0].ID(),
+		From:      1,
+		AllowKeys: map[PlayerNumber][]string{2: {forBob}, 3: {forCarol}},
+		Plays:     []CardProof{{Card: card, AllowKey: allowKey}},
+		Moves:     []string{"JOKED 8D 50"},
+	}
+	pubs := map[PlayerNumber]crypto.PublicKey{2: prvs[1].Public(), 3: prvs[2].Public()}
+	var packed bytes.Buffer
+	if err := sent.Pack(&packed, prvs[0], pubs); err != nil {
+		t.Fatalf("could not pack: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		reader    crypto.Signer
+		player    PlayerNumber
+		allowKeys []string
+	}{
+		{"bob", prvs[1], 2, []string{forBob}},
+		{"carol", prvs[2], 3, []string{forCarol}},
+		{"sender", prvs[0], 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ReadTurnMessage(bytes.NewReader(packed.Bytes()), prvs[0].Public())
+			if err != nil {
+				t.Fatalf("could not read: %v", err)
+			}
+			if msg.GameID != sent.GameID || msg.From != sent.From {
+				t.Errorf("got game %s from %d, want %s from %d", msg.GameID, msg.From, sent.GameID, sent.From)
+			}
+			if !reflect.DeepEqual(msg.Plays, sent.Plays) || !reflect.DeepEqual(msg.Moves, sent.Moves) {
+				t.Errorf("got plays %v and moves %v, want %v and %v", msg.Plays, msg.Moves, sent.Plays, sent.Moves)
+			}
+			if got := msg.Recipients(); !reflect.DeepEqual(got, []PlayerNumber{2, 3}) {
+				t.Errorf("got recipients %v, want [2 3]", got)
+			}
+
+			player, allowKeys, err := msg.OpenAllowKeys(tt.reader)
+			if err != nil {
+				t.Fatalf("could not open allowKeys: %v", err)
+			}
+			if player != tt.player || !reflect.DeepEqual(allowKeys, tt.allowKeys) {
+				t.Errorf("got allowKeys %v for player %d, want %v for player %d", allowKeys, player, tt.allowKeys, tt.player)
+			}
+		})
+	}
+}
+
+func TestTurnMessageTampering(t *testing.T) {
+	games, prvs := testGames(t, 2, DealOptions{})
+	sent := TurnMessage{GameID: games[0].ID(), From: 1, Moves: []string{"JOKED 8D 50"}}
+	var packed bytes.Buffer
+	if err := sent.Pack(&packed, prvs[0], nil); err != nil {
+		t.Fatalf("could not pack: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		packed string
+		sender crypto.PublicKey
+	}{
+		{"changed move", strings.Replace(packed.String(), "JOKED", "JOKES", 1), prvs[0].Public()},
+		{"changed sender", strings.Replace(packed.String(), "from 1", "from 2", 1), prvs[0].Public()},
+		{"added entry", strings.Replace(packed.String(), "move ", "move SNEAKY 1A 99\nmove ", 1), prvs[0].Public()},
+		{"wrong signer", packed.String(), prvs[1].Public()},
+		{"no signature", strings.TrimSpace(packed.String())[:strings.LastIndex(strings.TrimSpace(packed.String()), "\n")], prvs[0].Public()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadTurnMessage(strings.NewReader(tt.packed), tt.sender); err == nil {
+				t.Errorf("a tampered turn message was read")
+			}
+		})
+	}
+}
+
+func TestTurnMessageValidation(t *testing.T) {
+	games, prvs := testGames(t, 2, DealOptions{})
+	tests := []struct {
+		name string
+		msg  TurnMessage
+	}{
+		{"no game ID", TurnMessage{From: 1}},
+		{"no sender", TurnMessage{GameID: games[0].ID()}},
+		{"bad allowKey", TurnMessage{GameID: games[0].ID(), From: 1, Reveals: []CardProof{{Card: "A♠️", AllowKey: "nope"}}}},
+		{"multi-line move", TurnMessage{GameID: games[0].ID(), From: 1, Moves: []string{"one\ntwo"}}},
+		{"bad roll", TurnMessage{GameID: games[0].ID(), From: 1, Rolls: []string{"nope"}}},
+		{"discard as a burn", TurnMessage{GameID: games[0].ID(), From: 1, Burns: []string{"Af0BAA"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.msg.Pack(&bytes.Buffer{}, prvs[0], nil); err == nil {
+				t.Errorf("an invalid turn message was packed")
+			}
+		})
+	}
+}
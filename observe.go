@@ -0,0 +1,135 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"fmt"
+)
+
+// Release ends the game for this player, sealing their key stack so that only the given god observer (see RoleGod)
+// can read it. Once every player has released their key stack to them, the god observer can see every card.
+// This player can't allow draws or draw cards afterwards.
+func (g *Game) Release(godPub crypto.PublicKey) (string, error) {
+	if g.role != RolePlayer {
+		return "", ErrObserving
+	}
+
+	fingerprint, err := Fingerprint(godPub)
+	if err != nil {
+		return "", err
+	}
+	god := g.findObserver(fingerprint)
+	if god == 0 {
+		return "", fmt.Errorf("the deal file doesn't list that key as a god observer")
+	}
+
+	plain := append([]byte{byte(g.playerNumber)}, bytes.Join(g.keys, nil)...)
+	sealed, err := seal(plain, godPub)
+	if err != nil {
+		return "", fmt.Errorf("unable to encrypt the key stack for %s: %w", g.PlayerName(god), err)
+	}
+
+	wasReleased := g.released
+	g.released = true
+	if err := g.Save(); err != nil {
+		g.released = wasReleased
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// findObserver finds the god observer with the given key fingerprint, returning 0 if there isn't one.
+func (g *Game) findObserver(fingerprint string) PlayerNumber {
+	for _, entry := range g.roster {
+		if entry.Role == RoleGod && entry.Fingerprint == fingerprint {
+			return entry.Player
+		}
+	}
+	return 0
+}
+
+// AddRelease reads a key stack a player released to this god observer, with their private key, returning the number
+// of the player that released it.
+func (g *Game) AddRelease(release string, godPrv crypto.Signer) (PlayerNumber, error) {
+	if g.role != RoleGod {
+		return 0, fmt.Errorf("only god observers can be given released key stacks")
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(release)
+	if err != nil {
+		return 0, fmt.Errorf("released key stack is badly formed")
+	}
+	plain, err := unseal(sealed, godPrv)
+	if err != nil || len(plain) == 0 {
+		return 0, fmt.Errorf("released key stack wasn't released to you")
+	}
+
+	player, stack, err := checkRelease(int(plain[0]), plain[1:], g.Players, len(g.cards))
+	if err != nil {
+		return 0, err
+	}
+
+	previous, wasReleased := g.releases[player]
+	g.releases[player] = stack
+	if err := g.Save(); err != nil {
+		if wasReleased {
+			g.releases[player] = previous
+		} else {
+			delete(g.releases, player)
+		}
+		return 0, fmt.Errorf("could not save game state: %w", err)
+	}
+	return player, nil
+}
+
+// checkRelease checks a released key stack is from a player in the game and holds a key share for every card,
+// splitting it into those shares.
+func checkRelease(player int, stack []byte, players, cardCount int) (PlayerNumber, [][]byte, error) {
+	if player < 1 || player > players {
+		return 0, nil, fmt.Errorf("released key stack is from a player who isn't in this game")
+	}
+	if len(stack) != cardCount*aesCipherSize {
+		return 0, nil, fmt.Errorf("released key stack is badly formed")
+	}
+
+	keys := make([][]byte, cardCount)
+	for i := range keys {
+		keys[i] = stack[i*aesCipherSize : (i+1)*aesCipherSize]
+	}
+	return PlayerNumber(player), keys, nil
+}
+
+// Releases lists the players who have released their key stacks to this god observer.
+func (g *Game) Releases() []PlayerNumber {
+	return sortedPlayers(g.releases)
+}
+
+// Cards lists every card in the deck, in deck order, once every player has released their key stack to this god
+// observer. The released key stacks are checked against the deal, so no player can misrepresent their cards.
+func (g *Game) Cards() ([]string, error) {
+	if g.role != RoleGod {
+		return nil, fmt.Errorf("only god observers can see every card")
+	}
+	if len(g.releases) != g.Players {
+		return nil, fmt.Errorf("%d of %d players have released their key stacks", len(g.releases), g.Players)
+	}
+
+	cards := make([]string, len(g.cards))
+	for cardID := range cards {
+		shares := make([]string, 0, g.Players)
+		for _, player := range sortedPlayers(g.releases) {
+			shares = append(shares, toAllowKey(cardID, g.releases[player][cardID]))
+		}
+
+		_, cardKey, err := g.allowKeysToCardKey(shares)
+		if err != nil {
+			return nil, fmt.Errorf("the released key stacks don't match card %d of the deal", cardID+1)
+		}
+		if cards[cardID], err = g.decryptCard(cardID, cardKey); err != nil {
+			return nil, err
+		}
+	}
+	return cards, nil
+}
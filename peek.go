@@ -0,0 +1,301 @@
+package trustdraw
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A player can peek at the next cards to be drawn, without drawing them: the other players give them their allowKeys
+// for those cards (see Game.AllowPeek), which they use to see them (see Game.Peek). The cards stay in the deck.
+//
+// The peeking player can then put the cards back in a different order (see Game.Reorder), which every player applies
+// to the order they allow draws in (see Game.ApplyReorder). The reorder only lists card IDs, so it doesn't show what
+// the cards are, but it does show which of them were moved.
+
+// PeekedCard is a card in the deck that this player has peeked at.
+type PeekedCard struct {
+	CardID int
+	Card   string
+}
+
+// Top lists the IDs of the next n cards to be drawn from the given pile ("" for the main deck), in the order AllowDraw
+// gives them.
+func (g *Game) Top(pile string, n int) []int {
+	order := g.drawOrder(pile)
+	if n < len(order) {
+		order = order[:n]
+	}
+	return order
+}
+
+// drawOrder lists the IDs of the cards still in the given pile, in the order AllowDraw gives them: any reordered cards
+// first, then the rest in the order they were dealt.
+func (g *Game) drawOrder(pile string) []int {
+	in, err := g.findPile(pile)
+	if err != nil {
+		return nil
+	}
+	inPile := func(cardID int) bool {
+		return cardID >= in.first && cardID < in.first+in.count && g.state[cardID] == 0
+	}
+
+	var order []int
+	first := make(map[int]bool)
+	for _, cardID := range g.order {
+		if inPile(cardID) {
+			order = append(order, cardID)
+			first[cardID] = true
+		}
+	}
+	for cardID := in.first; cardID < in.first+in.count; cardID++ {
+		if inPile(cardID) && !first[cardID] {
+			order = append(order, cardID)
+		}
+	}
+	return order
+}
+
+// AllowPeek returns this player's allowKeys for the next n cards to be drawn from the given pile ("" for the main
+// deck), so the given player can look at them without drawing them. The peek is recorded, so the player can reorder
+// those cards afterwards.
+func (g *Game) AllowPeek(intended PlayerNumber, pile string, n int) ([]string, error) {
+	if err := g.canPlay(); err != nil {
+		return nil, err
+	}
+	if intended < 1 || intended > PlayerNumber(g.Players) {
+		return nil, fmt.Errorf("player %d is not in this game", intended)
+	}
+	if _, departed := g.recovered[intended]; departed {
+		return nil, fmt.Errorf("%s has left the game", g.PlayerName(intended))
+	}
+	if _, err := g.findPile(pile); err != nil {
+		return nil, err
+	}
+	top := g.Top(pile, n)
+	if n < 1 || len(top) < n {
+		return nil, fmt.Errorf("there are %d cards left to peek at", len(top))
+	}
+
+	if err := g.setPeeked(intended, top); err != nil {
+		return nil, err
+	}
+	allowKeys := make([]string, len(top))
+	for i, cardID := range top {
+		allowKeys[i] = toAllowKey(cardID, g.keys[cardID])
+	}
+	return allowKeys, nil
+}
+
+// Peek uses the allowKeys other players gave with AllowPeek to look at the next cards to be drawn from a pile, in the
+// order they'll be drawn. The cards stay in the deck.
+func (g *Game) Peek(allowKeys ...string) ([]PeekedCard, error) {
+	if err := g.canPlay(); err != nil {
+		return nil, err
+	}
+	byCard := make(map[int][]string)
+	var pile string
+	for _, allowKey := range allowKeys {
+		cardID, _, err := fromAllowKey(allowKey)
+		if err != nil {
+			return nil, err
+		}
+		byCard[cardID] = append(byCard[cardID], allowKey)
+		pile = g.PileOf(cardID)
+	}
+	if len(byCard) == 0 {
+		return nil, fmt.Errorf("no allowKeys given")
+	}
+
+	top := g.Top(pile, len(byCard))
+	var peeked []PeekedCard
+	for _, cardID := range top {
+		keys, ok := byCard[cardID]
+		if !ok {
+			return nil, fmt.Errorf("the allowKeys aren't for the next %d cards to be drawn", len(byCard))
+		}
+		// The allowKeys of players who have left the game can be left out, as their recovered key shares are used.
+		if needed := g.Players - 1 - len(g.recovered); len(keys) < needed || len(keys) > g.Players-1 {
+			return nil, fmt.Errorf("wrong number of allowKeys for card %d (%d needed, %d given)", cardID+1, needed, len(keys))
+		}
+		_, cardKey, err := g.allowKeysToCardKey(g.withRecovered(keys))
+		if err != nil {
+			return nil, fmt.Errorf("could not re-create card key: %w", err)
+		}
+		card, err := g.decryptCard(cardID, cardKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt card: %w", err)
+		}
+		peeked = append(peeked, PeekedCard{CardID: cardID, Card: card})
+	}
+
+	if err := g.setPeeked(g.playerNumber, top); err != nil {
+		return nil, err
+	}
+	return peeked, nil
+}
+
+// Reorder puts the cards this player last peeked at back on the deck in the given order, returning the reorder to
+// share with the other players, who apply it with ApplyReorder.
+func (g *Game) Reorder(order ...int) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	reorder := make([]byte, 1, 1+2*len(order))
+	reorder[0] = byte(g.playerNumber)
+	for _, cardID := range order {
+		reorder = binary.LittleEndian.AppendUint16(reorder, uint16(cardID))
+	}
+	token := base64.RawStdEncoding.EncodeToString(reorder)
+	if err := g.ApplyReorder(token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ApplyReorder checks that a player's reorder is of the cards they were allowed to peek at, which must still be the
+// next to be drawn, and puts them first in the order draws are allowed in.
+func (g *Game) ApplyReorder(reorder string) error {
+	peeker, order, err := parseReorder(reorder)
+	if err != nil {
+		return err
+	}
+	peeked, ok := g.peeked[peeker]
+	if !ok {
+		return fmt.Errorf("%s hasn't peeked at any cards", g.PlayerName(peeker))
+	}
+	if !sameCards(g.Top(g.PileOf(peeked[0]), len(peeked)), peeked) {
+		return fmt.Errorf("the cards %s peeked at aren't the next to be drawn any more", g.PlayerName(peeker))
+	}
+	if !sameCards(order, peeked) {
+		return fmt.Errorf("the reorder isn't of the cards %s peeked at", g.PlayerName(peeker))
+	}
+
+	// The reordered cards go before any cards reordered earlier, which are still drawn before the rest of their pile.
+	oldOrder := g.order
+	g.order = append([]int(nil), order...)
+	for _, cardID := range oldOrder {
+		if g.state[cardID] == 0 && !containsAny(order, cardID) {
+			g.order = append(g.order, cardID)
+		}
+	}
+	delete(g.peeked, peeker)
+	if err := g.Save(); err != nil {
+		g.order = oldOrder
+		g.peeked[peeker] = peeked
+		return fmt.Errorf("could not save game state: %w", err)
+	}
+	return nil
+}
+
+func (g *Game) setPeeked(player PlayerNumber, cards []int) error {
+	peeked, wasPeeked := g.peeked[player]
+	g.peeked[player] = cards
+	if err := g.Save(); err != nil {
+		if wasPeeked {
+			g.peeked[player] = peeked
+		} else {
+			delete(g.peeked, player)
+		}
+		return fmt.Errorf("could not save game state: %w", err)
+	}
+	return nil
+}
+
+// sameCards reports whether two lists hold the same card IDs, in any order, each once.
+func sameCards(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[int]int)
+	for _, cardID := range a {
+		count[cardID]++
+	}
+	for _, cardID := range b {
+		if count[cardID] != 1 {
+			return false
+		}
+		count[cardID]--
+	}
+	return true
+}
+
+func parseReorder(reorder string) (PlayerNumber, []int, error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(reorder)
+	if err != nil || len(decoded) < 3 || len(decoded)%2 != 1 {
+		return 0, nil, fmt.Errorf("invalid reorder")
+	}
+	var order []int
+	for i := 1; i < len(decoded); i += 2 {
+		order = append(order, int(binary.LittleEndian.Uint16(decoded[i:i+2])))
+	}
+	return PlayerNumber(decoded[0]), order, nil
+}
+
+// containsAny reports whether an unsorted list of card IDs holds the given card.
+func containsAny(cards []int, cardID int) bool {
+	for _, c := range cards {
+		if c == cardID {
+			return true
+		}
+	}
+	return false
+}
+
+// peekStateLines encodes the reordered cards, and the cards each player has been allowed to peek at, as state lines.
+func (g *Game) peekStateLines() []string {
+	var lines []string
+	if len(g.order) > 0 {
+		lines = append(lines, "order "+joinCardIDs(g.order))
+	}
+	for _, player := range sortedPlayers(g.peeked) {
+		lines = append(lines, fmt.Sprintf("peeked %d %s", player, joinCardIDs(g.peeked[player])))
+	}
+	return lines
+}
+
+// loadPeekState reads an "order" or "peeked" state line.
+func (g *Game) loadPeekState(fields []string) error {
+	switch {
+	case fields[0] == "order" && len(fields) == 2:
+		order, err := splitCardIDs(fields[1], len(g.cards))
+		if err != nil {
+			return fmt.Errorf("state holds an invalid draw order")
+		}
+		g.order = order
+	case fields[0] == "peeked" && len(fields) == 3:
+		player, err := strconv.Atoi(fields[1])
+		cards, cardsErr := splitCardIDs(fields[2], len(g.cards))
+		if err != nil || cardsErr != nil || player < 1 || player > g.Players {
+			return fmt.Errorf("state holds an invalid peek")
+		}
+		g.peeked[PlayerNumber(player)] = cards
+	default:
+		return fmt.Errorf("state holds an invalid peek")
+	}
+	return nil
+}
+
+// joinCardIDs lists card IDs in order, separated by commas.
+func joinCardIDs(cards []int) string {
+	ids := make([]string, len(cards))
+	for i, cardID := range cards {
+		ids[i] = strconv.Itoa(cardID)
+	}
+	return strings.Join(ids, ",")
+}
+
+// splitCardIDs reads a list of card IDs written with joinCardIDs.
+func splitCardIDs(list string, cardCount int) ([]int, error) {
+	var cards []int
+	for _, id := range strings.Split(list, ",") {
+		cardID, err := strconv.Atoi(id)
+		if err != nil || cardID < 0 || cardID >= cardCount {
+			return nil, fmt.Errorf("invalid card ID: %s", id)
+		}
+		cards = append(cards, cardID)
+	}
+	return cards, nil
+}
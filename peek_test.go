@@ -0,0 +1,90 @@
+package trustdraw
+
+import "testing"
+
+func TestPeekAndReorder(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	var allowKeys []string
+	for _, game := range games[1:] {
+		keys, err := game.AllowPeek(1, "", 3)
+		if err != nil {
+			t.Fatalf("could not allow a peek: %v", err)
+		}
+		allowKeys = append(allowKeys, keys...)
+	}
+	peeked, err := games[0].Peek(allowKeys...)
+	if err != nil || len(peeked) != 3 {
+		t.Fatalf("peeked at %d cards (%v), want 3", len(peeked), err)
+	}
+	if got := games[0].Remaining(); got != len(testDeck) {
+		t.Errorf("%d cards are left after peeking, want all %d", got, len(testDeck))
+	}
+
+	order := []int{peeked[2].CardID, peeked[0].CardID, peeked[1].CardID}
+	reorder, err := games[0].Reorder(order...)
+	if err != nil {
+		t.Fatalf("could not reorder: %v", err)
+	}
+	for _, game := range games[1:] {
+		if err := game.ApplyReorder(reorder); err != nil {
+			t.Fatalf("player %d could not apply the reorder: %v", game.PlayerNumber(), err)
+		}
+	}
+	if err := games[1].ApplyReorder(reorder); err == nil {
+		t.Errorf("the same reorder was applied twice")
+	}
+
+	// Draws follow the new order.
+	for i, cardID := range order {
+		card, _, _ := testDraw(t, games, 2)
+		if want := peeked[indexOfCard(peeked, cardID)].Card; card != want {
+			t.Errorf("draw %d was %s, want %s", i+1, card, want)
+		}
+	}
+}
+
+func TestPeekedCardsArentDrawn(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	var allowKeys []string
+	for _, game := range games[1:] {
+		keys, err := game.AllowPeek(1, "", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		allowKeys = append(allowKeys, keys...)
+	}
+	peeked, err := games[0].Peek(allowKeys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	own := toAllowKey(peeked[0].CardID, games[0].keys[peeked[0].CardID])
+
+	// The peeker holds the same allowKeys as a drawer would, but nobody recorded the card as given to them.
+	if ok, err := games[1].VerifyDraw(peeked[0].Card, own, allowKeys[1]); err != nil || ok {
+		t.Errorf("a peeked card was verified as drawn (%v)", err)
+	}
+	if ok, err := games[1].VerifyDrawBy(1, peeked[0].Card, own, allowKeys[1]); err != nil || ok {
+		t.Errorf("a peeked card was verified as drawn by the peeker (%v)", err)
+	}
+
+	// Once it's really drawn, it verifies.
+	card, allowKey, drawKeys := testDraw(t, games, 1)
+	if card != peeked[0].Card {
+		t.Fatalf("drew %s, but peeked at %s", card, peeked[0].Card)
+	}
+	if ok, err := games[1].VerifyDrawBy(1, card, allowKey, drawKeys[1]); err != nil || !ok {
+		t.Errorf("the drawn card wasn't verified (%v)", err)
+	}
+	if ok, err := games[1].VerifyDrawBy(3, card, allowKey, drawKeys[1]); err != nil || ok {
+		t.Errorf("the drawn card was verified as someone else's (%v)", err)
+	}
+}
+
+func indexOfCard(peeked []PeekedCard, cardID int) int {
+	for i, card := range peeked {
+		if card.CardID == cardID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package trustdraw
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Pile is a named set of cards dealt alongside the main deck, like a bonus deck next to a Scrabble bag. Each pile is
+// shuffled on its own, and its cards come after the main deck's in the deal file, so their card IDs (and allowKeys)
+// say which pile they're from. Draws are allowed from the top of a pile with DrawTop.In.
+type Pile struct {
+	Name  string
+	Cards []string
+}
+
+// pileRange is where a pile's cards are in the deck. The main deck has no name, and always comes first.
+type pileRange struct {
+	name         string
+	first, count int
+}
+
+// validatePiles checks that the piles have unique names that can be written in a deal file header.
+func validatePiles(piles []Pile) error {
+	seen := make(map[string]bool)
+	for _, pile := range piles {
+		switch {
+		case pile.Name == "":
+			return fmt.Errorf("every pile needs a name")
+		case len(pile.Name) > maxNameLength:
+			return fmt.Errorf("pile name %s is too long, must be %d bytes or fewer", pile.Name, maxNameLength)
+		case strings.IndexFunc(pile.Name, unicode.IsSpace) != -1:
+			return fmt.Errorf("pile name %s can't contain whitespace", pile.Name)
+		case seen[pile.Name]:
+			return fmt.Errorf("more than one pile is called %s", pile.Name)
+		case len(pile.Cards) == 0:
+			return fmt.Errorf("pile %s has no cards", pile.Name)
+		}
+		seen[pile.Name] = true
+	}
+	return nil
+}
+
+// parsePile reads a "pile" deal file header entry.
+func parsePile(fields []string) (pileRange, error) {
+	if len(fields) != 3 {
+		return pileRange{}, fmt.Errorf("deal file pile is badly formed")
+	}
+	count, err := strconv.Atoi(fields[2])
+	if err != nil || count < 1 {
+		return pileRange{}, fmt.Errorf("deal file pile is badly formed")
+	}
+	return pileRange{name: fields[1], count: count}, nil
+}
+
+// layoutPiles works out where each of the piles listed in a deal file header is in a deck of the given size, with the
+// main deck first.
+func layoutPiles(piles []pileRange, cardCount int) ([]pileRange, error) {
+	main := cardCount
+	for _, pile := range piles {
+		main -= pile.count
+	}
+	if main < 0 {
+		return nil, fmt.Errorf("deal file piles hold more cards than the deck")
+	}
+
+	laidOut := []pileRange{{count: main}}
+	first := main
+	for _, pile := range piles {
+		laidOut = append(laidOut, pileRange{name: pile.name, first: first, count: pile.count})
+		first += pile.count
+	}
+	return laidOut, nil
+}
+
+// Piles lists the names of the piles dealt alongside the main deck.
+func (g *Game) Piles() []string {
+	var names []string
+	for _, pile := range g.piles[1:] {
+		names = append(names, pile.name)
+	}
+	return names
+}
+
+// PileOf returns the name of the pile the card with the given ID was dealt in, which is "" for the main deck.
+func (g *Game) PileOf(cardID int) string {
+	for _, pile := range g.piles {
+		if cardID >= pile.first && cardID < pile.first+pile.count {
+			return pile.name
+		}
+	}
+	return ""
+}
+
+// RemainingIn counts the cards still in the given pile ("" for the main deck) to be drawn.
+func (g *Game) RemainingIn(pile string) int {
+	return len(g.drawOrder(pile))
+}
+
+// findPile returns where the named pile is in the deck.
+func (g *Game) findPile(name string) (pileRange, error) {
+	for _, pile := range g.piles {
+		if pile.name == name {
+			return pile, nil
+		}
+	}
+	return pileRange{}, fmt.Errorf("there's no pile called %s", name)
+}
@@ -0,0 +1,56 @@
+// Package relay hosts TrustDraw games over HTTP, storing deal files and relaying turn messages between players.
+//
+// The relay only ever sees deal files and turn messages, which are safe to publish: hidden cards and
+// allowKeys stay encrypted for the players they are meant for.
+package relay
+
+import (
+	"crypto"
+	"errors"
+
+	"github.com/jphastings/trustdraw"
+)
+
+var (
+	ErrGameNotFound     = errors.New("game not found")
+	ErrGameExists       = errors.New("game already exists")
+	ErrDuplicateMessage = errors.New("turn message has already been sent")
+)
+
+// Backend stores the games hosted by a relay Server.
+type Backend interface {
+	// CreateGame stores a new game, returning ErrGameExists if one with the same ID is already stored.
+	CreateGame(game Game) error
+	// Game retrieves a stored game, returning ErrGameNotFound if there isn't one.
+	Game(id string) (Game, error)
+	// AppendTurn adds a turn message to a game's transcript, returning its sequence number (starting at 1).
+	// It returns ErrDuplicateMessage if the identical message was already stored.
+	AppendTurn(id string, turn Turn) (int, error)
+	// Turns lists a game's turn messages with sequence numbers greater than after.
+	Turns(id string, after int) ([]Turn, error)
+}
+
+// Game is a game hosted by the relay.
+type Game struct {
+	ID   string
+	Deal []byte
+	// PlayerKeys are the players' public keys, in player number order.
+	PlayerKeys []crypto.PublicKey
+	// TranscriptDelay is the number of the latest turns left out of the public transcript.
+	TranscriptDelay int
+}
+
+// playerKey looks up a player's public key.
+func (g Game) playerKey(player trustdraw.PlayerNumber) (crypto.PublicKey, bool) {
+	if player < 1 || int(player) > len(g.PlayerKeys) {
+		return nil, false
+	}
+	return g.PlayerKeys[player-1], true
+}
+
+// Turn is a packed turn message sent to the relay.
+type Turn struct {
+	Seq     int                    `json:"seq"`
+	From    trustdraw.PlayerNumber `json:"from"`
+	Message string                 `json:"message"`
+}
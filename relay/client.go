@@ -0,0 +1,154 @@
+package relay
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/playerauth"
+)
+
+// Client talks to a relay Server.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	player    trustdraw.PlayerNumber
+	playerPrv crypto.Signer
+}
+
+// NewClient creates a Client for the relay at the given base URL, eg. "http://localhost:8080".
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// AsPlayer returns a copy of the Client that authenticates its requests as the given player,
+// which is needed for sending and receiving turn messages.
+func (c *Client) AsPlayer(player trustdraw.PlayerNumber, playerPrv crypto.Signer) *Client {
+	authed := *c
+	authed.player = player
+	authed.playerPrv = playerPrv
+	return &authed
+}
+
+// CreateGame hosts a deal on the relay, returning the game's ID.
+func (c *Client) CreateGame(deal []byte, dealerPub crypto.PublicKey, playerPubs ...crypto.PublicKey) (string, error) {
+	req := CreateGameRequest{Deal: string(deal)}
+
+	var err error
+	if req.DealerKey, err = encodePublicKey(dealerPub); err != nil {
+		return "", err
+	}
+	for _, pub := range playerPubs {
+		pemKey, err := encodePublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		req.PlayerKeys = append(req.PlayerKeys, pemKey)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	var res CreateGameResponse
+	if err := c.do(http.MethodPost, "/games", "application/json", body, false, &res); err != nil {
+		return "", err
+	}
+	return res.ID, nil
+}
+
+// Deal retrieves a hosted game's deal file.
+func (c *Client) Deal(gameID string) ([]byte, error) {
+	var deal []byte
+	err := c.do(http.MethodGet, "/games/"+url.PathEscape(gameID)+"/deal", "", nil, false, &deal)
+	return deal, err
+}
+
+// SendTurn sends a packed turn message (see trustdraw.TurnMessage.Pack) to the other players, returning its sequence number.
+func (c *Client) SendTurn(gameID string, message []byte) (int, error) {
+	var res SendTurnResponse
+	err := c.do(http.MethodPost, "/games/"+url.PathEscape(gameID)+"/turns", "text/plain; charset=utf-8", message, true, &res)
+	return res.Seq, err
+}
+
+// Turns retrieves the turn messages sent since the given sequence number (use 0 for all of them).
+func (c *Client) Turns(gameID string, after int) ([]Turn, error) {
+	var turns []Turn
+	path := "/games/" + url.PathEscape(gameID) + "/turns?after=" + strconv.Itoa(after)
+	err := c.do(http.MethodGet, path, "", nil, true, &turns)
+	return turns, err
+}
+
+// Transcript retrieves the public parts of every turn of a hosted game.
+func (c *Client) Transcript(gameID string) ([]TranscriptEntry, error) {
+	var entries []TranscriptEntry
+	err := c.do(http.MethodGet, "/games/"+url.PathEscape(gameID)+"/transcript", "", nil, false, &entries)
+	return entries, err
+}
+
+// do makes a request to the relay, decoding a JSON response into out (or copying it, if out is a *[]byte).
+func (c *Client) do(method, path, contentType string, body []byte, authed bool, out any) error {
+	req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if authed {
+		if c.playerPrv == nil {
+			return fmt.Errorf("the relay client must be used AsPlayer for this request")
+		}
+		if err := playerauth.Sign(req, c.player, c.playerPrv); err != nil {
+			return err
+		}
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not reach the relay: %w", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("could not read the relay's response: %w", err)
+	}
+
+	if res.StatusCode >= 300 {
+		var errRes errorResponse
+		if json.Unmarshal(data, &errRes) == nil && errRes.Error != "" {
+			return fmt.Errorf("relay error (%d): %s", res.StatusCode, errRes.Error)
+		}
+		return fmt.Errorf("relay error (%d)", res.StatusCode)
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func encodePublicKey(pub any) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
@@ -0,0 +1,78 @@
+package relay
+
+import "sync"
+
+// MemoryBackend is a Backend that holds games in memory, for testing and short-lived servers.
+type MemoryBackend struct {
+	mu    sync.RWMutex
+	games map[string]Game
+	turns map[string][]Turn
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+// NewMemoryBackend creates an empty in-memory Backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		games: make(map[string]Game),
+		turns: make(map[string][]Turn),
+	}
+}
+
+func (b *MemoryBackend) CreateGame(game Game) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.games[game.ID]; ok {
+		return ErrGameExists
+	}
+	b.games[game.ID] = game
+	return nil
+}
+
+func (b *MemoryBackend) Game(id string) (Game, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	game, ok := b.games[id]
+	if !ok {
+		return Game{}, ErrGameNotFound
+	}
+	return game, nil
+}
+
+func (b *MemoryBackend) AppendTurn(id string, turn Turn) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.games[id]; !ok {
+		return 0, ErrGameNotFound
+	}
+	for _, existing := range b.turns[id] {
+		if existing.Message == turn.Message {
+			return 0, ErrDuplicateMessage
+		}
+	}
+
+	turn.Seq = len(b.turns[id]) + 1
+	b.turns[id] = append(b.turns[id], turn)
+	return turn.Seq, nil
+}
+
+func (b *MemoryBackend) Turns(id string, after int) ([]Turn, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if _, ok := b.games[id]; !ok {
+		return nil, ErrGameNotFound
+	}
+	if after < 0 {
+		after = 0
+	}
+
+	turns := b.turns[id]
+	if after >= len(turns) {
+		return []Turn{}, nil
+	}
+	return append([]Turn(nil), turns[after:]...), nil
+}
@@ -0,0 +1,345 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+	"github.com/jphastings/trustdraw/internal/playerauth"
+)
+
+// maxBodySize limits the size of deal files and turn messages the relay will accept.
+const maxBodySize = 8 << 20
+
+// Server is an http.Handler that hosts games stored in a Backend.
+//
+//	POST /games                  Host a new game (CreateGameRequest)
+//	GET  /games/{id}/deal        The game's deal file
+//	GET  /games/{id}/turns       Turn messages sent so far (players only, ?after=seq)
+//	POST /games/{id}/turns       Send a turn message (players only)
+//	GET  /games/{id}/transcript  The public parts of every turn (but the latest, if the game has a TranscriptDelay)
+type Server struct {
+	backend Backend
+}
+
+// NewServer creates a relay Server storing its games in the given Backend.
+func NewServer(backend Backend) *Server {
+	return &Server{backend: backend}
+}
+
+// CreateGameRequest is the body of a request to host a new game.
+type CreateGameRequest struct {
+	Deal string `json:"deal"`
+	// DealerKey is the dealer's PEM or OpenSSH encoded RSA or Ed25519 public key, used to verify the deal.
+	DealerKey string `json:"dealerKey"`
+	// PlayerKeys are the players' PEM or OpenSSH encoded RSA or Ed25519 public keys, in player number order. They must
+	// be the keys the deal's roster lists, so deal files from before rosters (which don't list them) can't be hosted.
+	PlayerKeys []string `json:"playerKeys"`
+	// TranscriptDelay is the number of the latest turns to leave out of the public transcript, so that observers
+	// (like a stream's audience) can't see a turn until the game has moved on.
+	TranscriptDelay int `json:"transcriptDelay,omitempty"`
+}
+
+// CreateGameResponse is the body of the response to CreateGameRequest.
+type CreateGameResponse struct {
+	ID string `json:"id"`
+}
+
+// SendTurnResponse is the body of the response to sending a turn message.
+type SendTurnResponse struct {
+	Seq int `json:"seq"`
+}
+
+// TranscriptEntry holds the public parts of a turn message.
+type TranscriptEntry struct {
+	Seq        int                      `json:"seq"`
+	From       trustdraw.PlayerNumber   `json:"from"`
+	Recipients []trustdraw.PlayerNumber `json:"recipients,omitempty"`
+	Reveals    []Proof                  `json:"reveals,omitempty"`
+	Plays      []Proof                  `json:"plays,omitempty"`
+	Moves      []string                 `json:"moves,omitempty"`
+	// Rolls are the sender's commitments to, and reveals for, dice rolls, which anyone can check with
+	// trustdraw.VerifyRoll.
+	Rolls []string `json:"rolls,omitempty"`
+	// Steals are the sender's offers, picks and reveals for steals, which anyone can check with trustdraw.VerifySteal.
+	Steals []string `json:"steals,omitempty"`
+	// Reorders are the sender's new orders for cards they peeked at.
+	Reorders []string `json:"reorders,omitempty"`
+	// Burns and Discards are the sender's burns of cards from the deck, and face-down discards from their hand.
+	Burns    []string `json:"burns,omitempty"`
+	Discards []string `json:"discards,omitempty"`
+}
+
+// Proof is a card shown in a turn, with the allowKey that proves it.
+type Proof struct {
+	Card     string `json:"card"`
+	AllowKey string `json:"allowKey"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "games":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.createGame(w, r)
+	case len(parts) == 3 && parts[0] == "games":
+		game, err := s.backend.Game(parts[1])
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		switch {
+		case parts[2] == "deal" && r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write(game.Deal)
+		case parts[2] == "turns" && r.Method == http.MethodGet:
+			s.listTurns(w, r, game)
+		case parts[2] == "turns" && r.Method == http.MethodPost:
+			s.sendTurn(w, r, game)
+		case parts[2] == "transcript" && r.Method == http.MethodGet:
+			s.transcript(w, game)
+		case parts[2] == "deal" || parts[2] == "transcript":
+			methodNotAllowed(w, http.MethodGet)
+		case parts[2] == "turns":
+			methodNotAllowed(w, http.MethodGet, http.MethodPost)
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) createGame(w http.ResponseWriter, r *http.Request) {
+	var req CreateGameRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"request body is not valid JSON"})
+		return
+	}
+
+	dealerPub, err := cmdhelpers.ParseDealerPublicKey([]byte(req.DealerKey), "dealerKey")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	_, players, err := trustdraw.VerifyDeal(strings.NewReader(req.Deal), dealerPub)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("deal is not valid: %v", err)})
+		return
+	}
+	if players != len(req.PlayerKeys) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{
+			fmt.Sprintf("deal is for %d players, but %d player keys were given", players, len(req.PlayerKeys))})
+		return
+	}
+
+	if req.TranscriptDelay < 0 {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"transcriptDelay can't be negative"})
+		return
+	}
+
+	roster, err := trustdraw.DealRoster(strings.NewReader(req.Deal))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+	// Anyone can host a game, so the players' keys must be checked against the deal, or whoever hosts it first could
+	// pass their own keys off as the players'.
+	for _, entry := range roster {
+		if entry.Fingerprint == "" {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				"deal doesn't list its players' keys, so they can't be checked; deal it again with a newer version"})
+			return
+		}
+	}
+
+	game := Game{Deal: []byte(req.Deal), TranscriptDelay: req.TranscriptDelay}
+	for i, pemKey := range req.PlayerKeys {
+		pub, err := cmdhelpers.ParsePlayerPublicKey([]byte(pemKey), fmt.Sprintf("playerKeys[%d]", i))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+		if fingerprint, err := trustdraw.Fingerprint(pub); err != nil || fingerprint != roster[i].Fingerprint {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				fmt.Sprintf("playerKeys[%d] isn't the key the deal lists for player %d", i, i+1)})
+			return
+		}
+		game.PlayerKeys = append(game.PlayerKeys, pub)
+	}
+
+	if game.ID, err = trustdraw.DealID(strings.NewReader(req.Deal)); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+
+	if err := s.backend.CreateGame(game); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/games/"+game.ID)
+	writeJSON(w, http.StatusCreated, CreateGameResponse{ID: game.ID})
+}
+
+func (s *Server) sendTurn(w http.ResponseWriter, r *http.Request, game Game) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	player, err := playerauth.Verify(r, game.playerKey)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{err.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"unable to read turn message"})
+		return
+	}
+
+	pub, _ := game.playerKey(player)
+	msg, err := trustdraw.ReadTurnMessage(bytes.NewReader(data), pub)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+		return
+	}
+	if msg.GameID != game.ID {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"turn message is for a different game"})
+		return
+	}
+	if msg.From != player {
+		writeJSON(w, http.StatusForbidden, errorResponse{fmt.Sprintf("player %d cannot send turns for player %d", player, msg.From)})
+		return
+	}
+
+	seq, err := s.backend.AppendTurn(game.ID, Turn{From: player, Message: string(data)})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, SendTurnResponse{Seq: seq})
+}
+
+func (s *Server) listTurns(w http.ResponseWriter, r *http.Request, game Game) {
+	if _, err := playerauth.Verify(r, game.playerKey); err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{err.Error()})
+		return
+	}
+
+	after := 0
+	if afterText := r.URL.Query().Get("after"); afterText != "" {
+		var err error
+		if after, err = strconv.Atoi(afterText); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"after must be a sequence number"})
+			return
+		}
+	}
+
+	turns, err := s.backend.Turns(game.ID, after)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, turns)
+}
+
+func (s *Server) transcript(w http.ResponseWriter, game Game) {
+	turns, err := s.backend.Turns(game.ID, 0)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if game.TranscriptDelay >= len(turns) {
+		turns = nil
+	} else {
+		turns = turns[:len(turns)-game.TranscriptDelay]
+	}
+
+	entries := make([]TranscriptEntry, 0, len(turns))
+	for _, turn := range turns {
+		entry, err := transcriptEntry(game, turn)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// transcriptEntry extracts the public parts of a stored turn message.
+func transcriptEntry(game Game, turn Turn) (TranscriptEntry, error) {
+	pub, ok := game.playerKey(turn.From)
+	if !ok {
+		return TranscriptEntry{}, fmt.Errorf("turn %d is from an unknown player", turn.Seq)
+	}
+	msg, err := trustdraw.ReadTurnMessage(strings.NewReader(turn.Message), pub)
+	if err != nil {
+		return TranscriptEntry{}, fmt.Errorf("turn %d is invalid: %w", turn.Seq, err)
+	}
+
+	entry := TranscriptEntry{
+		Seq:        turn.Seq,
+		From:       turn.From,
+		Recipients: msg.Recipients(),
+		Moves:      msg.Moves,
+		Rolls:      msg.Rolls,
+		Steals:     msg.Steals,
+		Reorders:   msg.Reorders,
+		Burns:      msg.Burns,
+		Discards:   msg.Discards,
+	}
+	for _, reveal := range msg.Reveals {
+		entry.Reveals = append(entry.Reveals, Proof{Card: reveal.Card, AllowKey: reveal.AllowKey})
+	}
+	for _, play := range msg.Plays {
+		entry.Plays = append(entry.Plays, Proof{Card: play.Card, AllowKey: play.AllowKey})
+	}
+
+	return entry, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("unable to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrGameNotFound):
+		writeJSON(w, http.StatusNotFound, errorResponse{err.Error()})
+	case errors.Is(err, ErrGameExists), errors.Is(err, ErrDuplicateMessage):
+		writeJSON(w, http.StatusConflict, errorResponse{err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+}
@@ -0,0 +1,249 @@
+package relay_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/relay"
+)
+
+// testGame is a deal for two players, hosted on a relay running on localhost with the memory backend.
+type testGame struct {
+	client     *relay.Client
+	deal       []byte
+	dealerPrv  crypto.Signer
+	playerPrvs []crypto.Signer
+	games      []*trustdraw.Game
+}
+
+func newTestGame(t *testing.T) *testGame {
+	t.Helper()
+	server := httptest.NewServer(relay.NewServer(relay.NewMemoryBackend()))
+	t.Cleanup(server.Close)
+
+	tg := &testGame{client: relay.NewClient(server.URL), dealerPrv: testKey(t)}
+	roster := make([]trustdraw.Player, 2)
+	for i := range roster {
+		tg.playerPrvs = append(tg.playerPrvs, testKey(t))
+		roster[i] = trustdraw.Player{PublicKey: tg.playerPrvs[i].Public()}
+	}
+	var deal bytes.Buffer
+	if err := trustdraw.DealPlayers(&deal, []string{"A♠️", "2♠️", "3♠️", "4♠️"}, tg.dealerPrv, roster...); err != nil {
+		t.Fatalf("could not deal: %v", err)
+	}
+	tg.deal = deal.Bytes()
+	for _, prv := range tg.playerPrvs {
+		game, err := trustdraw.OpenGame(bytes.NewReader(tg.deal), prv, "")
+		if err != nil {
+			t.Fatalf("could not open game: %v", err)
+		}
+		tg.games = append(tg.games, game)
+	}
+	return tg
+}
+
+func (tg *testGame) playerPubs() []crypto.PublicKey {
+	return []crypto.PublicKey{tg.playerPrvs[0].Public(), tg.playerPrvs[1].Public()}
+}
+
+// pack packs a turn message from the given player.
+func (tg *testGame) pack(t *testing.T, msg trustdraw.TurnMessage) []byte {
+	t.Helper()
+	msg.GameID = tg.games[0].ID()
+	pubs := map[trustdraw.PlayerNumber]crypto.PublicKey{1: tg.playerPrvs[0].Public(), 2: tg.playerPrvs[1].Public()}
+	var packed bytes.Buffer
+	if err := msg.Pack(&packed, tg.playerPrvs[msg.From-1], pubs); err != nil {
+		t.Fatalf("could not pack turn message: %v", err)
+	}
+	return packed.Bytes()
+}
+
+func testKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	_, prv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prv
+}
+
+func TestRelayEndToEnd(t *testing.T) {
+	tg := newTestGame(t)
+	alice := tg.client.AsPlayer(1, tg.playerPrvs[0])
+	bob := tg.client.AsPlayer(2, tg.playerPrvs[1])
+
+	id, err := tg.client.CreateGame(tg.deal, tg.dealerPrv.Public(), tg.playerPubs()...)
+	if err != nil {
+		t.Fatalf("could not host the game: %v", err)
+	}
+	if id != tg.games[0].ID() {
+		t.Errorf("got game ID %s, want %s", id, tg.games[0].ID())
+	}
+	deal, err := tg.client.Deal(id)
+	if err != nil || !bytes.Equal(deal, tg.deal) {
+		t.Fatalf("got a different deal back (%v)", err)
+	}
+
+	// Bob allows Alice to draw, and sends her the allowKey through the relay.
+	forAlice, err := tg.games[1].AllowDraw(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq, err := bob.SendTurn(id, tg.pack(t, trustdraw.TurnMessage{
+		From:      2,
+		AllowKeys: map[trustdraw.PlayerNumber][]string{1: {forAlice}},
+		Moves:     []string{"draw"},
+	}))
+	if err != nil || seq != 1 {
+		t.Fatalf("could not send Bob's turn: seq %d, %v", seq, err)
+	}
+
+	turns, err := alice.Turns(id, 0)
+	if err != nil || len(turns) != 1 {
+		t.Fatalf("got %d turns (%v), want 1", len(turns), err)
+	}
+	msg, err := trustdraw.ReadTurnMessage(strings.NewReader(turns[0].Message), tg.playerPrvs[1].Public())
+	if err != nil {
+		t.Fatalf("could not read Bob's turn: %v", err)
+	}
+	_, allowKeys, err := msg.OpenAllowKeys(tg.playerPrvs[0])
+	if err != nil || len(allowKeys) != 1 {
+		t.Fatalf("got allowKeys %v (%v), want Bob's", allowKeys, err)
+	}
+	card, allowKey, _, err := tg.games[0].Draw(allowKeys...)
+	if err != nil {
+		t.Fatalf("could not draw: %v", err)
+	}
+
+	// Alice plays the card she drew, which Bob can check.
+	if _, err := alice.SendTurn(id, tg.pack(t, trustdraw.TurnMessage{
+		From:  1,
+		Plays: []trustdraw.CardProof{{Card: card, AllowKey: allowKey}},
+	})); err != nil {
+		t.Fatalf("could not send Alice's turn: %v", err)
+	}
+	if turns, err := bob.Turns(id, 1); err != nil || len(turns) != 1 || turns[0].From != 1 {
+		t.Fatalf("got turns %v (%v), want only Alice's", turns, err)
+	}
+
+	transcript, err := tg.client.Transcript(id)
+	if err != nil || len(transcript) != 2 {
+		t.Fatalf("got %d transcript entries (%v), want 2", len(transcript), err)
+	}
+	if got := transcript[0].Recipients; len(got) != 1 || got[0] != 1 {
+		t.Errorf("got recipients %v, want Alice", got)
+	}
+	if got := transcript[1].Plays; len(got) != 1 || got[0].Card != card || got[0].AllowKey != allowKey {
+		t.Errorf("got plays %v, want %s", got, card)
+	}
+	if ok, err := tg.games[1].VerifyDrawBy(1, transcript[1].Plays[0].Card, transcript[1].Plays[0].AllowKey); err != nil || !ok {
+		t.Errorf("Bob couldn't verify Alice's play from the transcript (%v)", err)
+	}
+
+	// The relay only passes on allowKeys encrypted for their player, so never learns the hidden card.
+	published, _ := json.Marshal(transcript[0])
+	if bytes.Contains(published, []byte(forAlice)) || strings.Contains(turns[0].Message, forAlice) {
+		t.Errorf("the relay saw Bob's allowKey for Alice")
+	}
+}
+
+func TestRelayRejections(t *testing.T) {
+	tg := newTestGame(t)
+	id, err := tg.client.CreateGame(tg.deal, tg.dealerPrv.Public(), tg.playerPubs()...)
+	if err != nil {
+		t.Fatalf("could not host the game: %v", err)
+	}
+	aliceTurn := tg.pack(t, trustdraw.TurnMessage{From: 1, Moves: []string{"pass"}})
+	if _, err := tg.client.AsPlayer(1, tg.playerPrvs[0]).SendTurn(id, aliceTurn); err != nil {
+		t.Fatalf("could not send Alice's turn: %v", err)
+	}
+	outsider := testKey(t)
+
+	tests := []struct {
+		name string
+		do   func() error
+	}{
+		{"hosting twice", func() error {
+			_, err := tg.client.CreateGame(tg.deal, tg.dealerPrv.Public(), tg.playerPubs()...)
+			return err
+		}},
+		{"hosting with the wrong dealer key", func() error {
+			_, err := newTestGame(t).client.CreateGame(tg.deal, outsider.Public(), tg.playerPubs()...)
+			return err
+		}},
+		{"hosting with swapped player keys", func() error {
+			pubs := tg.playerPubs()
+			_, err := newTestGame(t).client.CreateGame(tg.deal, tg.dealerPrv.Public(), pubs[1], pubs[0])
+			return err
+		}},
+		{"hosting with an outsider's key", func() error {
+			_, err := newTestGame(t).client.CreateGame(tg.deal, tg.dealerPrv.Public(), tg.playerPubs()[0], outsider.Public())
+			return err
+		}},
+		{"hosting with too few player keys", func() error {
+			_, err := newTestGame(t).client.CreateGame(tg.deal, tg.dealerPrv.Public(), tg.playerPubs()[0])
+			return err
+		}},
+		{"a tampered deal", func() error {
+			deal := bytes.Replace(tg.deal, []byte("\n"), []byte("\n\n"), 1)
+			_, err := newTestGame(t).client.CreateGame(deal, tg.dealerPrv.Public(), tg.playerPubs()...)
+			return err
+		}},
+		{"reading turns without authenticating", func() error {
+			res, err := http.Get(tg.client.BaseURL + "/games/" + id + "/turns")
+			if err != nil {
+				return err
+			}
+			defer res.Body.Close()
+			if res.StatusCode != http.StatusUnauthorized {
+				return nil
+			}
+			return fmt.Errorf("relay error (%d)", res.StatusCode)
+		}},
+		{"reading turns as an outsider", func() error {
+			_, err := tg.client.AsPlayer(1, outsider).Turns(id, 0)
+			return err
+		}},
+		{"sending a turn as an outsider", func() error {
+			_, err := tg.client.AsPlayer(2, outsider).SendTurn(id, tg.pack(t, trustdraw.TurnMessage{From: 2, Moves: []string{"pass"}}))
+			return err
+		}},
+		{"sending another player's turn", func() error {
+			_, err := tg.client.AsPlayer(2, tg.playerPrvs[1]).SendTurn(id, aliceTurn)
+			return err
+		}},
+		{"sending a turn twice", func() error {
+			_, err := tg.client.AsPlayer(1, tg.playerPrvs[0]).SendTurn(id, aliceTurn)
+			return err
+		}},
+		{"sending a tampered turn", func() error {
+			tampered := bytes.Replace(tg.pack(t, trustdraw.TurnMessage{From: 1, Moves: []string{"pass"}}), []byte("pass"), []byte("cheat"), 1)
+			_, err := tg.client.AsPlayer(1, tg.playerPrvs[0]).SendTurn(id, tampered)
+			return err
+		}},
+		{"reading an unknown game", func() error {
+			_, err := tg.client.Deal("nope")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.do(); err == nil {
+				t.Errorf("the relay allowed it")
+			}
+		})
+	}
+
+	if turns, err := tg.client.AsPlayer(2, tg.playerPrvs[1]).Turns(id, 0); err != nil || len(turns) != 1 {
+		t.Errorf("got %d turns (%v), want only Alice's first one", len(turns), err)
+	}
+}
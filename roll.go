@@ -0,0 +1,443 @@
+package trustdraw
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dice are rolled with a commit-reveal protocol, so that no player can choose (or know in advance) the outcome. Every
+// player commits to a random value by sharing its hash (see Game.Roll), and once every player has committed they
+// reveal their values (see Game.RevealRoll). The outcome is derived from all the values together (see
+// Game.FinishRoll), so it's random as long as any one player's value was. A player who won't reveal their value
+// stalls the roll for everyone, but can't change its outcome.
+//
+// Commitments and reveals can be shared like allowKeys, or in turn messages (which are signed by the sender),
+// so the roll is recorded in a relay's transcript, where anyone can check it with VerifyRoll.
+
+const (
+	// maxRollSides and maxRollCount limit the size of a roll.
+	maxRollSides = math.MaxUint16
+	maxRollCount = 256
+	// rollNonceSize is the size of the random value each player commits to.
+	rollNonceSize = 32
+	// rollCommitSize and rollRevealSize are the decoded sizes of a roll commitment and reveal.
+	rollCommitSize = 4 + 1 + 2 + 2 + sha256.Size
+	rollRevealSize = 4 + 1 + rollNonceSize
+)
+
+// DiceRoll is the outcome of a roll every player contributed to.
+type DiceRoll struct {
+	// Number counts the rolls made in this game, starting at 1.
+	Number int
+	Sides  int
+	Count  int
+	// Results holds the value of each die, from 1 to Sides.
+	Results []int
+
+	seed []byte
+}
+
+// Order uses the roll's randomness to put the numbers from 1 to Sides in a random order. Roll with as many sides as
+// there are players (and a count of 1) to pick a turn order.
+func (r DiceRoll) Order() []int {
+	stream := newRollStream(r.seed, "order")
+	order := make([]int, r.Sides)
+	for i := range order {
+		order[i] = i + 1
+	}
+	for i := len(order) - 1; i > 0; i-- {
+		j := stream.uniform(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// String describes the roll, like "2d6: 3 5".
+func (r DiceRoll) String() string {
+	results := make([]string, len(r.Results))
+	for i, result := range r.Results {
+		results[i] = strconv.Itoa(result)
+	}
+	return fmt.Sprintf("%dd%d: %s", r.Count, r.Sides, strings.Join(results, " "))
+}
+
+// pendingRoll is a roll this player has committed to, but which hasn't finished.
+type pendingRoll struct {
+	number, sides, count int
+	nonce                []byte
+	// commits holds every player's commitment, once this player has revealed their value.
+	commits []string
+}
+
+// Roll starts this player's part in the next roll of count dice, each with the given number of sides (a random card
+// from a hand of 7 is one 7 sided die), returning the commitment to share with the other players. Calling it again
+// before the roll finishes returns the same commitment.
+func (g *Game) Roll(sides, count int) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	if sides < 2 || sides > maxRollSides {
+		return "", fmt.Errorf("dice must have between 2 and %d sides", maxRollSides)
+	}
+	if count < 1 || count > maxRollCount {
+		return "", fmt.Errorf("between 1 and %d dice can be rolled at once", maxRollCount)
+	}
+
+	if g.rolling != nil {
+		if g.rolling.sides != sides || g.rolling.count != count {
+			return "", fmt.Errorf("roll %d (%dd%d) hasn't finished yet", g.rolling.number, g.rolling.count, g.rolling.sides)
+		}
+		return g.rollCommit(g.rolling), nil
+	}
+
+	nonce := make([]byte, rollNonceSize)
+	if _, err := crand.Read(nonce); err != nil {
+		return "", fmt.Errorf("unable to roll: %w", err)
+	}
+	g.rolling = &pendingRoll{number: len(g.rolls) + 1, sides: sides, count: count, nonce: nonce}
+	if err := g.Save(); err != nil {
+		g.rolling = nil
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return g.rollCommit(g.rolling), nil
+}
+
+// RevealRoll reveals this player's value for the roll in progress, once given every other player's commitment to
+// theirs. Calling it again returns the same reveal.
+func (g *Game) RevealRoll(commits ...string) (string, error) {
+	if g.rolling == nil {
+		return "", fmt.Errorf("you haven't started a roll")
+	}
+	if g.rolling.commits != nil {
+		return g.rollReveal(g.rolling), nil
+	}
+
+	all := append([]string{g.rollCommit(g.rolling)}, commits...)
+	for _, commit := range all {
+		number, player, sides, count, _, err := parseRollCommit(commit)
+		if err != nil {
+			return "", err
+		}
+		if number != g.rolling.number || sides != g.rolling.sides || count != g.rolling.count {
+			return "", fmt.Errorf("%s's commitment is for a different roll", g.PlayerName(player))
+		}
+	}
+	if err := g.checkRollers(all, "commitment"); err != nil {
+		return "", err
+	}
+
+	g.rolling.commits = all
+	if err := g.Save(); err != nil {
+		g.rolling.commits = nil
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return g.rollReveal(g.rolling), nil
+}
+
+// FinishRoll checks every other player's revealed value for the roll in progress against their commitment, and
+// works out the roll's outcome, which is recorded in this player's state.
+func (g *Game) FinishRoll(reveals ...string) (DiceRoll, error) {
+	if g.rolling == nil || g.rolling.commits == nil {
+		return DiceRoll{}, fmt.Errorf("you haven't revealed your value for a roll")
+	}
+
+	all := append([]string{g.rollReveal(g.rolling)}, reveals...)
+	if err := g.checkRollers(all, "reveal"); err != nil {
+		return DiceRoll{}, err
+	}
+	roll, err := VerifyRoll(g.id, g.rolling.commits, all)
+	if err != nil {
+		return DiceRoll{}, err
+	}
+
+	rolling := g.rolling
+	g.rolls = append(g.rolls, roll)
+	g.rolling = nil
+	if err := g.Save(); err != nil {
+		g.rolls = g.rolls[:len(g.rolls)-1]
+		g.rolling = rolling
+		return DiceRoll{}, fmt.Errorf("could not save game state: %w", err)
+	}
+	return roll, nil
+}
+
+// Rolls lists the rolls this player has taken part in, in order.
+func (g *Game) Rolls() []DiceRoll {
+	return append([]DiceRoll(nil), g.rolls...)
+}
+
+// VerifyRoll checks every player's reveal for a roll in the given game against their commitment, returning the
+// roll's outcome. It doesn't know who plays the game, so check that everyone's commitment is amongst them.
+func VerifyRoll(gameID string, commits, reveals []string) (DiceRoll, error) {
+	if len(commits) == 0 || len(commits) != len(reveals) {
+		return DiceRoll{}, fmt.Errorf("every commitment needs a reveal")
+	}
+
+	hashes := make(map[PlayerNumber][]byte)
+	var number, sides, count int
+	for i, commit := range commits {
+		n, player, s, c, hash, err := parseRollCommit(commit)
+		if err != nil {
+			return DiceRoll{}, err
+		}
+		if i == 0 {
+			number, sides, count = n, s, c
+		} else if n != number || s != sides || c != count {
+			return DiceRoll{}, fmt.Errorf("the commitments are for different rolls")
+		}
+		if _, ok := hashes[player]; ok {
+			return DiceRoll{}, fmt.Errorf("player %d committed more than once", player)
+		}
+		hashes[player] = hash
+	}
+
+	nonces := make(map[PlayerNumber][]byte)
+	for _, reveal := range reveals {
+		n, player, nonce, err := parseRollReveal(reveal)
+		if err != nil {
+			return DiceRoll{}, err
+		}
+		hash, ok := hashes[player]
+		if n != number || !ok {
+			return DiceRoll{}, fmt.Errorf("player %d's reveal isn't for this roll", player)
+		}
+		if !bytes.Equal(rollHash(gameID, number, player, sides, count, nonce), hash) {
+			return DiceRoll{}, fmt.Errorf("player %d's reveal doesn't match their commitment", player)
+		}
+		nonces[player] = nonce
+	}
+	if len(nonces) != len(hashes) {
+		return DiceRoll{}, fmt.Errorf("every commitment needs a reveal")
+	}
+
+	seed := sha256.New()
+	seed.Write([]byte("TrustDraw roll\x00" + gameID + "\x00"))
+	seed.Write(rollHeader(number, 0, sides, count))
+	for _, player := range sortedPlayers(nonces) {
+		seed.Write(nonces[player])
+	}
+
+	roll := DiceRoll{Number: number, Sides: sides, Count: count, seed: seed.Sum(nil)}
+	stream := newRollStream(roll.seed, "dice")
+	for i := 0; i < count; i++ {
+		roll.Results = append(roll.Results, stream.uniform(sides)+1)
+	}
+	return roll, nil
+}
+
+// checkRollers checks that every player still in the game made exactly one of the given commitments or reveals.
+func (g *Game) checkRollers(tokens []string, kind string) error {
+	made := make(map[PlayerNumber]bool)
+	for _, token := range tokens {
+		decoded, err := base64.RawStdEncoding.DecodeString(token)
+		if err != nil || len(decoded) < 5 {
+			return fmt.Errorf("invalid roll %s", kind)
+		}
+		player := PlayerNumber(decoded[4])
+		if made[player] {
+			return fmt.Errorf("more than one %s was given for %s", kind, g.PlayerName(player))
+		}
+		made[player] = true
+	}
+
+	for p := 1; p <= g.Players; p++ {
+		player := PlayerNumber(p)
+		if _, departed := g.recovered[player]; departed {
+			continue
+		}
+		if !made[player] {
+			return fmt.Errorf("%s's %s is needed", g.PlayerName(player), kind)
+		}
+		delete(made, player)
+	}
+	if extra := sortedPlayers(made); len(extra) > 0 {
+		return fmt.Errorf("%s isn't rolling in this game", g.PlayerName(extra[0]))
+	}
+	return nil
+}
+
+func (g *Game) rollCommit(roll *pendingRoll) string {
+	hash := rollHash(g.id, roll.number, g.playerNumber, roll.sides, roll.count, roll.nonce)
+	return base64.RawStdEncoding.EncodeToString(append(rollHeader(roll.number, g.playerNumber, roll.sides, roll.count), hash...))
+}
+
+func (g *Game) rollReveal(roll *pendingRoll) string {
+	reveal := rollHeader(roll.number, g.playerNumber, 0, 0)[:5]
+	return base64.RawStdEncoding.EncodeToString(append(reveal, roll.nonce...))
+}
+
+// rollHeader encodes a roll's number, the committing player, and the roll's sides and count.
+func rollHeader(number int, player PlayerNumber, sides, count int) []byte {
+	header := make([]byte, 9)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(number))
+	header[4] = byte(player)
+	binary.LittleEndian.PutUint16(header[5:7], uint16(sides))
+	binary.LittleEndian.PutUint16(header[7:9], uint16(count))
+	return header
+}
+
+// rollHash is what a player commits to: the hash of their value, bound to the game and the roll.
+func rollHash(gameID string, number int, player PlayerNumber, sides, count int, nonce []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte("TrustDraw roll commitment\x00" + gameID + "\x00"))
+	hash.Write(rollHeader(number, player, sides, count))
+	hash.Write(nonce)
+	return hash.Sum(nil)
+}
+
+func parseRollCommit(commit string) (number int, player PlayerNumber, sides, count int, hash []byte, err error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(commit)
+	if err != nil || len(decoded) != rollCommitSize {
+		return 0, 0, 0, 0, nil, fmt.Errorf("invalid roll commitment")
+	}
+	number = int(binary.LittleEndian.Uint32(decoded[0:4]))
+	sides = int(binary.LittleEndian.Uint16(decoded[5:7]))
+	count = int(binary.LittleEndian.Uint16(decoded[7:9]))
+	if sides < 2 || count < 1 || count > maxRollCount {
+		return 0, 0, 0, 0, nil, fmt.Errorf("invalid roll commitment")
+	}
+	return number, PlayerNumber(decoded[4]), sides, count, decoded[9:], nil
+}
+
+func parseRollReveal(reveal string) (number int, player PlayerNumber, nonce []byte, err error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(reveal)
+	if err != nil || len(decoded) != rollRevealSize {
+		return 0, 0, nil, fmt.Errorf("invalid roll reveal")
+	}
+	return int(binary.LittleEndian.Uint32(decoded[0:4])), PlayerNumber(decoded[4]), decoded[5:], nil
+}
+
+// rollStream draws uniformly random numbers from a roll's seed.
+type rollStream struct {
+	seed    []byte
+	counter uint32
+	buf     []byte
+}
+
+func newRollStream(seed []byte, purpose string) *rollStream {
+	sum := sha256.Sum256(append(append([]byte(nil), seed...), purpose...))
+	return &rollStream{seed: sum[:]}
+}
+
+// uniform returns a number from 0 to n-1, rejecting values that would make some numbers more likely than others.
+func (s *rollStream) uniform(n int) int {
+	limit := math.MaxUint64 - math.MaxUint64%uint64(n)
+	for {
+		if len(s.buf) < 8 {
+			block := make([]byte, 4)
+			binary.LittleEndian.PutUint32(block, s.counter)
+			s.counter++
+			sum := sha256.Sum256(append(append([]byte(nil), s.seed...), block...))
+			s.buf = sum[:]
+		}
+		value := binary.LittleEndian.Uint64(s.buf[:8])
+		s.buf = s.buf[8:]
+		if value < limit {
+			return int(value % uint64(n))
+		}
+	}
+}
+
+// usableRoll returns this player's record of the given roll, which must be one they took part in, and which hasn't
+// picked a card or cut the deck yet. The record is used, rather than the given roll, so its results can't have been
+// changed. The caller records the roll in usedRolls once it's used.
+func (g *Game) usableRoll(roll DiceRoll) (DiceRoll, error) {
+	if roll.Number < 1 || roll.Number > len(g.rolls) || !bytes.Equal(g.rolls[roll.Number-1].seed, roll.seed) {
+		return DiceRoll{}, fmt.Errorf("you didn't take part in roll %d", roll.Number)
+	}
+	if g.usedRolls[roll.Number] {
+		return DiceRoll{}, fmt.Errorf("roll %d has already been used", roll.Number)
+	}
+	return g.rolls[roll.Number-1], nil
+}
+
+// rollStateLines encodes the roll in progress, the finished rolls, and which of them have been used, as state lines.
+func (g *Game) rollStateLines() []string {
+	var lines []string
+	for _, roll := range g.rolls {
+		results := make([]string, len(roll.Results))
+		for i, result := range roll.Results {
+			results[i] = strconv.Itoa(result)
+		}
+		lines = append(lines, fmt.Sprintf("rolled %d %d %d %s %s", roll.Number, roll.Sides, roll.Count,
+			strings.Join(results, ","), base64.RawStdEncoding.EncodeToString(roll.seed)))
+	}
+	if roll := g.rolling; roll != nil {
+		fields := []string{"rolling", strconv.Itoa(roll.number), strconv.Itoa(roll.sides), strconv.Itoa(roll.count),
+			base64.RawStdEncoding.EncodeToString(roll.nonce)}
+		lines = append(lines, strings.Join(append(fields, roll.commits...), " "))
+	}
+	var used []string
+	for _, roll := range g.rolls {
+		if g.usedRolls[roll.Number] {
+			used = append(used, strconv.Itoa(roll.Number))
+		}
+	}
+	if len(used) > 0 {
+		lines = append(lines, "used "+strings.Join(used, ","))
+	}
+	return lines
+}
+
+// loadRollState reads a "rolled", "rolling" or "used" state line.
+func (g *Game) loadRollState(fields []string) error {
+	if fields[0] == "used" && len(fields) == 2 {
+		for _, number := range strings.Split(fields[1], ",") {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 1 {
+				return fmt.Errorf("state holds an invalid used roll")
+			}
+			g.usedRolls[n] = true
+		}
+		return nil
+	}
+	if len(fields) < 5 {
+		return fmt.Errorf("state holds an invalid roll")
+	}
+	var numbers [3]int
+	for i := range numbers {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("state holds an invalid roll")
+		}
+		numbers[i] = n
+	}
+
+	if fields[0] == "rolling" {
+		nonce, err := base64.RawStdEncoding.DecodeString(fields[4])
+		if err != nil || len(nonce) != rollNonceSize {
+			return fmt.Errorf("state holds an invalid roll")
+		}
+		g.rolling = &pendingRoll{number: numbers[0], sides: numbers[1], count: numbers[2], nonce: nonce}
+		if len(fields) > 5 {
+			g.rolling.commits = fields[5:]
+		}
+		return nil
+	}
+
+	if len(fields) != 6 {
+		return fmt.Errorf("state holds an invalid roll")
+	}
+	roll := DiceRoll{Number: numbers[0], Sides: numbers[1], Count: numbers[2]}
+	for _, result := range strings.Split(fields[4], ",") {
+		n, err := strconv.Atoi(result)
+		if err != nil || n < 1 || n > roll.Sides {
+			return fmt.Errorf("state holds an invalid roll")
+		}
+		roll.Results = append(roll.Results, n)
+	}
+	seed, err := base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil || len(roll.Results) != roll.Count {
+		return fmt.Errorf("state holds an invalid roll")
+	}
+	roll.seed = seed
+	g.rolls = append(g.rolls, roll)
+	return nil
+}
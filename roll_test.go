@@ -0,0 +1,166 @@
+package trustdraw
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+// testRoll has every player roll the given dice, returning their commitments and reveals, and the roll.
+func testRoll(t *testing.T, games []*Game, sides, count int) ([]string, []string, DiceRoll) {
+	t.Helper()
+	var commits, reveals []string
+	for _, game := range games {
+		commit, err := game.Roll(sides, count)
+		if err != nil {
+			t.Fatalf("player %d could not roll: %v", game.PlayerNumber(), err)
+		}
+		commits = append(commits, commit)
+	}
+	for i, game := range games {
+		reveal, err := game.RevealRoll(without(commits, i)...)
+		if err != nil {
+			t.Fatalf("player %d could not reveal: %v", game.PlayerNumber(), err)
+		}
+		reveals = append(reveals, reveal)
+	}
+
+	var roll DiceRoll
+	for i, game := range games {
+		finished, err := game.FinishRoll(without(reveals, i)...)
+		if err != nil {
+			t.Fatalf("player %d could not finish the roll: %v", game.PlayerNumber(), err)
+		}
+		if i > 0 && !reflect.DeepEqual(finished.Results, roll.Results) {
+			t.Fatalf("player %d rolled %v, but player 1 rolled %v", game.PlayerNumber(), finished.Results, roll.Results)
+		}
+		roll = finished
+	}
+	return commits, reveals, roll
+}
+
+// without copies a list, leaving out the given index.
+func without(list []string, i int) []string {
+	return append(append([]string(nil), list[:i]...), list[i+1:]...)
+}
+
+func TestRolls(t *testing.T) {
+	tests := []struct {
+		name    string
+		players int
+		sides   int
+		count   int
+	}{
+		{"one die", 2, 6, 1},
+		{"many dice", 3, 20, 5},
+		{"a random order", 3, 7, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deal, prvs, _ := testDeal(t, tt.players, DealOptions{})
+			var games []*Game
+			for _, prv := range prvs {
+				games = append(games, openTestGame(t, deal, prv, ""))
+			}
+			for number := 1; number <= 2; number++ {
+				commits, reveals, roll := testRoll(t, games, tt.sides, tt.count)
+				if roll.Number != number || roll.Sides != tt.sides || len(roll.Results) != tt.count {
+					t.Errorf("got roll %d of %dd%d, want %d of %dd%d", roll.Number, len(roll.Results), roll.Sides, number, tt.count, tt.sides)
+				}
+				for _, result := range roll.Results {
+					if result < 1 || result > tt.sides {
+						t.Errorf("rolled %d on a %d sided die", result, tt.sides)
+					}
+				}
+
+				// Anyone can check the roll from the commitments and reveals, in any order.
+				verified, err := VerifyRoll(games[0].ID(), commits, append(without(reveals, 0), reveals[0]))
+				if err != nil || !reflect.DeepEqual(verified.Results, roll.Results) {
+					t.Errorf("got %v (%v) verifying the roll, want %v", verified.Results, err, roll.Results)
+				}
+			}
+
+			// The rolls are kept in the player's state.
+			if got := openTestGame(t, deal, prvs[0], games[0].State()).Rolls(); !reflect.DeepEqual(got, games[0].Rolls()) {
+				t.Errorf("got rolls %v after loading the state, want %v", got, games[0].Rolls())
+			}
+		})
+	}
+}
+
+func TestRollTampering(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	commits, reveals, _ := testRoll(t, games, 6, 2)
+	otherGames, _ := testGames(t, 3, DealOptions{})
+	otherCommits, otherReveals, _ := testRoll(t, otherGames, 6, 2)
+	laterCommits, laterReveals, _ := testRoll(t, games, 6, 2)
+
+	changed, _ := base64.RawStdEncoding.DecodeString(reveals[1])
+	changed[len(changed)-1] ^= 1
+	relabelled, _ := base64.RawStdEncoding.DecodeString(reveals[1])
+	relabelled[4] = 3
+
+	tests := []struct {
+		name    string
+		gameID  string
+		commits []string
+		reveals []string
+	}{
+		{"changed reveal", games[0].ID(), commits, []string{reveals[0], base64.RawStdEncoding.EncodeToString(changed), reveals[2]}},
+		{"reveal given as another player's", games[0].ID(), commits, []string{reveals[0], base64.RawStdEncoding.EncodeToString(relabelled), reveals[2]}},
+		{"missing reveal", games[0].ID(), commits, reveals[:2]},
+		{"repeated reveal", games[0].ID(), commits, []string{reveals[0], reveals[1], reveals[1]}},
+		{"repeated commitment", games[0].ID(), []string{commits[0], commits[1], commits[1]}, reveals},
+		{"reveal from a later roll", games[0].ID(), commits, []string{reveals[0], reveals[1], laterReveals[2]}},
+		{"commitments from different rolls", games[0].ID(), []string{commits[0], commits[1], laterCommits[2]}, reveals},
+		{"roll from another game", games[0].ID(), otherCommits, otherReveals},
+		{"wrong game", otherGames[0].ID(), commits, reveals},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if roll, err := VerifyRoll(tt.gameID, tt.commits, tt.reveals); err == nil {
+				t.Errorf("a tampered roll was verified as %v", roll.Results)
+			}
+		})
+	}
+}
+
+func TestRollingNeedsEveryPlayer(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	var commits []string
+	for _, game := range games {
+		commit, err := game.Roll(6, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, commit)
+	}
+	otherGames, _ := testGames(t, 3, DealOptions{})
+	different, err := otherGames[1].Roll(8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		commits []string
+	}{
+		{"missing commitment", commits[1:2]},
+		{"repeated commitment", []string{commits[1], commits[1]}},
+		{"commitment for a different roll", []string{different, commits[2]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reveal, err := games[0].RevealRoll(tt.commits...)
+			if err == nil {
+				t.Fatalf("revealed %s without every player's commitment", reveal)
+			}
+		})
+	}
+
+	// The used rolls line is only written once a roll has been used.
+	if bytes.Contains([]byte(games[0].State()), []byte("used")) {
+		t.Errorf("the state lists used rolls before any were used: %q", games[0].State())
+	}
+}
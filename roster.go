@@ -0,0 +1,269 @@
+package trustdraw
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxNameLength limits the length of players' names, in bytes.
+const maxNameLength = 64
+
+// Role is the part someone dealt into a game plays in it.
+type Role int
+
+const (
+	// RolePlayer can allow draws, draw and verify cards.
+	RolePlayer Role = iota
+	// RoleObserver can see cards that are revealed with every player's allowKey for them (see HeldCard.AllowKeys),
+	// but can't allow draws or draw.
+	RoleObserver
+	// RoleGod is an observer who can also be given every player's key stack once the game is over (see Game.Release),
+	// so they can see every card.
+	RoleGod
+)
+
+// roleEntries are the deal file header entries that list each role.
+var roleEntries = map[Role]string{RolePlayer: "player", RoleObserver: "observer", RoleGod: "god"}
+
+// Player is someone to deal a game to.
+type Player struct {
+	// Name is shown to the other players in place of the player's number. It is optional, but can't contain
+	// whitespace or be only digits.
+	Name      string
+	PublicKey crypto.PublicKey
+	// Role is RolePlayer unless this is an observer. Observers must come after all the players.
+	Role Role
+}
+
+// RosterEntry describes one of the players (or observers) of a game, as listed in the deal file.
+type RosterEntry struct {
+	// Player is the player's number. Observers are numbered after the players.
+	Player PlayerNumber
+	// Name is empty if the player wasn't given one, or the deal file predates rosters.
+	Name string
+	// Fingerprint identifies the player's public key (see Fingerprint). It is empty if the deal file predates rosters.
+	Fingerprint string
+	Role        Role
+}
+
+// Fingerprint identifies a public key: the SHA-256 hash of its PKIX encoding, like "SHA256:…".
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(der)
+	return "SHA256:" + base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// Roster lists the players of the game, in player number order.
+func (g *Game) Roster() []RosterEntry {
+	return append([]RosterEntry(nil), g.roster[:g.Players]...)
+}
+
+// Observers lists the game's observers, in number order.
+func (g *Game) Observers() []RosterEntry {
+	return append([]RosterEntry(nil), g.roster[g.Players:]...)
+}
+
+// PlayerName returns the name of the given player (or observer), or "player N" ("observer N") if they don't have one.
+func (g *Game) PlayerName(player PlayerNumber) string {
+	if player < 1 || int(player) > len(g.roster) {
+		return fmt.Sprintf("player %d", player)
+	}
+
+	entry := g.roster[player-1]
+	switch {
+	case entry.Name != "":
+		return entry.Name
+	case entry.Role != RolePlayer:
+		return fmt.Sprintf("observer %d", player)
+	default:
+		return fmt.Sprintf("player %d", player)
+	}
+}
+
+// FindPlayer finds the player with the given name, or player number.
+func (g *Game) FindPlayer(who string) (PlayerNumber, error) {
+	if number, err := strconv.Atoi(who); err == nil {
+		if number < 1 || number > g.Players {
+			return 0, fmt.Errorf("player %d is not in this game (there are %d players)", number, g.Players)
+		}
+		return PlayerNumber(number), nil
+	}
+
+	for _, entry := range g.roster {
+		if entry.Name != who {
+			continue
+		}
+		if entry.Role != RolePlayer {
+			return 0, fmt.Errorf("%s is observing this game, not playing it", who)
+		}
+		return entry.Player, nil
+	}
+	return 0, fmt.Errorf("nobody called '%s' is in this game", who)
+}
+
+// validateRoster checks that players' names can be written in a deal file, and can't be mistaken for each other,
+// and that any observers come after the players.
+func validateRoster(players []Player) error {
+	seen := make(map[string]bool)
+	for i, player := range players {
+		if _, ok := roleEntries[player.Role]; !ok {
+			return fmt.Errorf("player %d has an unknown role", i+1)
+		}
+		if i > 0 && player.Role == RolePlayer && players[i-1].Role != RolePlayer {
+			return fmt.Errorf("observers must come after all the players")
+		}
+
+		name := player.Name
+		if name == "" {
+			continue
+		}
+
+		if len(name) > maxNameLength {
+			return fmt.Errorf("player %d's name is too long, must be %d bytes or fewer", i+1, maxNameLength)
+		}
+		if strings.IndexFunc(name, unicode.IsSpace) != -1 {
+			return fmt.Errorf("player %d's name (%s) can't contain whitespace", i+1, name)
+		}
+		if _, err := strconv.Atoi(name); err == nil {
+			return fmt.Errorf("player %d's name (%s) can't be a number", i+1, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("more than one player is called %s", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// writeRoster writes a "player" line to the deal file header for each player, and an "observer" or "god" line for
+// each observer.
+func writeRoster(w io.Writer, players []Player) error {
+	for i, player := range players {
+		fingerprint, err := Fingerprint(player.PublicKey)
+		if err != nil {
+			return fmt.Errorf("unable to fingerprint player %d's key: %w", i+1, err)
+		}
+
+		line := strings.TrimSpace(fmt.Sprintf("%s %d %s %s", roleEntries[player.Role], i+1, fingerprint, player.Name))
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dealHeader holds what the header stanza of a deal file says about the game.
+type dealHeader struct {
+	// roster lists the players, then the observers.
+	roster  []RosterEntry
+	players int
+	// escrow is the number of players needed to recover a departed player's key shares, or 0 if they can't be.
+	escrow int
+	// piles lists the piles dealt alongside the main deck, in the order they come in the deck.
+	piles []pileRange
+}
+
+// parseHeader reads the header stanza of a deal file with key stacks for the given number of players and observers.
+// Deal files from before rosters were added get a roster entry for each player with no name or fingerprint.
+func parseHeader(header string, stacks int) (dealHeader, error) {
+	var parsed dealHeader
+	listed := make(map[int]RosterEntry)
+	observers := 0
+
+	lines := strings.Split(header, "\n")
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "escrow" {
+			threshold, err := strconv.Atoi(strings.Join(fields[1:], " "))
+			if err != nil || threshold < 2 || parsed.escrow != 0 {
+				return dealHeader{}, fmt.Errorf("deal file escrow threshold is badly formed")
+			}
+			parsed.escrow = threshold
+			continue
+		}
+		if fields[0] == "pile" {
+			pile, err := parsePile(fields)
+			if err != nil {
+				return dealHeader{}, err
+			}
+			for _, other := range parsed.piles {
+				if other.name == pile.name {
+					return dealHeader{}, fmt.Errorf("deal file lists pile %s more than once", pile.name)
+				}
+			}
+			parsed.piles = append(parsed.piles, pile)
+			continue
+		}
+
+		role, ok := entryRole(fields[0])
+		if !ok {
+			return dealHeader{}, fmt.Errorf("unknown deal file header entry: %s", fields[0])
+		}
+		if len(fields) < 3 || len(fields) > 4 {
+			return dealHeader{}, fmt.Errorf("deal file roster is badly formed")
+		}
+		number, err := strconv.Atoi(fields[1])
+		if err != nil || number < 1 || number > stacks {
+			return dealHeader{}, fmt.Errorf("deal file roster lists a player who isn't in the game")
+		}
+		if _, ok := listed[number]; ok {
+			return dealHeader{}, fmt.Errorf("deal file roster lists player %d more than once", number)
+		}
+
+		entry := RosterEntry{Player: PlayerNumber(number), Fingerprint: fields[2], Role: role}
+		if len(fields) == 4 {
+			entry.Name = fields[3]
+		}
+		listed[number] = entry
+		if role != RolePlayer {
+			observers++
+		}
+	}
+
+	parsed.players = stacks - observers
+	parsed.roster = make([]RosterEntry, stacks)
+	named := make([]Player, stacks)
+	for i := range parsed.roster {
+		entry, ok := listed[i+1]
+		if !ok {
+			entry = RosterEntry{Player: PlayerNumber(i + 1)}
+		}
+		if (entry.Role == RolePlayer) != (i < parsed.players) {
+			return dealHeader{}, fmt.Errorf("deal file roster doesn't list the observers after the players")
+		}
+		parsed.roster[i] = entry
+		named[i] = Player{Name: entry.Name, Role: entry.Role}
+	}
+
+	if err := validateRoster(named); err != nil {
+		return dealHeader{}, fmt.Errorf("deal file roster is invalid: %w", err)
+	}
+	if parsed.escrow >= parsed.players {
+		return dealHeader{}, fmt.Errorf("deal file escrow threshold is more than the other players")
+	}
+	return parsed, nil
+}
+
+// entryRole finds the role a deal file header entry lists, if it lists one.
+func entryRole(entry string) (Role, bool) {
+	for role, name := range roleEntries {
+		if name == entry {
+			return role, true
+		}
+	}
+	return 0, false
+}
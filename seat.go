@@ -0,0 +1,609 @@
+package trustdraw
+
+import (
+	"crypto"
+	crand "crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Players can be seated in a game in progress. A seat is made of signed parts: one from the dealer, or one from each
+// player already in the game. Together they give every existing player a random delta for each card still in the
+// deck, which they XOR into their key share for it, and give the new player the XOR of all those deltas as their key
+// share. The deltas cancel out, so every card's key stays the same, but the undrawn cards now need the new player's
+// allowKey too. Nobody but the new player (and the dealer, for seats the dealer issues) knows their key shares.
+//
+// Seats are added to the end of the deal file, one stanza each (see ExtendDeal), and applied by OpenGame.
+
+// seatVersion is the first line of every seat part.
+const seatVersion = "TrustDraw-Seat/v1.0"
+
+// notInDeck is the state of cards that had already left the deck when this player was seated.
+// They can't know who holds them.
+const notInDeck PlayerNumber = 255
+
+// seat is one player seated in a game in progress, and the parts that seat them.
+type seat struct {
+	entry RosterEntry
+	// cards lists the IDs of the cards that are re-keyed, in order.
+	cards []int
+	parts []seatPart
+}
+
+// seatPart is what the dealer, or one existing player, gives towards seating a new player.
+type seatPart struct {
+	gameID string
+	entry  RosterEntry
+	cards  []int
+	// issuer is the player who issued this part, or 0 if the dealer did. Players include their public key.
+	issuer    PlayerNumber
+	issuerPub crypto.PublicKey
+	// rekeys holds the deltas for each existing player this part covers, sealed for them.
+	rekeys map[PlayerNumber][]byte
+	// share holds the XOR of this part's deltas, sealed for the new player.
+	share []byte
+
+	body []byte
+	sig  []byte
+}
+
+// SeatPlayer issues a seat for a new player in a game in progress, as the game's dealer. cards lists the IDs of the
+// cards still in the deck (see Game.Undrawn), which every player must agree on, and playerPubs are the public keys of
+// the players already in the game, in player number order. The seat must be added to the deal file with ExtendDeal.
+func SeatPlayer(dealFile io.Reader, dealerPrv crypto.Signer, newPlayer Player, cards []int, playerPubs ...crypto.PublicKey) (string, error) {
+	stanzas, err := extractStanzas(dealFile)
+	if err != nil {
+		return "", err
+	}
+	header, _, err := readSeats(stanzas)
+	if err != nil {
+		return "", err
+	}
+	if err := checkSeat(header.roster, header.players, header.escrow, newPlayer); err != nil {
+		return "", err
+	}
+	if err := checkCardIDs(cards, len(strings.Split(stanzas[1], "\n"))); err != nil {
+		return "", err
+	}
+	if len(playerPubs) != header.players {
+		return "", fmt.Errorf("the public keys of all %d players are needed", header.players)
+	}
+	for i, pub := range playerPubs {
+		fingerprint, err := Fingerprint(pub)
+		if err != nil {
+			return "", err
+		}
+		if listed := header.roster[i].Fingerprint; listed != "" && listed != fingerprint {
+			return "", fmt.Errorf("the deal file's roster lists a different key for player %d", i+1)
+		}
+	}
+
+	part := seatPart{
+		gameID: dealID(stanzas[3]),
+		entry:  seatEntry(header.players, newPlayer),
+		cards:  cards,
+		rekeys: make(map[PlayerNumber][]byte),
+	}
+	deltas := make([][]byte, len(playerPubs))
+	for i, pub := range playerPubs {
+		if deltas[i], err = randomDeltas(len(cards)); err != nil {
+			return "", err
+		}
+		if part.rekeys[PlayerNumber(i+1)], err = seal(deltas[i], pub); err != nil {
+			return "", fmt.Errorf("unable to encrypt the re-keying for player %d: %w", i+1, err)
+		}
+	}
+	if part.share, err = seal(xor(deltas...), newPlayer.PublicKey); err != nil {
+		return "", fmt.Errorf("unable to encrypt the key shares for the new player: %w", err)
+	}
+
+	return part.sign(dealerPrv)
+}
+
+// OfferSeat issues this player's part of a seat for a new player, for when the existing players seat them jointly.
+// Every player must offer a part for the same cards, so must agree on which are still in the deck.
+// The parts must be added to the deal file together with ExtendDeal.
+func (g *Game) OfferSeat(newPlayer Player, playerPrv crypto.Signer) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	if err := checkSeat(g.roster, g.Players, g.escrow, newPlayer); err != nil {
+		return "", err
+	}
+	if g.roster[g.playerNumber-1].Fingerprint == "" {
+		return "", fmt.Errorf("players can only seat new players in deal files with a roster, ask the dealer instead")
+	}
+	cards := g.Undrawn()
+	if len(cards) == 0 {
+		return "", ErrNoCardsLeft
+	}
+
+	delta, err := randomDeltas(len(cards))
+	if err != nil {
+		return "", err
+	}
+	part := seatPart{
+		gameID:    g.id,
+		entry:     seatEntry(g.Players, newPlayer),
+		cards:     cards,
+		issuer:    g.playerNumber,
+		issuerPub: playerPrv.Public(),
+	}
+	rekey, err := seal(delta, playerPrv.Public())
+	if err != nil {
+		return "", fmt.Errorf("unable to encrypt your re-keying: %w", err)
+	}
+	part.rekeys = map[PlayerNumber][]byte{g.playerNumber: rekey}
+	if part.share, err = seal(delta, newPlayer.PublicKey); err != nil {
+		return "", fmt.Errorf("unable to encrypt the key shares for the new player: %w", err)
+	}
+
+	return part.sign(playerPrv)
+}
+
+// Undrawn lists the IDs of the cards still in the deck, according to this player's state.
+func (g *Game) Undrawn() []int {
+	var cards []int
+	for cardID, owner := range g.state {
+		if owner == 0 {
+			cards = append(cards, cardID)
+		}
+	}
+	return cards
+}
+
+// ExtendDeal writes a copy of the deal file with a new player seated in it: either the dealer's seat, or every
+// existing player's part of it. Everyone must then use the extended deal file, which has the same ID.
+func ExtendDeal(dealFile io.Reader, extended io.Writer, seatParts ...string) error {
+	stanzas, err := extractStanzas(dealFile)
+	if err != nil {
+		return err
+	}
+	stanzas = append(stanzas, strings.Join(seatParts, "\n"))
+	if _, _, err := readSeats(stanzas); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(extended, strings.Join(stanzas, "\n\n"))
+	return err
+}
+
+// FormatCardIDs writes a list of card IDs as the card numbers people see, with runs as ranges, like "3,13-52".
+func FormatCardIDs(cards []int) string {
+	var ranges []string
+	for i := 0; i < len(cards); {
+		j := i
+		for j+1 < len(cards) && cards[j+1] == cards[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(cards[i]+1))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", cards[i]+1, cards[j]+1))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
+// ParseCardIDs reads a list of card IDs written by FormatCardIDs.
+func ParseCardIDs(list string) ([]int, error) {
+	var cards []int
+	for _, r := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(r, "-")
+		from, err := strconv.Atoi(first)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(last)
+		}
+		if err != nil || from < 1 || to < from || to > maxCards {
+			return nil, fmt.Errorf("invalid list of cards: %s", list)
+		}
+		for card := from; card <= to; card++ {
+			cards = append(cards, card-1)
+		}
+	}
+	return cards, nil
+}
+
+// checkSeat checks that a new player can be seated in a game with the given roster.
+func checkSeat(roster []RosterEntry, players, escrow int, newPlayer Player) error {
+	if escrow > 0 {
+		return fmt.Errorf("players can't be seated in games dealt with escrow")
+	}
+	if newPlayer.Role != RolePlayer {
+		return fmt.Errorf("only players can be seated in a game in progress")
+	}
+	if players+1 > MaxPlayers {
+		return fmt.Errorf("no more than %d players are allowed", MaxPlayers)
+	}
+	if err := checkKey(newPlayer.PublicKey, "the new player's"); err != nil {
+		return err
+	}
+	fingerprint, err := Fingerprint(newPlayer.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	named := []Player{newPlayer}
+	for _, entry := range roster {
+		if entry.Fingerprint == fingerprint {
+			return fmt.Errorf("that key is already in this game")
+		}
+		named = append(named, Player{Name: entry.Name})
+	}
+	return validateRoster(named)
+}
+
+// checkCardIDs checks that a list of card IDs are in order, with no repeats, and in a deck of the given size.
+func checkCardIDs(cards []int, cardCount int) error {
+	if len(cards) == 0 {
+		return fmt.Errorf("no cards are left to re-key")
+	}
+	for i, cardID := range cards {
+		if cardID < 0 || cardID >= cardCount || (i > 0 && cardID <= cards[i-1]) {
+			return fmt.Errorf("the cards to re-key must be in the deck, in order")
+		}
+	}
+	return nil
+}
+
+// seatEntry is the roster entry for a new player seated in a game with the given number of players.
+func seatEntry(players int, newPlayer Player) RosterEntry {
+	fingerprint, _ := Fingerprint(newPlayer.PublicKey)
+	return RosterEntry{Player: PlayerNumber(players + 1), Name: newPlayer.Name, Fingerprint: fingerprint}
+}
+
+// randomDeltas makes a random delta for each of the given number of cards.
+func randomDeltas(cards int) ([]byte, error) {
+	deltas := make([]byte, cards*aesCipherSize)
+	if _, err := crand.Read(deltas); err != nil {
+		return nil, fmt.Errorf("unable to re-key the deck: %w", err)
+	}
+	return deltas, nil
+}
+
+// sign signs a seat part, returning it as it is written in the deal file.
+func (p *seatPart) sign(prv crypto.Signer) (string, error) {
+	lines := []string{
+		seatVersion,
+		"game " + p.gameID,
+		strings.TrimSpace(fmt.Sprintf("seat %d %s %s", p.entry.Player, p.entry.Fingerprint, p.entry.Name)),
+		"cards " + FormatCardIDs(p.cards),
+	}
+	if p.issuer == 0 {
+		lines = append(lines, "issuer dealer")
+	} else {
+		der, err := x509.MarshalPKIXPublicKey(p.issuerPub)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("issuer %d %s", p.issuer, base64.RawStdEncoding.EncodeToString(der)))
+	}
+	for _, player := range sortedPlayers(p.rekeys) {
+		lines = append(lines, fmt.Sprintf("rekey %d %s", player, base64.RawStdEncoding.EncodeToString(p.rekeys[player])))
+	}
+	lines = append(lines, "share "+base64.RawStdEncoding.EncodeToString(p.share))
+
+	body := []byte(strings.Join(lines, "\n"))
+	sig, err := signDeal(prv, body)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign the seat: %w", err)
+	}
+	return base64.RawStdEncoding.EncodeToString(body) + "." + base64.RawStdEncoding.EncodeToString(sig), nil
+}
+
+// parseSeatPart reads a seat part, as written in the deal file. It doesn't check the signature.
+func parseSeatPart(line string) (seatPart, error) {
+	invalid := fmt.Errorf("seat part is badly formed")
+
+	bodyB64, sigB64, ok := strings.Cut(strings.TrimSpace(line), ".")
+	if !ok {
+		return seatPart{}, invalid
+	}
+	body, err := base64.RawStdEncoding.DecodeString(bodyB64)
+	if err != nil {
+		return seatPart{}, invalid
+	}
+	sig, err := base64.RawStdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return seatPart{}, invalid
+	}
+
+	part := seatPart{body: body, sig: sig, rekeys: make(map[PlayerNumber][]byte)}
+	lines := strings.Split(string(body), "\n")
+	if lines[0] != seatVersion {
+		return seatPart{}, fmt.Errorf("unknown seat version: %s", lines[0])
+	}
+	seen := make(map[string]bool)
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (seen[fields[0]] && fields[0] != "rekey") {
+			return seatPart{}, invalid
+		}
+		seen[fields[0]] = true
+
+		switch fields[0] {
+		case "game":
+			part.gameID = fields[1]
+		case "seat":
+			if len(fields) < 3 || len(fields) > 4 {
+				return seatPart{}, invalid
+			}
+			number, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return seatPart{}, invalid
+			}
+			part.entry = RosterEntry{Player: PlayerNumber(number), Fingerprint: fields[2]}
+			if len(fields) == 4 {
+				part.entry.Name = fields[3]
+			}
+		case "cards":
+			if part.cards, err = ParseCardIDs(fields[1]); err != nil {
+				return seatPart{}, invalid
+			}
+		case "issuer":
+			if fields[1] == "dealer" {
+				continue
+			}
+			number, err := strconv.Atoi(fields[1])
+			if err != nil || number < 1 || len(fields) != 3 {
+				return seatPart{}, invalid
+			}
+			der, err := base64.RawStdEncoding.DecodeString(fields[2])
+			if err != nil {
+				return seatPart{}, invalid
+			}
+			if part.issuerPub, err = x509.ParsePKIXPublicKey(der); err != nil {
+				return seatPart{}, invalid
+			}
+			part.issuer = PlayerNumber(number)
+		case "rekey":
+			number, err := strconv.Atoi(fields[1])
+			if err != nil || number < 1 || len(fields) != 3 {
+				return seatPart{}, invalid
+			}
+			if part.rekeys[PlayerNumber(number)], err = base64.RawStdEncoding.DecodeString(fields[2]); err != nil {
+				return seatPart{}, invalid
+			}
+		case "share":
+			if part.share, err = base64.RawStdEncoding.DecodeString(fields[1]); err != nil {
+				return seatPart{}, invalid
+			}
+		default:
+			return seatPart{}, fmt.Errorf("unknown seat entry: %s", fields[0])
+		}
+	}
+	if !seen["game"] || !seen["seat"] || !seen["cards"] || !seen["issuer"] || !seen["share"] {
+		return seatPart{}, invalid
+	}
+	return part, nil
+}
+
+// readSeats reads the header of a deal file, and the seats added to the end of it, returning the header with the
+// seated players added to its roster (before the observers, who are renumbered after them). The signatures of parts
+// issued by players are checked, as they carry their own keys, but the dealer's aren't (see verifySeats).
+func readSeats(stanzas []string) (dealHeader, []seat, error) {
+	stacks := len(strings.Split(stanzas[2], "\n"))
+	header, err := parseHeader(stanzas[0], stacks)
+	if err != nil {
+		return dealHeader{}, nil, err
+	}
+	cardCount := len(strings.Split(stanzas[1], "\n"))
+	gameID := dealID(stanzas[3])
+
+	var seats []seat
+	for n, stanza := range stanzas[4:] {
+		if header.escrow > 0 {
+			return dealHeader{}, nil, fmt.Errorf("players can't be seated in games dealt with escrow")
+		}
+
+		var s seat
+		for _, line := range strings.Split(strings.TrimSpace(stanza), "\n") {
+			part, err := parseSeatPart(line)
+			if err != nil {
+				return dealHeader{}, nil, fmt.Errorf("seat %d is invalid: %w", n+1, err)
+			}
+			if err := header.checkSeatPart(part, gameID, cardCount); err != nil {
+				return dealHeader{}, nil, fmt.Errorf("seat %d is invalid: %w", n+1, err)
+			}
+			if len(s.parts) > 0 && (part.entry != s.entry || FormatCardIDs(part.cards) != FormatCardIDs(s.cards)) {
+				return dealHeader{}, nil, fmt.Errorf("seat %d is invalid: its parts seat different players, or re-key different cards", n+1)
+			}
+			s.entry, s.cards = part.entry, part.cards
+			s.parts = append(s.parts, part)
+		}
+		if err := header.checkSeatComplete(s); err != nil {
+			return dealHeader{}, nil, fmt.Errorf("seat %d is invalid: %w", n+1, err)
+		}
+
+		header.seat(s.entry)
+		seats = append(seats, s)
+	}
+	return header, seats, nil
+}
+
+// checkSeatPart checks that a seat part seats the next player in this game, and that it was issued by the dealer or
+// (with a valid signature) one of the players.
+func (h *dealHeader) checkSeatPart(part seatPart, gameID string, cardCount int) error {
+	if part.gameID != gameID {
+		return fmt.Errorf("it is for a different game")
+	}
+	if part.entry.Player != PlayerNumber(h.players+1) {
+		return fmt.Errorf("it seats player %d, but the next player is %d", part.entry.Player, h.players+1)
+	}
+	if err := checkCardIDs(part.cards, cardCount); err != nil {
+		return err
+	}
+	for _, entry := range h.roster {
+		if entry.Fingerprint == part.entry.Fingerprint {
+			return fmt.Errorf("it seats a key that is already in the game")
+		}
+		if part.entry.Name != "" && entry.Name == part.entry.Name {
+			return fmt.Errorf("more than one player is called %s", part.entry.Name)
+		}
+	}
+	if err := validateRoster([]Player{{Name: part.entry.Name}}); err != nil {
+		return err
+	}
+
+	if part.issuer == 0 {
+		if len(part.rekeys) != h.players {
+			return fmt.Errorf("the dealer's part doesn't re-key every player")
+		}
+		return nil
+	}
+
+	if int(part.issuer) > h.players || len(part.rekeys) != 1 || part.rekeys[part.issuer] == nil {
+		return fmt.Errorf("player %d's part is badly formed", part.issuer)
+	}
+	fingerprint, err := Fingerprint(part.issuerPub)
+	if err != nil || h.roster[part.issuer-1].Fingerprint == "" || fingerprint != h.roster[part.issuer-1].Fingerprint {
+		return fmt.Errorf("player %d's part was made with a key that isn't theirs", part.issuer)
+	}
+	if err := verifyDeal(part.issuerPub, part.body, part.sig); err != nil {
+		return fmt.Errorf("player %d's part has an invalid signature", part.issuer)
+	}
+	return nil
+}
+
+// checkSeatComplete checks that a seat has either the dealer's part, or exactly one part from every player.
+func (h *dealHeader) checkSeatComplete(s seat) error {
+	issuers := make(map[PlayerNumber]bool)
+	for _, part := range s.parts {
+		if issuers[part.issuer] {
+			return fmt.Errorf("it has more than one part from the same issuer")
+		}
+		issuers[part.issuer] = true
+	}
+
+	if issuers[0] {
+		if len(s.parts) > 1 {
+			return fmt.Errorf("it has parts from both the dealer and players")
+		}
+		return nil
+	}
+	for p := 1; p <= h.players; p++ {
+		if !issuers[PlayerNumber(p)] {
+			return fmt.Errorf("it is missing player %d's part", p)
+		}
+	}
+	return nil
+}
+
+// seat adds a seated player to the roster, after the other players, renumbering the observers after them.
+func (h *dealHeader) seat(entry RosterEntry) {
+	roster := append([]RosterEntry(nil), h.roster[:h.players]...)
+	roster = append(roster, entry)
+	for _, observer := range h.roster[h.players:] {
+		observer.Player++
+		roster = append(roster, observer)
+	}
+	h.roster = roster
+	h.players++
+}
+
+// verifySeats checks that every part of every seat issued by the dealer was signed by them.
+func verifySeats(seats []seat, dealerPub crypto.PublicKey) error {
+	for n, s := range seats {
+		for _, part := range s.parts {
+			if part.issuer != 0 {
+				continue
+			}
+			if err := verifyDeal(dealerPub, part.body, part.sig); err != nil {
+				return fmt.Errorf("seat %d was not issued by the specified dealer", n+1)
+			}
+		}
+	}
+	return nil
+}
+
+// takeSeat finds the seat made for the holder of the given private key, returning its index and their key stack, in
+// which the cards that had already left the deck have empty key shares.
+func takeSeat(seats []seat, prv crypto.Signer, cardCount int) (int, [][]byte, bool) {
+	for n, s := range seats {
+		var shares [][]byte
+		for _, part := range s.parts {
+			share, err := unseal(part.share, prv)
+			if err != nil || len(share) != len(s.cards)*aesCipherSize {
+				break
+			}
+			shares = append(shares, share)
+		}
+		if len(shares) != len(s.parts) {
+			continue
+		}
+
+		keys := make([][]byte, cardCount)
+		for cardID := range keys {
+			keys[cardID] = make([]byte, aesCipherSize)
+		}
+		combined := xor(shares...)
+		for i, cardID := range s.cards {
+			keys[cardID] = combined[i*aesCipherSize : (i+1)*aesCipherSize]
+		}
+		return n, keys, true
+	}
+	return 0, nil, false
+}
+
+// applySeat re-keys this player's key shares for the cards a seat re-keys, with the deltas it gives them.
+func (g *Game) applySeat(s seat, prv crypto.Signer) error {
+	for _, part := range s.parts {
+		sealed, ok := part.rekeys[g.playerNumber]
+		if !ok {
+			continue
+		}
+		deltas, err := unseal(sealed, prv)
+		if err != nil || len(deltas) != len(s.cards)*aesCipherSize {
+			return fmt.Errorf("could not read your re-keying for seating %s", g.PlayerName(s.entry.Player))
+		}
+		for i, cardID := range s.cards {
+			g.keys[cardID] = xor(g.keys[cardID], deltas[i*aesCipherSize:(i+1)*aesCipherSize])
+		}
+	}
+	return nil
+}
+
+// seatPlayers applies the seats in the deal file to this player's key stack and state. from is the index of the first
+// seat made after this player joined the game. The first time a seat is seen, each existing player checks that the
+// cards it re-keys are still in the deck, and the seated player marks the cards that had already left it.
+func (g *Game) seatPlayers(seats []seat, from int, prv crypto.Signer) error {
+	if g.role != RolePlayer {
+		return nil
+	}
+	if from > 0 && g.seated < from {
+		for cardID, owner := range g.state {
+			if owner == 0 && !containsCard(seats[from-1].cards, cardID) {
+				g.state[cardID] = notInDeck
+			}
+		}
+		g.seated = from
+	}
+
+	for n, s := range seats[from:] {
+		if from+n >= g.seated {
+			for _, cardID := range s.cards {
+				if owner := g.state[cardID]; owner != 0 {
+					return fmt.Errorf("seating %s re-keys card %d, which you've given to %s", g.PlayerName(s.entry.Player), cardID+1, g.PlayerName(owner))
+				}
+			}
+		}
+		if err := g.applySeat(s, prv); err != nil {
+			return err
+		}
+	}
+	g.seated = len(seats)
+	return nil
+}
+
+// containsCard reports whether a sorted list of card IDs holds the given card.
+func containsCard(cards []int, cardID int) bool {
+	i := sort.SearchInts(cards, cardID)
+	return i < len(cards) && cards[i] == cardID
+}
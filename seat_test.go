@@ -0,0 +1,184 @@
+package trustdraw
+
+import (
+	"bytes"
+	"crypto"
+	"strings"
+	"testing"
+)
+
+// seatTestGame is a game for two players, one of whom has drawn a card, with a third player waiting to be seated.
+type seatTestGame struct {
+	deal      []byte
+	dealerPrv crypto.Signer
+	prvs      []crypto.Signer
+	games     []*Game
+	newPlayer Player
+	drawn     string
+}
+
+func newSeatTestGame(t *testing.T) *seatTestGame {
+	t.Helper()
+	deal, prvs, dealerPrv := testDeal(t, 2, DealOptions{})
+	sg := &seatTestGame{deal: deal, dealerPrv: dealerPrv, prvs: append(prvs, testKey(t))}
+	for _, prv := range prvs {
+		sg.games = append(sg.games, openTestGame(t, deal, prv, ""))
+	}
+	sg.newPlayer = Player{Name: "carol", PublicKey: sg.prvs[2].Public()}
+	sg.drawn, _, _ = testDraw(t, sg.games, 2)
+	return sg
+}
+
+// extend adds the seat parts to the deal file, and reopens every player's game with it.
+func (sg *seatTestGame) extend(t *testing.T, parts ...string) []*Game {
+	t.Helper()
+	var extended bytes.Buffer
+	if err := ExtendDeal(bytes.NewReader(sg.deal), &extended, parts...); err != nil {
+		t.Fatalf("could not extend the deal: %v", err)
+	}
+	if _, players, err := VerifyDeal(bytes.NewReader(extended.Bytes()), sg.dealerPrv.Public()); err != nil || players != 3 {
+		t.Fatalf("the extended deal for %d players didn't verify: %v", players, err)
+	}
+	games := make([]*Game, 3)
+	for i, prv := range sg.prvs {
+		state := ""
+		if i < len(sg.games) {
+			state = sg.games[i].State()
+		}
+		games[i] = openTestGame(t, extended.Bytes(), prv, state)
+	}
+	return games
+}
+
+func TestSeating(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts func(t *testing.T, sg *seatTestGame) []string
+	}{
+		{"by the dealer", func(t *testing.T, sg *seatTestGame) []string {
+			part, err := SeatPlayer(bytes.NewReader(sg.deal), sg.dealerPrv, sg.newPlayer, sg.games[0].Undrawn(),
+				sg.prvs[0].Public(), sg.prvs[1].Public())
+			if err != nil {
+				t.Fatalf("could not seat: %v", err)
+			}
+			return []string{part}
+		}},
+		{"by the players", func(t *testing.T, sg *seatTestGame) []string {
+			var parts []string
+			for i, game := range sg.games {
+				part, err := game.OfferSeat(sg.newPlayer, sg.prvs[i])
+				if err != nil {
+					t.Fatalf("player %d could not offer a seat: %v", i+1, err)
+				}
+				parts = append(parts, part)
+			}
+			return parts
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sg := newSeatTestGame(t)
+			games := sg.extend(t, tt.parts(t, sg)...)
+
+			for _, game := range games {
+				if game.ID() != sg.games[0].ID() || game.Players != 3 {
+					t.Fatalf("got game %s for %d players, want %s for 3", game.ID(), game.Players, sg.games[0].ID())
+				}
+			}
+			if got := games[2].PlayerNumber(); got != 3 || games[0].PlayerName(3) != "carol" {
+				t.Errorf("the new player is %s (%d), want carol (3)", games[0].PlayerName(got), got)
+			}
+			if got, want := games[2].Remaining(), sg.games[0].Remaining(); got != want {
+				t.Errorf("the new player sees %d cards left, want %d", got, want)
+			}
+
+			// Every player can draw, which needs the re-keyed shares of the players already in the game.
+			for _, drawer := range []PlayerNumber{1, 2} {
+				if card, _, _ := testDraw(t, games, drawer); card == sg.drawn {
+					t.Errorf("player %d drew %s, which was already drawn", drawer, card)
+				}
+			}
+
+			// The new player's draws can be checked by the players already in the game.
+			card, allowKey, allowKeys := testDraw(t, games, 3)
+			if ok, err := games[0].VerifyDrawBy(3, card, allowKey, allowKeys[1]); err != nil || !ok {
+				t.Errorf("player 1 couldn't verify the new player's draw (%v)", err)
+			}
+		})
+	}
+}
+
+func TestSeatingTampering(t *testing.T) {
+	sg := newSeatTestGame(t)
+	pubs := []crypto.PublicKey{sg.prvs[0].Public(), sg.prvs[1].Public()}
+	dealerSeat, err := SeatPlayer(bytes.NewReader(sg.deal), sg.dealerPrv, sg.newPlayer, sg.games[0].Undrawn(), pubs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedSeat, err := SeatPlayer(bytes.NewReader(sg.deal), testKey(t), sg.newPlayer, sg.games[0].Undrawn(), pubs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	every := make([]int, len(testDeck))
+	for i := range every {
+		every[i] = i
+	}
+	drawnSeat, err := SeatPlayer(bytes.NewReader(sg.deal), sg.dealerPrv, sg.newPlayer, every, pubs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offer1, err := sg.games[0].OfferSeat(sg.newPlayer, sg.prvs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	offer2, err := sg.games[1].OfferSeat(sg.newPlayer, sg.prvs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherGame := newSeatTestGame(t)
+	otherOffer, err := otherGame.games[1].OfferSeat(sg.newPlayer, otherGame.prvs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing any one character of the signed seat must be caught.
+	lines := strings.Split(dealerSeat, "\n")
+	changed := []byte(lines[len(lines)/2])
+	if changed[0] == 'A' {
+		changed[0] = 'B'
+	} else {
+		changed[0] = 'A'
+	}
+	lines[len(lines)/2] = string(changed)
+	changedSeat := strings.Join(lines, "\n")
+
+	tests := []struct {
+		name  string
+		parts []string
+	}{
+		{"seat signed by someone other than the dealer", []string{forgedSeat}},
+		{"changed seat", []string{changedSeat}},
+		{"seat re-keying a drawn card", []string{drawnSeat}},
+		{"missing a player's part", []string{offer1}},
+		{"the same player's part twice", []string{offer1, offer1}},
+		{"a part from another game", []string{offer1, otherOffer}},
+		{"a dealer's seat and players' parts", []string{dealerSeat, offer1, offer2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var extended bytes.Buffer
+			if err := ExtendDeal(bytes.NewReader(sg.deal), &extended, tt.parts...); err != nil {
+				return
+			}
+			if _, _, err := VerifyDeal(bytes.NewReader(extended.Bytes()), sg.dealerPrv.Public()); err != nil {
+				return
+			}
+			for i, game := range sg.games {
+				if _, err := OpenGame(bytes.NewReader(extended.Bytes()), sg.prvs[i], game.State()); err != nil {
+					return
+				}
+			}
+			t.Errorf("every player accepted the seat")
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Package sidecar serves the TrustDraw library as a gRPC/Connect service (see proto/trustdraw/v1), so that
+// game servers written in any language can drive the protocol over localhost.
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/cards"
+	trustdrawv1 "github.com/jphastings/trustdraw/gen/trustdraw/v1"
+	"github.com/jphastings/trustdraw/gen/trustdraw/v1/trustdrawv1connect"
+	"github.com/jphastings/trustdraw/internal/cmdhelpers"
+)
+
+// Server holds open games in memory, keyed by game ID and player.
+type Server struct {
+	mu    sync.Mutex
+	games map[gameKey]*openGame
+}
+
+var _ trustdrawv1connect.TrustDrawServiceHandler = (*Server)(nil)
+
+type gameKey struct {
+	id     string
+	player trustdraw.PlayerNumber
+}
+
+// openGame is a game held by the server, along with its transcript and anyone subscribed to it.
+type openGame struct {
+	game        *trustdraw.Game
+	events      []*trustdrawv1.TranscriptEvent
+	subscribers map[chan *trustdrawv1.TranscriptEvent]struct{}
+}
+
+// NewServer creates a Server with no open games.
+func NewServer() *Server {
+	return &Server{games: make(map[gameKey]*openGame)}
+}
+
+// NewHandler creates a Server, returning the path to mount it on and its http.Handler.
+func NewHandler(opts ...connect.HandlerOption) (string, http.Handler) {
+	return trustdrawv1connect.NewTrustDrawServiceHandler(NewServer(), opts...)
+}
+
+func (s *Server) Deal(_ context.Context, req *connect.Request[trustdrawv1.DealRequest]) (*connect.Response[trustdrawv1.DealResponse], error) {
+	var dealCards []string
+	switch deck := req.Msg.Deck.(type) {
+	case *trustdrawv1.DealRequest_DeckName:
+		inBuilt, ok := cards.LoadInBuilt(deck.DeckName)
+		if !ok {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown deck: %s", deck.DeckName))
+		}
+		dealCards = inBuilt
+	case *trustdrawv1.DealRequest_Cards:
+		dealCards = append([]string(nil), deck.Cards.GetCards()...)
+	default:
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("a deck name or cards are needed"))
+	}
+
+	dealerPrv, err := cmdhelpers.ParseDealerPrivateKey([]byte(req.Msg.DealerPrivateKey), "dealer_private_key")
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	playerPubs := make([]crypto.PublicKey, len(req.Msg.PlayerPublicKeys))
+	for i, pemKey := range req.Msg.PlayerPublicKeys {
+		if playerPubs[i], err = cmdhelpers.ParsePlayerPublicKey([]byte(pemKey), fmt.Sprintf("player_public_keys[%d]", i)); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+
+	var deal bytes.Buffer
+	if err := trustdraw.Deal(&deal, dealCards, dealerPrv, playerPubs...); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	id, err := trustdraw.DealID(bytes.NewReader(deal.Bytes()))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&trustdrawv1.DealResponse{Deal: deal.String(), GameId: id}), nil
+}
+
+func (s *Server) VerifyDeal(_ context.Context, req *connect.Request[trustdrawv1.VerifyDealRequest]) (*connect.Response[trustdrawv1.VerifyDealResponse], error) {
+	dealerPub, err := cmdhelpers.ParseDealerPublicKey([]byte(req.Msg.DealerPublicKey), "dealer_public_key")
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	cardCount, players, err := trustdraw.VerifyDeal(strings.NewReader(req.Msg.Deal), dealerPub)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return connect.NewResponse(&trustdrawv1.VerifyDealResponse{Cards: int32(cardCount), Players: int32(players)}), nil
+}
+
+func (s *Server) OpenGame(_ context.Context, req *connect.Request[trustdrawv1.OpenGameRequest]) (*connect.Response[trustdrawv1.OpenGameResponse], error) {
+	playerPrv, err := cmdhelpers.ParsePlayerPrivateKey([]byte(req.Msg.PlayerPrivateKey), "player_private_key")
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	game, err := trustdraw.OpenGame(strings.NewReader(req.Msg.Deal), playerPrv, req.Msg.State)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	key := gameKey{id: game.ID(), player: game.PlayerNumber()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.games[key]; ok {
+		return nil, connect.NewError(connect.CodeAlreadyExists, fmt.Errorf("game %s is already open for player %d", key.id, key.player))
+	}
+
+	open := &openGame{game: game, subscribers: make(map[chan *trustdrawv1.TranscriptEvent]struct{})}
+	s.games[key] = open
+	open.record(&trustdrawv1.TranscriptEvent{Kind: trustdrawv1.TranscriptEvent_KIND_OPENED})
+
+	return connect.NewResponse(&trustdrawv1.OpenGameResponse{
+		Game:    &trustdrawv1.GameRef{GameId: key.id, Player: int32(key.player)},
+		Players: int32(game.Players),
+	}), nil
+}
+
+func (s *Server) CloseGame(_ context.Context, req *connect.Request[trustdrawv1.CloseGameRequest]) (*connect.Response[trustdrawv1.CloseGameResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	open.record(&trustdrawv1.TranscriptEvent{Kind: trustdrawv1.TranscriptEvent_KIND_CLOSED})
+	for sub := range open.subscribers {
+		close(sub)
+	}
+	delete(s.games, key)
+
+	return connect.NewResponse(&trustdrawv1.CloseGameResponse{}), nil
+}
+
+func (s *Server) AllowDraw(_ context.Context, req *connect.Request[trustdrawv1.AllowDrawRequest]) (*connect.Response[trustdrawv1.AllowDrawResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	allowKey, err := open.game.AllowDraw(trustdraw.PlayerNumber(req.Msg.Player))
+	if errors.Is(err, trustdraw.ErrNoCardsLeft) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	} else if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	open.record(&trustdrawv1.TranscriptEvent{Kind: trustdrawv1.TranscriptEvent_KIND_ALLOWED_DRAW, Player: req.Msg.Player})
+	return connect.NewResponse(&trustdrawv1.AllowDrawResponse{AllowKey: allowKey}), nil
+}
+
+func (s *Server) Draw(_ context.Context, req *connect.Request[trustdrawv1.DrawRequest]) (*connect.Response[trustdrawv1.DrawResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	card, allowKey, alreadyDrawn, err := open.game.Draw(req.Msg.AllowKeys...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	open.record(&trustdrawv1.TranscriptEvent{Kind: trustdrawv1.TranscriptEvent_KIND_DREW, Player: int32(key.player)})
+	return connect.NewResponse(&trustdrawv1.DrawResponse{Card: card, AllowKey: allowKey, AlreadyDrawn: alreadyDrawn}), nil
+}
+
+func (s *Server) VerifyDraw(_ context.Context, req *connect.Request[trustdrawv1.VerifyDrawRequest]) (*connect.Response[trustdrawv1.VerifyDrawResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a player, the card is checked as drawn by whoever this player's state records it as given to.
+	player := trustdraw.PlayerNumber(req.Msg.Player)
+	if player == 0 {
+		player = open.game.GivenTo(req.Msg.AllowKeys...)
+	}
+	var valid bool
+	if player != 0 {
+		valid, err = open.game.VerifyDrawBy(player, req.Msg.Card, req.Msg.AllowKeys...)
+	} else {
+		valid, err = open.game.VerifyDraw(req.Msg.Card, req.Msg.AllowKeys...)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	open.record(&trustdrawv1.TranscriptEvent{
+		Kind:   trustdrawv1.TranscriptEvent_KIND_VERIFIED_DRAW,
+		Player: int32(player),
+		Card:   req.Msg.Card,
+		Valid:  valid,
+	})
+	return connect.NewResponse(&trustdrawv1.VerifyDrawResponse{Valid: valid}), nil
+}
+
+func (s *Server) GetState(_ context.Context, req *connect.Request[trustdrawv1.GetStateRequest]) (*connect.Response[trustdrawv1.GetStateResponse], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := open.game.Holdings()
+	res := &trustdrawv1.GetStateResponse{
+		State:     open.game.State(),
+		Remaining: int32(holdings[0]),
+		Holdings:  make([]int32, open.game.Players),
+	}
+	for p := range res.Holdings {
+		res.Holdings[p] = int32(holdings[trustdraw.PlayerNumber(p+1)])
+	}
+
+	return connect.NewResponse(res), nil
+}
+
+func (s *Server) Subscribe(ctx context.Context, req *connect.Request[trustdrawv1.SubscribeRequest], stream *connect.ServerStream[trustdrawv1.TranscriptEvent]) error {
+	s.mu.Lock()
+	_, open, err := s.lookup(req.Msg.Game)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+
+	// Buffer enough for the events so far, so they can be queued without holding up other requests.
+	sub := make(chan *trustdrawv1.TranscriptEvent, len(open.events)+64)
+	for _, event := range open.events {
+		sub <- event
+	}
+	open.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(open.subscribers, sub)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-sub:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lookup finds an open game. The caller must hold s.mu.
+func (s *Server) lookup(ref *trustdrawv1.GameRef) (gameKey, *openGame, error) {
+	if ref == nil {
+		return gameKey{}, nil, connect.NewError(connect.CodeInvalidArgument, errors.New("a game is needed"))
+	}
+
+	key := gameKey{id: ref.GameId, player: trustdraw.PlayerNumber(ref.Player)}
+	open, ok := s.games[key]
+	if !ok {
+		return key, nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("game %s is not open for player %d", key.id, key.player))
+	}
+	return key, open, nil
+}
+
+// record adds an event to the game's transcript and sends it to subscribers. The caller must hold s.mu.
+// Subscribers that have fallen too far behind are dropped, rather than holding up the game.
+func (g *openGame) record(event *trustdrawv1.TranscriptEvent) {
+	event.Seq = int64(len(g.events) + 1)
+	g.events = append(g.events, event)
+
+	for sub := range g.subscribers {
+		select {
+		case sub <- event:
+		default:
+			close(sub)
+			delete(g.subscribers, sub)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package statestore
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+)
+
+// EncryptedHeader is the first line of an encrypted state.
+const EncryptedHeader = "TrustDraw-State/v1"
+
+// ErrNotEncrypted is returned when an encrypted store holds a state that isn't encrypted, as state files made
+// before encryption was added do.
+var ErrNotEncrypted = errors.New("the game state is not encrypted (older state files can be brought in with `trustdraw state import`)")
+
+// Encrypted wraps another store so the state it holds is encrypted and authenticated (with AES-256-GCM) under a key
+// derived from the player's private key, and bound to one game. Any tampering is detected when the state is loaded.
+//
+// An encrypted state looks like:
+//
+//	TrustDraw-State/v1
+//	game <game ID>
+//	<base64 nonce & ciphertext>
+type Encrypted struct {
+	inner  trustdraw.StateStore
+	aead   cipher.AEAD
+	gameID string
+}
+
+var _ trustdraw.StateStore = (*Encrypted)(nil)
+
+// NewEncrypted wraps the inner store, encrypting the state of the given game with a key derived from playerPrv.
+// The key is derived from a (deterministic, PKCS #1 v1.5) signature, so keys held by an agent can be used too.
+func NewEncrypted(inner trustdraw.StateStore, playerPrv crypto.Signer, gameID string) (*Encrypted, error) {
+	key, err := stateKey(playerPrv)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive the state key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encrypted{inner: inner, aead: aead, gameID: gameID}, nil
+}
+
+func (e *Encrypted) Load() (string, error) {
+	sealed, err := e.inner.Load()
+	if err != nil {
+		return "", err
+	}
+	return e.Open(sealed)
+}
+
+func (e *Encrypted) Save(state string) error {
+	sealed, err := e.Seal(state)
+	if err != nil {
+		return err
+	}
+	return e.inner.Save(sealed)
+}
+
+func (e *Encrypted) CompareAndSwap(old, new string) error {
+	current, err := e.inner.Load()
+	if err != nil {
+		return err
+	}
+	state, err := e.Open(current)
+	if err != nil {
+		return err
+	}
+	if !sameState(state, old) {
+		return trustdraw.ErrStateChanged
+	}
+
+	sealed, err := e.Seal(new)
+	if err != nil {
+		return err
+	}
+	// The inner store checks the stored state is still the one we decrypted, so a change in between isn't lost.
+	return e.inner.CompareAndSwap(current, sealed)
+}
+
+// Seal encrypts a state so that only this player can open it, for this game.
+func (e *Encrypted) Seal(state string) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := e.aead.Seal(nonce, nonce, []byte(state), []byte(e.gameID))
+
+	return strings.Join([]string{
+		EncryptedHeader,
+		"game " + e.gameID,
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, "\n"), nil
+}
+
+// Open decrypts a state made with Seal, checking it hasn't been tampered with. An empty state opens as empty.
+func (e *Encrypted) Open(sealed string) (string, error) {
+	if strings.TrimSpace(sealed) == "" {
+		return "", nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(sealed), "\n")
+	if lines[0] != EncryptedHeader {
+		return "", ErrNotEncrypted
+	}
+	if len(lines) != 3 {
+		return "", fmt.Errorf("the encrypted game state is badly formed")
+	}
+	if gameID := strings.TrimPrefix(lines[1], "game "); gameID != e.gameID {
+		return "", fmt.Errorf("the game state is for a different game (%s)", gameID)
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(lines[2])
+	if err != nil || len(data) < e.aead.NonceSize() {
+		return "", fmt.Errorf("the encrypted game state is badly formed")
+	}
+	nonce, ciphertext := data[:e.aead.NonceSize()], data[e.aead.NonceSize():]
+
+	state, err := e.aead.Open(nil, nonce, ciphertext, []byte(e.gameID))
+	if err != nil {
+		return "", fmt.Errorf("the game state has been tampered with, or is for a different player")
+	}
+	return string(state), nil
+}
+
+// stateKey derives a 256-bit key from the player's private key, by hashing its signature of a fixed message.
+// Both PKCS #1 v1.5 (RSA) and Ed25519 signatures are deterministic, so the key is the same every time.
+func stateKey(playerPrv crypto.Signer) ([]byte, error) {
+	digest := sha256.Sum256([]byte("TrustDraw state encryption key"))
+	var opts crypto.SignerOpts = crypto.SHA256
+	if _, ok := playerPrv.Public().(ed25519.PublicKey); ok {
+		opts = crypto.Hash(0)
+	}
+	signature, err := playerPrv.Sign(rand.Reader, digest[:], opts)
+	if err != nil {
+		return nil, err
+	}
+
+	key := sha256.Sum256(signature)
+	return key[:], nil
+}
@@ -0,0 +1,141 @@
+package statestore_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/statestore"
+)
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	deal, prvs := testDeal(t)
+	gameID, err := trustdraw.DealID(bytes.NewReader(deal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := statestore.NewMemory("")
+	store, err := statestore.NewEncrypted(inner, prvs[0], gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game, err := trustdraw.OpenGameWithStore(bytes.NewReader(deal), prvs[0], store)
+	if err != nil {
+		t.Fatalf("could not open game: %v", err)
+	}
+	if _, err := game.AllowDraw(2); err != nil {
+		t.Fatalf("could not allow a draw: %v", err)
+	}
+
+	sealed, _ := inner.Load()
+	if !strings.HasPrefix(sealed, statestore.EncryptedHeader) || strings.Contains(sealed, game.State()) {
+		t.Errorf("the stored state isn't encrypted: %q", sealed)
+	}
+
+	// The same key, even loaded again, opens the state it saved.
+	again, err := statestore.NewEncrypted(inner, prvs[0], gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := trustdraw.OpenGameWithStore(bytes.NewReader(deal), prvs[0], again)
+	if err != nil {
+		t.Fatalf("could not reopen game: %v", err)
+	}
+	if reopened.State() != game.State() {
+		t.Errorf("got state %q, want %q", reopened.State(), game.State())
+	}
+
+	// Exporting and importing (see trustdraw state) round trips too.
+	exported, err := store.Seal(game.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported, err := again.Open(exported); err != nil || imported != game.State() {
+		t.Errorf("got imported state %q (%v), want %q", imported, err, game.State())
+	}
+}
+
+func TestEncryptedTampering(t *testing.T) {
+	deal, prvs := testDeal(t)
+	gameID, err := trustdraw.DealID(bytes.NewReader(deal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := statestore.NewEncrypted(statestore.NewMemory(""), prvs[0], gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := store.Seal("drawn 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(sealed, "\n")
+
+	otherPlayer, err := statestore.NewEncrypted(statestore.NewMemory(""), prvs[1], gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherGame, err := statestore.NewEncrypted(statestore.NewMemory(""), prvs[0], "another-game")
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := []byte(lines[2])
+	flipped[len(flipped)/2] ^= 'A' ^ 'B'
+
+	tests := []struct {
+		name    string
+		store   *statestore.Encrypted
+		sealed  string
+		wantErr error
+	}{
+		{"changed ciphertext", store, strings.Join([]string{lines[0], lines[1], string(flipped)}, "\n"), nil},
+		{"truncated ciphertext", store, strings.Join([]string{lines[0], lines[1], lines[2][:8]}, "\n"), nil},
+		{"changed game", store, strings.Join([]string{lines[0], "game another-game", lines[2]}, "\n"), nil},
+		{"missing line", store, strings.Join(lines[:2], "\n"), nil},
+		{"another player's key", otherPlayer, sealed, nil},
+		{"another game's state", otherGame, sealed, nil},
+		{"plaintext state", store, "drawn 1", statestore.ErrNotEncrypted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := tt.store.Open(tt.sealed)
+			if err == nil {
+				t.Fatalf("a tampered state was opened as %q", state)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncryptedRefusesToOverwriteTamperedState(t *testing.T) {
+	deal, prvs := testDeal(t)
+	gameID, err := trustdraw.DealID(bytes.NewReader(deal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := statestore.NewMemory("")
+	store, err := statestore.NewEncrypted(inner, prvs[0], gameID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("drawn 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, _ := inner.Load()
+	tampered := strings.Replace(sealed, "game "+gameID, "game "+gameID+"x", 1)
+	if err := inner.Save(tampered); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CompareAndSwap("drawn 1", "drawn 1,2"); err == nil {
+		t.Errorf("a tampered state was replaced")
+	}
+	if got, _ := inner.Load(); got != tampered {
+		t.Errorf("the tampered state was changed")
+	}
+}
@@ -0,0 +1,96 @@
+// Package statestore holds implementations of trustdraw.StateStore, for keeping game state in files, memory or SQLite.
+package statestore
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+)
+
+// File holds a game's state in a file, as the CLI does.
+//
+// Writes are atomic (the new state is written to a temporary file which replaces the old one) and are made while
+// holding a lock on a ".lock" file beside it, so two processes playing the same game can't corrupt it.
+type File struct {
+	path string
+}
+
+var _ trustdraw.StateStore = (*File)(nil)
+
+// NewFile creates a store for the state file at path. The file doesn't need to exist yet.
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Path returns the path of the state file.
+func (f *File) Path() string {
+	return f.path
+}
+
+func (f *File) Load() (string, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+func (f *File) Save(state string) error {
+	unlock, err := lockFile(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return f.write(state)
+}
+
+func (f *File) CompareAndSwap(old, new string) error {
+	unlock, err := lockFile(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := f.Load()
+	if err != nil {
+		return err
+	}
+	if !sameState(current, old) {
+		return trustdraw.ErrStateChanged
+	}
+
+	return f.write(new)
+}
+
+// write replaces the state file atomically, so an interrupted write can't corrupt the game.
+func (f *File) write(state string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(state); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// sameState compares two states, ignoring surrounding whitespace (which a person editing a state file might add).
+func sameState(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
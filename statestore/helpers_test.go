@@ -0,0 +1,47 @@
+package statestore_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/jphastings/trustdraw"
+)
+
+func testKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	_, prv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prv
+}
+
+// testDeal deals a small deck to two players, returning the deal file and the players' private keys.
+func testDeal(t *testing.T) ([]byte, []crypto.Signer) {
+	t.Helper()
+	prvs := []crypto.Signer{testKey(t), testKey(t)}
+	var deal bytes.Buffer
+	if err := trustdraw.Deal(&deal, []string{"A♠️", "2♠️", "3♠️", "4♠️"}, testKey(t), prvs[0].Public(), prvs[1].Public()); err != nil {
+		t.Fatalf("could not deal: %v", err)
+	}
+	return deal.Bytes(), prvs
+}
+
+// openStoredTestGames deals a small deck to two players, opening the first player's game with the given store, and the
+// second's without one.
+func openStoredTestGames(t *testing.T, store trustdraw.StateStore) ([]*trustdraw.Game, []byte) {
+	t.Helper()
+	deal, prvs := testDeal(t)
+	first, err := trustdraw.OpenGameWithStore(bytes.NewReader(deal), prvs[0], store)
+	if err != nil {
+		t.Fatalf("could not open game: %v", err)
+	}
+	second, err := trustdraw.OpenGame(bytes.NewReader(deal), prvs[1], "")
+	if err != nil {
+		t.Fatalf("could not open game: %v", err)
+	}
+	return []*trustdraw.Game{first, second}, deal
+}
@@ -0,0 +1,34 @@
+//go:build !unix
+
+package statestore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// lockTimeout is how long to wait for another process to release a lock before giving up.
+const lockTimeout = 10 * time.Second
+
+// lockFile takes an exclusive lock by creating the file at path, waiting for any other holder to remove it.
+// Without flock a crashed process can leave the lock behind, in which case it must be deleted by hand.
+func lockFile(path string) (unlock func(), err error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the lock file (%s) is still held, delete it if no other game is running", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,44 @@
+package statestore
+
+import (
+	"sync"
+
+	"github.com/jphastings/trustdraw"
+)
+
+// Memory holds a game's state in memory, for games that don't need to outlive the process (or that are saved some other way).
+type Memory struct {
+	mu    sync.Mutex
+	state string
+}
+
+var _ trustdraw.StateStore = (*Memory)(nil)
+
+// NewMemory creates a store holding the given state, which can be empty for a new game.
+func NewMemory(state string) *Memory {
+	return &Memory{state: state}
+}
+
+func (m *Memory) Load() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state, nil
+}
+
+func (m *Memory) Save(state string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = state
+	return nil
+}
+
+func (m *Memory) CompareAndSwap(old, new string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !sameState(m.state, old) {
+		return trustdraw.ErrStateChanged
+	}
+	m.state = new
+	return nil
+}
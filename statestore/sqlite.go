@@ -0,0 +1,78 @@
+package statestore
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jphastings/trustdraw"
+)
+
+// SQLite holds game states in the trustdraw_states table of a SQLite database, one row per name.
+//
+// Any SQLite driver can be used (like github.com/mattn/go-sqlite3 or modernc.org/sqlite), so none is imported here.
+type SQLite struct {
+	db   *sql.DB
+	name string
+}
+
+var _ trustdraw.StateStore = (*SQLite)(nil)
+
+// whitespace is the whitespace SQLite's trim removes when comparing states.
+const whitespace = " \t\r\n"
+
+// NewSQLite creates a store for the state with the given name (which should identify both the game and the player,
+// eg. the game ID and player number), creating the table if it doesn't exist yet.
+func NewSQLite(db *sql.DB, name string) (*SQLite, error) {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS trustdraw_states (
+		name TEXT PRIMARY KEY,
+		state TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("could not create the trustdraw_states table: %w", err)
+	}
+
+	return &SQLite{db: db, name: name}, nil
+}
+
+func (s *SQLite) Load() (string, error) {
+	var state string
+	err := s.db.QueryRow(`SELECT state FROM trustdraw_states WHERE name = ?`, s.name).Scan(&state)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return state, err
+}
+
+func (s *SQLite) Save(state string) error {
+	_, err := s.db.Exec(`INSERT INTO trustdraw_states (name, state) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET state = excluded.state`, s.name, state)
+	return err
+}
+
+func (s *SQLite) CompareAndSwap(old, new string) error {
+	var result sql.Result
+	var err error
+	// States are compared ignoring surrounding whitespace, as sameState does.
+	if sameState(old, "") {
+		// There may be no row yet, so insert one; the update only happens if the stored state is also empty.
+		result, err = s.db.Exec(`INSERT INTO trustdraw_states (name, state) VALUES (?, ?)
+			ON CONFLICT (name) DO UPDATE SET state = excluded.state WHERE trim(trustdraw_states.state, ?) = ''`,
+			s.name, new, whitespace)
+	} else {
+		result, err = s.db.Exec(`UPDATE trustdraw_states SET state = ? WHERE name = ? AND trim(state, ?) = ?`,
+			new, s.name, whitespace, strings.TrimSpace(old))
+	}
+	if err != nil {
+		return err
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		return trustdraw.ErrStateChanged
+	}
+	return nil
+}
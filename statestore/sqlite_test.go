@@ -0,0 +1,36 @@
+//go:build cgo
+
+package statestore_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/statestore"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The SQLite driver needs cgo, so the SQLite store is only tested where it's available.
+func init() {
+	testStores = append(testStores, struct {
+		name string
+		new  func(t *testing.T) trustdraw.StateStore
+	}{"sqlite", newTestSQLite})
+}
+
+func newTestSQLite(t *testing.T) trustdraw.StateStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "states.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store, err := statestore.NewSQLite(db, "game-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
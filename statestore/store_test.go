@@ -0,0 +1,120 @@
+package statestore_test
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jphastings/trustdraw"
+	"github.com/jphastings/trustdraw/statestore"
+)
+
+// testStores are the stores every StateStore test is run against, each made empty.
+var testStores = []struct {
+	name string
+	new  func(t *testing.T) trustdraw.StateStore
+}{
+	{"memory", func(t *testing.T) trustdraw.StateStore { return statestore.NewMemory("") }},
+	{"file", func(t *testing.T) trustdraw.StateStore {
+		return statestore.NewFile(filepath.Join(t.TempDir(), "game.state"))
+	}},
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  string
+		old     string
+		new     string
+		wantErr error
+		want    string
+	}{
+		{"first save", "", "", "drawn 1", nil, "drawn 1"},
+		{"matching state", "drawn 1", "drawn 1", "drawn 1,2", nil, "drawn 1,2"},
+		{"matching state, ignoring whitespace", "drawn 1\n", "drawn 1", "drawn 1,2", nil, "drawn 1,2"},
+		{"changed state", "drawn 1,3", "drawn 1", "drawn 1,2", trustdraw.ErrStateChanged, "drawn 1,3"},
+		{"state saved since it was empty", "drawn 3", "", "drawn 1", trustdraw.ErrStateChanged, "drawn 3"},
+	}
+	for _, store := range testStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+				s := store.new(t)
+				if tt.stored != "" {
+					if err := s.Save(tt.stored); err != nil {
+						t.Fatalf("could not save: %v", err)
+					}
+				}
+
+				if err := s.CompareAndSwap(tt.old, tt.new); !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				if got, err := s.Load(); err != nil || got != tt.want {
+					t.Errorf("got state %q (%v), want %q", got, err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestCompareAndSwapRace(t *testing.T) {
+	for _, store := range testStores {
+		t.Run(store.name, func(t *testing.T) {
+			s := store.new(t)
+			if err := s.Save("drawn 1"); err != nil {
+				t.Fatalf("could not save: %v", err)
+			}
+
+			// Only one of many changes made from the same state can win.
+			const racers = 10
+			var wg sync.WaitGroup
+			errs := make(chan error, racers)
+			for i := 0; i < racers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs <- s.CompareAndSwap("drawn 1", "drawn 1,"+string(rune('2'+i)))
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+
+			won := 0
+			for err := range errs {
+				switch {
+				case err == nil:
+					won++
+				case !errors.Is(err, trustdraw.ErrStateChanged):
+					t.Errorf("got error %v", err)
+				}
+			}
+			if won != 1 {
+				t.Errorf("%d changes were saved, want 1", won)
+			}
+		})
+	}
+}
+
+func TestGameUndoesChangesWhenStateChanged(t *testing.T) {
+	for _, store := range testStores {
+		t.Run(store.name, func(t *testing.T) {
+			s := store.new(t)
+			games, _ := openStoredTestGames(t, s)
+			if _, err := games[0].AllowDraw(2); err != nil {
+				t.Fatalf("could not allow a draw: %v", err)
+			}
+
+			// Something else (like another process) changes the state behind the game's back.
+			if err := s.Save("drawn 3"); err != nil {
+				t.Fatal(err)
+			}
+			remaining := games[0].Remaining()
+			if _, err := games[0].AllowDraw(2); !errors.Is(err, trustdraw.ErrStateChanged) {
+				t.Errorf("got error %v, want %v", err, trustdraw.ErrStateChanged)
+			}
+			if got := games[0].Remaining(); got != remaining {
+				t.Errorf("%d cards remain, want the draw undone (%d)", got, remaining)
+			}
+		})
+	}
+}
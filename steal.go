@@ -0,0 +1,479 @@
+package trustdraw
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// A player can steal a random card from another player's hand, without seeing the rest of it, and without the victim
+// being able to choose which card they lose. Everyone knows which cards each player holds, but not what they are.
+//
+// The victim puts their hand in a secret order, and shares a commitment to it (see Game.OfferHand). The thief picks a
+// position in it (see Game.PickCard), which can't favour any card as they don't know the order. The victim then
+// reveals the order, and hands the card at that position to the thief by giving them every player's allowKey for it
+// (see Game.HandOver and Game.TakeCard). The other players check the order was of the victim's hand, and record the
+// card as the thief's (see Game.RecordSteal). The victim couldn't change the order after seeing the pick, so they
+// couldn't choose the card.
+//
+// Offers, picks and reveals can be sent in turn messages, so the steal is recorded in a relay's transcript, where
+// anyone can check it with VerifySteal.
+
+const (
+	// stealNonceSize is the size of the random value that keeps the victim's order secret until it's revealed.
+	stealNonceSize = 32
+	// stealOfferSize and stealPickSize are the decoded sizes of a steal offer, and of a pick (which includes the offer).
+	stealOfferSize = 4 + 1 + 1 + 2 + sha256.Size
+	stealPickSize  = stealOfferSize + 2
+	// stealRevealHeaderSize is the decoded size of a steal reveal, before its list of card IDs.
+	stealRevealHeaderSize = 4 + 1 + stealNonceSize
+)
+
+// Steal is a card taken at random from one player's hand by another.
+type Steal struct {
+	// Number counts the steals made in this game, starting at 1.
+	Number int
+	Victim PlayerNumber
+	Thief  PlayerNumber
+	// Hand lists the IDs of the cards in the victim's hand, in the order they put it in.
+	Hand []int
+	// Index is the position in Hand the thief picked, starting at 1.
+	Index int
+}
+
+// CardID returns the ID of the card that was stolen.
+func (s Steal) CardID() int {
+	return s.Hand[s.Index-1]
+}
+
+// pendingOffer is a victim's offer of their hand that the thief hasn't taken from yet.
+type pendingOffer struct {
+	thief PlayerNumber
+	nonce []byte
+	hand  []int
+}
+
+// OfferHand starts the theft of a random card from this player's hand, by the given player. It puts the hand in a
+// secret order, and returns the commitment to it that the thief needs to pick a card. Calling it again before the card
+// is handed over returns the same offer, unless this player's hand has changed.
+func (g *Game) OfferHand(thief PlayerNumber) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	if err := g.checkThief(thief, g.playerNumber); err != nil {
+		return "", err
+	}
+	if g.offered != nil && g.offered.thief != thief {
+		return "", fmt.Errorf("%s is already stealing from you", g.PlayerName(g.offered.thief))
+	}
+
+	var hand []int
+	for cardID, owner := range g.state {
+		if owner == g.playerNumber {
+			hand = append(hand, cardID)
+		}
+	}
+	if len(hand) == 0 {
+		return "", fmt.Errorf("you have no cards to steal")
+	}
+	if g.offered != nil && g.checkHand(g.playerNumber, g.offered.hand) == nil {
+		return g.stealOffer(g.offered), nil
+	}
+
+	nonce := make([]byte, stealNonceSize)
+	if _, err := crand.Read(nonce); err != nil {
+		return "", fmt.Errorf("unable to offer your hand: %w", err)
+	}
+	for i := len(hand) - 1; i > 0; i-- {
+		j, err := crand.Int(crand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("unable to offer your hand: %w", err)
+		}
+		hand[i], hand[j.Int64()] = hand[j.Int64()], hand[i]
+	}
+
+	offered := g.offered
+	g.offered = &pendingOffer{thief: thief, nonce: nonce, hand: hand}
+	if err := g.Save(); err != nil {
+		g.offered = offered
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return g.stealOffer(g.offered), nil
+}
+
+// PickCard picks the card at the given position (from 1) in the hand the victim offered this player, or a random one
+// if the position is 0, returning the pick to give to the victim. Calling it again for the same offer returns the
+// same pick.
+func (g *Game) PickCard(offer string, index int) (string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", err
+	}
+	number, victim, thief, size, _, err := parseStealOffer(offer)
+	if err != nil {
+		return "", err
+	}
+	if thief != g.playerNumber {
+		return "", fmt.Errorf("the offer is for %s to steal from", g.PlayerName(thief))
+	}
+	if err := g.checkThief(thief, victim); err != nil {
+		return "", err
+	}
+	if number != g.stolen+1 {
+		return "", fmt.Errorf("the offer is for steal %d, but this is steal %d", number, g.stolen+1)
+	}
+	if pickedFrom, _, err := parseStealPick(g.picked); err == nil && pickedFrom == offer {
+		return g.picked, nil
+	}
+
+	held, unknown := 0, 0
+	for _, owner := range g.state {
+		switch owner {
+		case victim:
+			held++
+		case notInDeck:
+			unknown++
+		}
+	}
+	if size < held || size > held+unknown {
+		return "", fmt.Errorf("%s offered %d cards, but holds %d", g.PlayerName(victim), size, held)
+	}
+
+	if index == 0 {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(size)))
+		if err != nil {
+			return "", fmt.Errorf("unable to pick a card: %w", err)
+		}
+		index = int(n.Int64()) + 1
+	}
+	if index < 1 || index > size {
+		return "", fmt.Errorf("pick a card from 1 to %d", size)
+	}
+
+	pick, _ := base64.RawStdEncoding.DecodeString(offer)
+	pick = binary.LittleEndian.AppendUint16(pick, uint16(index))
+	picked := g.picked
+	g.picked = base64.RawStdEncoding.EncodeToString(pick)
+	if err := g.Save(); err != nil {
+		g.picked = picked
+		return "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return g.picked, nil
+}
+
+// HandOver hands the card the thief picked from this player's offered hand to them. It returns the reveal of the
+// hand's order, to share with every player, and every player's allowKey for the card, to give to the thief only.
+func (g *Game) HandOver(pick string) (string, []string, error) {
+	if err := g.canPlay(); err != nil {
+		return "", nil, err
+	}
+	if g.offered == nil {
+		return "", nil, fmt.Errorf("you haven't offered your hand")
+	}
+	if offer, _, err := parseStealPick(pick); err != nil || offer != g.stealOffer(g.offered) {
+		return "", nil, fmt.Errorf("the pick isn't for the hand you offered")
+	}
+	if err := g.checkHand(g.playerNumber, g.offered.hand); err != nil {
+		return "", nil, fmt.Errorf("your hand has changed since you offered it, offer it again")
+	}
+	reveal := g.stealReveal(g.offered)
+	steal, err := VerifySteal(g.id, pick, reveal)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cardID := steal.CardID()
+	allowKeys := append([]string{toAllowKey(cardID, g.keys[cardID])}, g.drawn[cardID]...)
+	offered := g.offered
+	g.state[cardID] = steal.Thief
+	g.offered = nil
+	g.stolen++
+	if err := g.Save(); err != nil {
+		g.state[cardID] = g.playerNumber
+		g.offered = offered
+		g.stolen--
+		return "", nil, fmt.Errorf("could not save game state: %w", err)
+	}
+	return reveal, allowKeys, nil
+}
+
+// TakeCard checks the victim's reveal against the card this player picked, and uses the allowKeys the victim handed
+// over to take the card into this player's hand.
+func (g *Game) TakeCard(reveal string, allowKeys ...string) (card string, allowKey string, error error) {
+	if err := g.canPlay(); err != nil {
+		return "", "", err
+	}
+	if g.picked == "" {
+		return "", "", fmt.Errorf("you haven't picked a card to steal")
+	}
+	steal, err := g.checkSteal(g.picked, reveal)
+	if err != nil {
+		return "", "", err
+	}
+
+	cardID := steal.CardID()
+	if given, err := allowKeysCardID(allowKeys); err != nil || given != cardID {
+		return "", "", fmt.Errorf("the allowKeys aren't for the card you picked")
+	}
+	// The victim gives every player's allowKey for the card, including this player's own, which isn't kept.
+	own := toAllowKey(cardID, g.keys[cardID])
+	var others []string
+	for _, allowKey := range g.withRecovered(allowKeys) {
+		if allowKey != own {
+			others = append(others, allowKey)
+		}
+	}
+	_, cardKey, err := g.allowKeysToCardKey(others)
+	if err != nil {
+		return "", "", fmt.Errorf("could not re-create card key: %w", err)
+	}
+	card, err = g.decryptCard(cardID, cardKey)
+	if err != nil {
+		return "", "", fmt.Errorf("could not decrypt card: %w", err)
+	}
+
+	owner, picked := g.state[cardID], g.picked
+	drawn, wasDrawn := g.drawn[cardID]
+	g.state[cardID] = g.playerNumber
+	g.drawn[cardID] = others
+	g.picked = ""
+	g.stolen++
+	if err := g.Save(); err != nil {
+		g.state[cardID] = owner
+		if wasDrawn {
+			g.drawn[cardID] = drawn
+		} else {
+			delete(g.drawn, cardID)
+		}
+		g.picked = picked
+		g.stolen--
+		return "", "", fmt.Errorf("could not save game state: %w", err)
+	}
+	return card, own, nil
+}
+
+// RecordSteal checks a steal between two other players, and records the stolen card as the thief's.
+func (g *Game) RecordSteal(pick, reveal string) (Steal, error) {
+	steal, err := g.checkSteal(pick, reveal)
+	if err != nil {
+		return Steal{}, err
+	}
+	if g.playerNumber == steal.Victim || g.playerNumber == steal.Thief {
+		return Steal{}, fmt.Errorf("you took part in this steal, so it's already recorded")
+	}
+
+	cardID := steal.CardID()
+	owner := g.state[cardID]
+	g.state[cardID] = steal.Thief
+	g.stolen++
+	if err := g.Save(); err != nil {
+		g.state[cardID] = owner
+		g.stolen--
+		return Steal{}, fmt.Errorf("could not save game state: %w", err)
+	}
+	return steal, nil
+}
+
+// Steals counts the steals this player has recorded.
+func (g *Game) Steals() int {
+	return g.stolen
+}
+
+// VerifySteal checks that the victim's reveal matches the offer the thief picked from, returning the steal. It doesn't
+// know who holds what, so check that the revealed hand is the victim's.
+func VerifySteal(gameID, pick, reveal string) (Steal, error) {
+	offer, index, err := parseStealPick(pick)
+	if err != nil {
+		return Steal{}, err
+	}
+	number, victim, thief, size, hash, _ := parseStealOffer(offer)
+
+	n, revealer, nonce, hand, err := parseStealReveal(reveal)
+	if err != nil {
+		return Steal{}, err
+	}
+	if n != number || revealer != victim || len(hand) != size {
+		return Steal{}, fmt.Errorf("the reveal isn't for this steal")
+	}
+	if !bytes.Equal(stealHash(gameID, number, victim, thief, hand, nonce), hash) {
+		return Steal{}, fmt.Errorf("player %d's reveal doesn't match the hand they offered", victim)
+	}
+	return Steal{Number: number, Victim: victim, Thief: thief, Hand: hand, Index: index}, nil
+}
+
+// checkSteal verifies a steal, and checks it's the next one, of the victim's hand according to this player's state.
+func (g *Game) checkSteal(pick, reveal string) (Steal, error) {
+	steal, err := VerifySteal(g.id, pick, reveal)
+	if err != nil {
+		return Steal{}, err
+	}
+	if steal.Number != g.stolen+1 {
+		return Steal{}, fmt.Errorf("this is steal %d, but steal %d is next", steal.Number, g.stolen+1)
+	}
+	if err := g.checkThief(steal.Thief, steal.Victim); err != nil {
+		return Steal{}, err
+	}
+	if err := g.checkHand(steal.Victim, steal.Hand); err != nil {
+		return Steal{}, err
+	}
+	return steal, nil
+}
+
+// checkThief checks that both players are still playing in this game, and aren't the same player.
+func (g *Game) checkThief(thief, victim PlayerNumber) error {
+	for _, player := range []PlayerNumber{thief, victim} {
+		if player < 1 || player > PlayerNumber(g.Players) || g.roster[player-1].Role != RolePlayer {
+			return fmt.Errorf("player %d is not playing in this game", player)
+		}
+		if _, departed := g.recovered[player]; departed {
+			return fmt.Errorf("%s has left the game", g.PlayerName(player))
+		}
+	}
+	if thief == victim {
+		return fmt.Errorf("players can't steal from themselves")
+	}
+	return nil
+}
+
+// checkHand checks that the given cards are every card this player's state records the victim as holding. Cards that
+// had left the deck before this player was seated may be amongst them too.
+func (g *Game) checkHand(victim PlayerNumber, hand []int) error {
+	seen := make(map[int]bool)
+	for _, cardID := range hand {
+		if cardID >= len(g.state) || seen[cardID] || (g.state[cardID] != victim && g.state[cardID] != notInDeck) {
+			return fmt.Errorf("the hand offered isn't the cards %s holds", g.PlayerName(victim))
+		}
+		seen[cardID] = true
+	}
+	for cardID, owner := range g.state {
+		if owner == victim && !seen[cardID] {
+			return fmt.Errorf("the hand offered isn't the cards %s holds", g.PlayerName(victim))
+		}
+	}
+	return nil
+}
+
+func (g *Game) stealOffer(offer *pendingOffer) string {
+	header := stealHeader(g.stolen+1, g.playerNumber, offer.thief, len(offer.hand))
+	hash := stealHash(g.id, g.stolen+1, g.playerNumber, offer.thief, offer.hand, offer.nonce)
+	return base64.RawStdEncoding.EncodeToString(append(header, hash...))
+}
+
+func (g *Game) stealReveal(offer *pendingOffer) string {
+	reveal := append(stealHeader(g.stolen+1, g.playerNumber, 0, 0)[:5], offer.nonce...)
+	for _, cardID := range offer.hand {
+		reveal = binary.LittleEndian.AppendUint16(reveal, uint16(cardID))
+	}
+	return base64.RawStdEncoding.EncodeToString(reveal)
+}
+
+// stealHeader encodes a steal's number, the victim and thief, and the number of cards in the victim's hand.
+func stealHeader(number int, victim, thief PlayerNumber, size int) []byte {
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], uint32(number))
+	header[4] = byte(victim)
+	header[5] = byte(thief)
+	binary.LittleEndian.PutUint16(header[6:8], uint16(size))
+	return header
+}
+
+// stealHash is what a victim commits to: the hash of their hand's order, bound to the game and the steal.
+func stealHash(gameID string, number int, victim, thief PlayerNumber, hand []int, nonce []byte) []byte {
+	hash := sha256.New()
+	hash.Write([]byte("TrustDraw steal commitment\x00" + gameID + "\x00"))
+	hash.Write(stealHeader(number, victim, thief, len(hand)))
+	hash.Write(nonce)
+	for _, cardID := range hand {
+		hash.Write(binary.LittleEndian.AppendUint16(nil, uint16(cardID)))
+	}
+	return hash.Sum(nil)
+}
+
+func parseStealOffer(offer string) (number int, victim, thief PlayerNumber, size int, hash []byte, err error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(offer)
+	if err != nil || len(decoded) != stealOfferSize {
+		return 0, 0, 0, 0, nil, fmt.Errorf("invalid steal offer")
+	}
+	size = int(binary.LittleEndian.Uint16(decoded[6:8]))
+	if size < 1 || size > maxCards {
+		return 0, 0, 0, 0, nil, fmt.Errorf("invalid steal offer")
+	}
+	return int(binary.LittleEndian.Uint32(decoded[0:4])), PlayerNumber(decoded[4]), PlayerNumber(decoded[5]), size, decoded[8:], nil
+}
+
+// parseStealPick splits a pick into the offer it was made from, and the position picked.
+func parseStealPick(pick string) (offer string, index int, err error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(pick)
+	if err != nil || len(decoded) != stealPickSize {
+		return "", 0, fmt.Errorf("invalid steal pick")
+	}
+	offer = base64.RawStdEncoding.EncodeToString(decoded[:stealOfferSize])
+	_, _, _, size, _, err := parseStealOffer(offer)
+	index = int(binary.LittleEndian.Uint16(decoded[stealOfferSize:]))
+	if err != nil || index < 1 || index > size {
+		return "", 0, fmt.Errorf("invalid steal pick")
+	}
+	return offer, index, nil
+}
+
+func parseStealReveal(reveal string) (number int, victim PlayerNumber, nonce []byte, hand []int, err error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(reveal)
+	if err != nil || len(decoded) <= stealRevealHeaderSize || (len(decoded)-stealRevealHeaderSize)%2 != 0 {
+		return 0, 0, nil, nil, fmt.Errorf("invalid steal reveal")
+	}
+	for i := stealRevealHeaderSize; i < len(decoded); i += 2 {
+		hand = append(hand, int(binary.LittleEndian.Uint16(decoded[i:i+2])))
+	}
+	return int(binary.LittleEndian.Uint32(decoded[0:4])), PlayerNumber(decoded[4]), decoded[5:stealRevealHeaderSize], hand, nil
+}
+
+// stealStateLines encodes the number of steals recorded, and this player's offer and pick in progress, as state lines.
+func (g *Game) stealStateLines() []string {
+	var lines []string
+	if g.stolen > 0 {
+		lines = append(lines, fmt.Sprintf("stolen %d", g.stolen))
+	}
+	if offer := g.offered; offer != nil {
+		lines = append(lines, fmt.Sprintf("offered %d %s %s", offer.thief,
+			base64.RawStdEncoding.EncodeToString(offer.nonce), joinCardIDs(offer.hand)))
+	}
+	if g.picked != "" {
+		lines = append(lines, "picked "+g.picked)
+	}
+	return lines
+}
+
+// loadStealState reads a "stolen", "offered" or "picked" state line.
+func (g *Game) loadStealState(fields []string) error {
+	switch {
+	case fields[0] == "stolen" && len(fields) == 2:
+		stolen, err := strconv.Atoi(fields[1])
+		if err != nil || stolen < 0 {
+			return fmt.Errorf("state holds an invalid number of steals")
+		}
+		g.stolen = stolen
+	case fields[0] == "offered" && len(fields) == 4:
+		thief, err := strconv.Atoi(fields[1])
+		nonce, nonceErr := base64.RawStdEncoding.DecodeString(fields[2])
+		if err != nil || nonceErr != nil || len(nonce) != stealNonceSize {
+			return fmt.Errorf("state holds an invalid steal offer")
+		}
+		hand, err := splitCardIDs(fields[3], len(g.cards))
+		if err != nil {
+			return fmt.Errorf("state holds an invalid steal offer")
+		}
+		g.offered = &pendingOffer{thief: PlayerNumber(thief), nonce: nonce, hand: hand}
+	case fields[0] == "picked" && len(fields) == 2:
+		if _, _, err := parseStealPick(fields[1]); err != nil {
+			return fmt.Errorf("state holds an invalid steal pick")
+		}
+		g.picked = fields[1]
+	default:
+		return fmt.Errorf("state holds an invalid steal")
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package trustdraw
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+)
+
+// testSteal has the thief steal a card at random from the victim, with every other player recording it.
+func testSteal(t *testing.T, games []*Game, victim, thief PlayerNumber) (pick, reveal, card string) {
+	t.Helper()
+	offer, err := games[victim-1].OfferHand(thief)
+	if err != nil {
+		t.Fatalf("could not offer a hand: %v", err)
+	}
+	if pick, err = games[thief-1].PickCard(offer, 0); err != nil {
+		t.Fatalf("could not pick a card: %v", err)
+	}
+	reveal, allowKeys, err := games[victim-1].HandOver(pick)
+	if err != nil {
+		t.Fatalf("could not hand over the card: %v", err)
+	}
+	if card, _, err = games[thief-1].TakeCard(reveal, allowKeys...); err != nil {
+		t.Fatalf("could not take the card: %v", err)
+	}
+	for _, game := range games {
+		if p := game.PlayerNumber(); p != victim && p != thief {
+			if _, err := game.RecordSteal(pick, reveal); err != nil {
+				t.Fatalf("player %d could not record the steal: %v", p, err)
+			}
+		}
+	}
+	return pick, reveal, card
+}
+
+func TestSteal(t *testing.T) {
+	tests := []struct {
+		name   string
+		held   int
+		victim PlayerNumber
+		thief  PlayerNumber
+	}{
+		{"from a hand of one", 1, 1, 2},
+		{"from a hand of three", 3, 1, 2},
+		{"by the player after", 2, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, _ := testGames(t, 3, DealOptions{})
+			hand := make(map[string]bool)
+			for i := 0; i < tt.held; i++ {
+				card, _, _ := testDraw(t, games, tt.victim)
+				hand[card] = true
+			}
+
+			pick, reveal, card := testSteal(t, games, tt.victim, tt.thief)
+			if !hand[card] {
+				t.Fatalf("stole %s, which the victim didn't hold", card)
+			}
+			steal, err := VerifySteal(games[0].ID(), pick, reveal)
+			if err != nil {
+				t.Fatalf("could not verify the steal: %v", err)
+			}
+			if steal.Number != 1 || steal.Victim != tt.victim || steal.Thief != tt.thief || len(steal.Hand) != tt.held {
+				t.Errorf("got steal %+v", steal)
+			}
+			for _, game := range games {
+				if owner := game.state[steal.CardID()]; owner != tt.thief {
+					t.Errorf("player %d records the card as player %d's, want the thief's", game.PlayerNumber(), owner)
+				}
+				if game.Steals() != 1 {
+					t.Errorf("player %d has recorded %d steals, want 1", game.PlayerNumber(), game.Steals())
+				}
+			}
+
+			thiefHand, err := games[tt.thief-1].Hand()
+			if err != nil || len(thiefHand) != 1 || thiefHand[0].Card != card {
+				t.Errorf("the thief holds %v (%v), want %s", thiefHand, err, card)
+			}
+			victimHand, err := games[tt.victim-1].Hand()
+			if err != nil || len(victimHand) != tt.held-1 {
+				t.Errorf("the victim holds %d cards (%v), want %d", len(victimHand), err, tt.held-1)
+			}
+		})
+	}
+}
+
+func TestStealTampering(t *testing.T) {
+	games, _ := testGames(t, 3, DealOptions{})
+	for i := 0; i < 3; i++ {
+		testDraw(t, games, 1)
+	}
+	testDraw(t, games, 3)
+	offer, err := games[0].OfferHand(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pick, err := games[1].PickCard(offer, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reveal := games[0].stealReveal(games[0].offered)
+	otherGames, _ := testGames(t, 3, DealOptions{})
+
+	decoded, _ := base64.RawStdEncoding.DecodeString(reveal)
+	tamper := func(change func(reveal []byte)) string {
+		changed := append([]byte(nil), decoded...)
+		change(changed)
+		return base64.RawStdEncoding.EncodeToString(changed)
+	}
+	// Swapping the first two cards would give the thief a different one, if the victim could change their order.
+	swapped := tamper(func(r []byte) {
+		first := binary.LittleEndian.Uint16(r[stealRevealHeaderSize:])
+		copy(r[stealRevealHeaderSize:], r[stealRevealHeaderSize+2:stealRevealHeaderSize+4])
+		binary.LittleEndian.PutUint16(r[stealRevealHeaderSize+2:], first)
+	})
+	// Swapping in player 3's card would give the thief a card the victim doesn't hold.
+	player3Hand, err := games[2].Hand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notHeld := tamper(func(r []byte) {
+		binary.LittleEndian.PutUint16(r[stealRevealHeaderSize:], uint16(player3Hand[0].CardID))
+	})
+
+	tests := []struct {
+		name   string
+		gameID string
+		reveal string
+	}{
+		{"reordered hand", games[0].ID(), swapped},
+		{"a card the victim doesn't hold", games[0].ID(), notHeld},
+		{"changed nonce", games[0].ID(), tamper(func(r []byte) { r[5] ^= 1 })},
+		{"reveal by another player", games[0].ID(), tamper(func(r []byte) { r[4] = 3 })},
+		{"reveal for another steal", games[0].ID(), tamper(func(r []byte) { r[0] = 2 })},
+		{"short hand", games[0].ID(), base64.RawStdEncoding.EncodeToString(decoded[:len(decoded)-2])},
+		{"another game", otherGames[0].ID(), reveal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if steal, err := VerifySteal(tt.gameID, pick, tt.reveal); err == nil {
+				t.Errorf("a tampered steal was verified, taking card %d", steal.CardID()+1)
+			}
+			if tt.gameID == games[0].ID() {
+				if _, err := games[2].RecordSteal(pick, tt.reveal); err == nil {
+					t.Errorf("a tampered steal was recorded")
+				}
+				if _, _, err := games[1].TakeCard(tt.reveal, "AAAA"); err == nil {
+					t.Errorf("a tampered steal was taken")
+				}
+			}
+		})
+	}
+
+	// Once the steal is made, it can't be recorded again.
+	reveal, allowKeys, err := games[0].HandOver(pick)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := games[1].TakeCard(reveal, allowKeys...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := games[2].RecordSteal(pick, reveal); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := games[2].RecordSteal(pick, reveal); err == nil {
+		t.Errorf("the same steal was recorded twice")
+	}
+}
@@ -0,0 +1,66 @@
+package trustdraw
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrStateChanged is returned when a game's stored state was changed by something else (like another terminal)
+// since it was loaded, so saving would have overwritten it.
+var ErrStateChanged = errors.New("game state was changed elsewhere")
+
+// StateStore holds the state of one player's game between sessions.
+// Implementations for files, memory and SQLite are in the statestore package.
+type StateStore interface {
+	// Load returns the stored state, or an empty string if none has been stored yet.
+	Load() (string, error)
+	// Save stores the state, replacing whatever was stored before.
+	Save(state string) error
+	// CompareAndSwap stores the new state only if the stored state is still old, returning ErrStateChanged if not.
+	CompareAndSwap(old, new string) error
+}
+
+// OpenGameWithStore opens a deal file as OpenGame does, loading the state from the given store.
+// The game saves its state back to the store after every action that changes it; if the stored state was changed
+// by something else in the meantime, the action is undone and ErrStateChanged is returned.
+func OpenGameWithStore(dealFile io.Reader, playerPrv crypto.Signer, store StateStore) (*Game, error) {
+	state, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("could not load game state: %w", err)
+	}
+
+	game, err := OpenGame(dealFile, playerPrv, state)
+	if err != nil {
+		return nil, err
+	}
+
+	game.store = store
+	game.stored = state
+	return game, nil
+}
+
+// Save stores the current state of the game, if it was opened with a store.
+// This is only needed after calling LoadState, as every other change is saved automatically.
+func (g *Game) Save() error {
+	if g.store == nil {
+		return nil
+	}
+
+	state := g.State()
+	if sameState(state, g.stored) {
+		return nil
+	}
+	if err := g.store.CompareAndSwap(g.stored, state); err != nil {
+		return err
+	}
+	g.stored = state
+	return nil
+}
+
+// sameState compares two states, ignoring surrounding whitespace (which a person editing a state file might add).
+func sameState(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
@@ -1,14 +1,17 @@
 package trustdraw
 
 import (
-	"crypto/ed25519"
+	"crypto"
 	"encoding/base64"
 	"fmt"
 	"io"
 	"strings"
 )
 
-func VerifyDeal(dealFile io.Reader, dealerPub ed25519.PublicKey) (int, int, error) {
+// VerifyDeal checks that a deal file was made by the holder of the dealer's (RSA or Ed25519) key, returning the number
+// of cards and players it holds. Observers aren't counted as players, but players seated after the deal are, as long
+// as the dealer, or every other player, seated them.
+func VerifyDeal(dealFile io.Reader, dealerPub crypto.PublicKey) (int, int, error) {
 	stanzas, err := extractStanzas(dealFile)
 	if err != nil {
 		return 0, 0, err
@@ -19,27 +22,41 @@ func VerifyDeal(dealFile io.Reader, dealerPub ed25519.PublicKey) (int, int, erro
 		return 0, 0, err
 	}
 
-	players, err := verifyPlayers(stanzas[2])
+	if _, err := verifyPlayers(stanzas[2]); err != nil {
+		return cards, 0, err
+	}
+	header, seats, err := readSeats(stanzas)
 	if err != nil {
 		return cards, 0, err
 	}
+	players := header.players
+	if _, err := layoutPiles(header.piles, cards); err != nil {
+		return cards, players, err
+	}
 
 	if err := verifySignature(stanzas, dealerPub); err != nil {
 		return cards, players, err
 	}
+	if err := verifySeats(seats, dealerPub); err != nil {
+		return cards, players, err
+	}
 
 	return cards, players, nil
 }
 
-func verifyVersion(version string) (string, error) {
+// verifyVersion checks the version line at the start of a deal file's header is one this code can read.
+func verifyVersion(header string) (string, error) {
+	version, _, _ := strings.Cut(header, "\n")
 	parts := strings.Split(version, "/")
-	if parts[0] != "TrustDraw" {
+	if parts[0] != "TrustDraw" || len(parts) != 2 {
 		return "", fmt.Errorf("not a deal file")
 	}
-	if parts[1] != "v1.0" {
+	switch parts[1] {
+	case "v1.0", "v1.1":
+		return parts[1], nil
+	default:
 		return "", fmt.Errorf("unknown deal file version: %s", version)
 	}
-	return parts[1], nil
 }
 
 func verifyCards(cards string) (int, error) {
@@ -72,14 +89,14 @@ func verifyPlayers(playerBlock string) (int, error) {
 	return len(players), nil
 }
 
-func verifySignature(stanzas []string, dealerPub ed25519.PublicKey) error {
+func verifySignature(stanzas []string, dealerPub crypto.PublicKey) error {
 	data := strings.Join(stanzas[0:3], "\n\n") + "\n"
 	sig, err := base64.RawStdEncoding.DecodeString(stanzas[3])
 	if err != nil {
 		return fmt.Errorf("deal file signature is badly formed")
 	}
 
-	if !ed25519.Verify(dealerPub, []byte(data), sig) {
+	if err := verifyDeal(dealerPub, []byte(data), sig); err != nil {
 		return fmt.Errorf("deck was not shuffled by the specified dealer")
 	}
 	return nil